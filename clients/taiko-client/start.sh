@@ -69,6 +69,7 @@
 
 # Prover
 #  - HIVE_TAIKO_PROVER_PRIVATE_KEY                   private key of the prover
+#  - HIVE_TAIKO_PROVER_RANDOM_DUMMY_PROOF_DELAY      random delay of dummy proofs, e.g. 1s-10s
 
 # Deployer
 # - HIVE_TAIKO_L1_DEPLOYER_ADDRESS
@@ -102,6 +103,9 @@ case $HIVE_TAIKO_ROLE in
   FLAGS="$FLAGS --zkevmRpcdParamsPath=12345"
   FLAGS="$FLAGS --l1.proverPrivKey=$HIVE_TAIKO_PROVER_PRIVATE_KEY"
   FLAGS="$FLAGS --dummy"
+  if [ "$HIVE_TAIKO_PROVER_RANDOM_DUMMY_PROOF_DELAY" != "" ]; then
+    FLAGS="$FLAGS --randomDummyProofDelay=$HIVE_TAIKO_PROVER_RANDOM_DUMMY_PROOF_DELAY"
+  fi
   ;;
 "proposer")
   FLAGS="$FLAGS --l2.http $HIVE_TAIKO_L2_HTTP_ENDPOINT"
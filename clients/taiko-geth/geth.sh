@@ -8,6 +8,8 @@
 #  - `chain.rlp` file is located in the filesystem root (optional)
 #  - `blocks` folder is located in the filesystem root (optional)
 #  - `keys` folder is located in the filesystem root (optional)
+#  - `datadir.tar.gz` archive of a data directory in the filesystem root (optional),
+#    as created by /hive-bin/snapshot.sh
 #
 # This script assumes the following environment variables:
 #
@@ -108,6 +110,20 @@ if [ -z "$HIVE_NODETYPE" ]; then
     FLAGS="$FLAGS --syncmode snap"
 fi
 
+# Restore the data directory of a snapshot if present
+if [ -f /datadir.tar.gz ]; then
+    echo "Restoring data directory..."
+    tar -xzf /datadir.tar.gz -C /root
+fi
+
+# Load the test chain if present. The chain must import completely.
+echo "Loading initial blockchain..."
+if [ -f /chain.rlp ]; then
+    $geth $FLAGS --taiko --gcmode=archive import /chain.rlp
+else
+    echo "Warning: chain.rlp not found."
+fi
+
 # Import clique signing key.
 if [ -n "$HIVE_CLIQUE_PRIVATEKEY" ]; then
     # Create password file.
@@ -161,4 +177,15 @@ FLAGS="$FLAGS --taiko --allow-insecure-unlock"
 # taiko part end
 
 echo "Running taiko-geth with flags $FLAGS"
+set +e
 $geth $FLAGS
+status=$?
+
+# Keep the container running while the data directory is copied out by
+# /hive-bin/snapshot.sh, which stops geth.
+if [ -f /snapshot.lock ]; then
+    while true; do
+        sleep 1
+    done
+fi
+exit $status
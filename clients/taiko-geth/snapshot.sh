@@ -0,0 +1,21 @@
+#!/bin/bash
+
+# Script to snapshot the data directory
+#
+# This is copied into the taiko-geth container by Hive and used by the upgrade tests.
+# It stops geth gracefully, so that all state is flushed to disk, and prints a
+# base64 encoded gzipped tar archive of the data directory. The container keeps
+# running until it is stopped by the simulator.
+#
+
+# Immediately abort the script on any error encountered
+set -e
+
+touch /snapshot.lock
+pkill -INT -x geth
+while pgrep -x geth >/dev/null; do
+    sleep 0.1
+done
+
+# geth runs with the default data directory.
+tar -czf - -C /root .ethereum | base64
@@ -0,0 +1,20 @@
+#!/bin/bash
+
+# Script to retrieve the result of the protocol deployment.
+#
+# Without arguments, the whole deployment is printed as JSON. Otherwise the argument is
+# a jq filter, e.g. '.contracts.TaikoL1'. The script exits with code 1 while the
+# deployment is running, and with code 2 if it has failed.
+
+if [ -f /deploy_failed ]; then
+  cat /deploy_failed
+  exit 2
+fi
+if [ ! -f /deploy_result.json ]; then
+  exit 1
+fi
+if [ "$1" = "" ]; then
+  cat /deploy_result.json
+else
+  jq -r "$1" /deploy_result.json
+fi
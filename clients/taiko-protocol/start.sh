@@ -0,0 +1,73 @@
+#!/bin/bash
+
+# Startup script of the taiko protocol deployer. It compiles the L1 contracts with the
+# given protocol parameters and deploys them to an L1 node. The result can be retrieved
+# using deploy_result.sh.
+#
+# Taiko environment variables
+#
+#  - HIVE_TAIKO_MAINNET_URL                          http endpoint of the l1 node
+#  - HIVE_TAIKO_PRIVATE_KEY                          private key of the deployer, funded on l1
+#  - HIVE_TAIKO_L1_DEPLOYER_ADDRESS                  address of the dao and team vaults
+#  - HIVE_TAIKO_L2_CHAIN_ID                          l2 chain id
+#  - HIVE_TAIKO_L2_GENESIS_BLOCK_HASH                hash of the l2 genesis block
+#  - HIVE_TAIKO_L2_ROLLUP_ADDRESS                    address of the TaikoL2 contract
+#  - HIVE_TAIKO_PROTOCOL_PARAMS                      comma separated overrides of LibSharedConfig,
+#                                                    e.g. "blockMaxGasLimit=6000000,commitConfirmations=1"
+
+cd /protocol
+
+function set_params() {
+  local config=contracts/libs/LibSharedConfig.sol
+  IFS=',' read -ra params <<<"$HIVE_TAIKO_PROTOCOL_PARAMS"
+  for param in "${params[@]}"; do
+    local key="${param%%=*}"
+    local value="${param#*=}"
+    if ! grep -Eq "^\s*${key}:" "$config"; then
+      echo "unknown protocol parameter ${key}"
+      return 1
+    fi
+    # The value is passed through the environment, so it may contain any character
+    # but the comma separating the parameters.
+    KEY="$key" VALUE="$value" awk '
+      match($0, "^[ \t]*" ENVIRON["KEY"] ":") {
+        rest = substr($0, RLENGTH + 1)
+        comma = index(rest, ",")
+        if (comma > 0) {
+          $0 = substr($0, 1, RLENGTH) " " ENVIRON["VALUE"] substr(rest, comma)
+        }
+      }
+      { print }' "$config" >"$config.new"
+    mv "$config.new" "$config"
+  done
+}
+
+function deploy() {
+  set -e
+  set_params
+  K_CHAIN_ID="$HIVE_TAIKO_L2_CHAIN_ID" pnpm compile
+
+  export MAINNET_URL="$HIVE_TAIKO_MAINNET_URL"
+  export PRIVATE_KEY="$HIVE_TAIKO_PRIVATE_KEY"
+  FLAGS="--network l1_test"
+  FLAGS="$FLAGS --dao-vault $HIVE_TAIKO_L1_DEPLOYER_ADDRESS"
+  FLAGS="$FLAGS --team-vault $HIVE_TAIKO_L1_DEPLOYER_ADDRESS"
+  FLAGS="$FLAGS --l2-genesis-block-hash $HIVE_TAIKO_L2_GENESIS_BLOCK_HASH"
+  FLAGS="$FLAGS --l2-chain-id $HIVE_TAIKO_L2_CHAIN_ID"
+  FLAGS="$FLAGS --taiko-l2 $HIVE_TAIKO_L2_ROLLUP_ADDRESS"
+  FLAGS="$FLAGS --confirmations 1"
+  echo "Deploying L1 contracts with flags $FLAGS"
+  npx hardhat deploy_L1 $FLAGS
+  cp deployments/l1_test_L1.json /deploy_result.json
+}
+
+if (deploy) >/deploy.log 2>&1; then
+  echo "Deployed L1 contracts"
+else
+  tail -n 50 /deploy.log >/deploy_failed
+  echo "Failed to deploy L1 contracts"
+fi
+cat /deploy.log
+
+# Keep the container running, so the deploy result can be retrieved.
+tail -f /dev/null
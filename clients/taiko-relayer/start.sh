@@ -0,0 +1,59 @@
+#!/bin/bash
+
+# Startup script of the taiko bridge relayer.
+#
+# Taiko environment variables
+#
+#  - HIVE_TAIKO_L1_WS_ENDPOINT                       ws endpoint of the l1 node
+#  - HIVE_TAIKO_L2_WS_ENDPOINT                       ws endpoint of the l2 node
+#  - HIVE_TAIKO_L1_ROLLUP_ADDRESS                    rollup address of the l1 node
+#  - HIVE_TAIKO_L2_ROLLUP_ADDRESS                    rollup address of the l2 node
+#  - HIVE_TAIKO_L1_BRIDGE_ADDRESS                    bridge address of the l1 node
+#  - HIVE_TAIKO_L2_BRIDGE_ADDRESS                    bridge address of the l2 node
+#  - HIVE_TAIKO_L1_VAULT_ADDRESS                     token vault address of the l1 node
+#  - HIVE_TAIKO_L2_VAULT_ADDRESS                     token vault address of the l2 node
+#  - HIVE_TAIKO_RELAYER_PRIVATE_KEY                  private key of the relayer, funded on l1 and l2
+#  - HIVE_TAIKO_RELAYER_CONFIRMATIONS                blocks to wait before processing a message
+
+set -e
+
+# Start the database.
+mysql_install_db --user=mysql --datadir=/var/lib/mysql >/dev/null
+mysqld_safe --user=mysql --datadir=/var/lib/mysql &
+for i in $(seq 1 30); do
+  if mysqladmin ping --silent; then
+    break
+  fi
+  sleep 1
+done
+mysql -uroot -e "CREATE DATABASE IF NOT EXISTS relayer; ALTER USER 'root'@'localhost' IDENTIFIED BY 'root'; FLUSH PRIVILEGES;"
+if [ -d /migrations ] && command -v goose >/dev/null; then
+  goose -dir /migrations mysql "root:root@tcp(localhost:3306)/relayer?parseTime=true" up
+fi
+
+export MYSQL_USER=root
+export MYSQL_PASSWORD=root
+export MYSQL_DATABASE=relayer
+export MYSQL_HOST=localhost:3306
+export MYSQL_MAX_IDLE_CONNS=50
+export MYSQL_MAX_OPEN_CONNS=200
+export MYSQL_CONN_MAX_LIFETIME_IN_MS=100000
+
+export RELAYER_ECDSA_KEY=$HIVE_TAIKO_RELAYER_PRIVATE_KEY
+export L1_RPC_URL=$HIVE_TAIKO_L1_WS_ENDPOINT
+export L2_RPC_URL=$HIVE_TAIKO_L2_WS_ENDPOINT
+export L1_TAIKO_ADDRESS=$HIVE_TAIKO_L1_ROLLUP_ADDRESS
+export L2_TAIKO_ADDRESS=$HIVE_TAIKO_L2_ROLLUP_ADDRESS
+export L1_BRIDGE_ADDRESS=$HIVE_TAIKO_L1_BRIDGE_ADDRESS
+export L2_BRIDGE_ADDRESS=$HIVE_TAIKO_L2_BRIDGE_ADDRESS
+export L1_TOKEN_VAULT_ADDRESS=$HIVE_TAIKO_L1_VAULT_ADDRESS
+export L2_TOKEN_VAULT_ADDRESS=$HIVE_TAIKO_L2_VAULT_ADDRESS
+export CONFIRMATIONS_BEFORE_PROCESSING=${HIVE_TAIKO_RELAYER_CONFIRMATIONS:-1}
+export NUM_GOROUTINES=20
+export SUBSCRIPTION_BACKOFF_IN_SECONDS=1
+export HTTP_PORT=4102
+export PROMETHEUS_HTTP_PORT=6061
+export CORS_ORIGINS=*
+
+echo "Running relayer for L1 bridge $L1_BRIDGE_ADDRESS and L2 bridge $L2_BRIDGE_ADDRESS"
+relayer -layer both -mode sync -watch-mode filter-and-subscribe
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// fixtureConfig configures the 'export-fixtures' subcommand.
+type fixtureConfig struct {
+	interval    int // export block and balance fixtures every n blocks
+	maxReceipts int // maximum number of receipt fixtures
+	maxBalances int // maximum number of accounts in balance fixtures
+}
+
+// fixture is a single rpc-compat test case, with the expected result.
+type fixture struct {
+	method string
+	name   string
+	params []interface{}
+	result interface{}
+}
+
+// exportFixturesCommand writes rpc-compat test cases for a chain.
+func exportFixturesCommand(args []string) {
+	var (
+		cfg     fixtureConfig
+		genesis = flag.String("genesis", "", "The path and filename to the genesis.json of the chain")
+		outdir  = flag.String("output", "tests", "Test case destination folder")
+	)
+	flag.IntVar(&cfg.interval, "interval", 1, "Export block and balance tests for every n-th block")
+	flag.IntVar(&cfg.maxReceipts, "max-receipts", 20, "Maximum number of transaction receipt tests")
+	flag.IntVar(&cfg.maxBalances, "max-balances", 5, "Maximum number of accounts in balance tests")
+	flag.CommandLine.Parse(args)
+	if flag.NArg() != 1 || *genesis == "" {
+		fatalf("Usage: hivechain export-fixtures -genesis <genesis.json> [ options ] <chain.rlp>")
+	}
+	if cfg.interval < 1 {
+		fatalf("-interval must be at least 1")
+	}
+
+	gspec, err := loadGenesis(*genesis)
+	if err != nil {
+		fatal(err)
+	}
+	blocks, err := readChain(flag.Arg(0))
+	if err != nil {
+		fatal(err)
+	}
+	if err := cfg.exportFixtures(gspec, blocks, *outdir); err != nil {
+		fatal(err)
+	}
+	// Also place the chain next to the tests, the way the rpc-compat simulator expects.
+	if err := copyFile(*genesis, filepath.Join(*outdir, "genesis.json")); err != nil {
+		fatal(err)
+	}
+	if err := copyFile(flag.Arg(0), filepath.Join(*outdir, "chain.rlp")); err != nil {
+		fatal(err)
+	}
+}
+
+// exportFixtures imports the chain into an in-process go-ethereum blockchain and
+// computes the expected responses from it.
+func (cfg fixtureConfig) exportFixtures(gspec *core.Genesis, blocks []*types.Block, outdir string) error {
+	db := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(db)
+	// All states are kept, balances are queried at every exported height.
+	cacheConfig := &core.CacheConfig{
+		TrieCleanLimit:    256,
+		TrieDirtyDisabled: true,
+		TrieTimeLimit:     5 * time.Minute,
+		SnapshotLimit:     0,
+	}
+	chain, err := core.NewBlockChain(db, cacheConfig, gspec.Config, verifierEngine(gspec.Config, db), vm.Config{}, nil, nil)
+	if err != nil {
+		return fmt.Errorf("can't create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	if len(blocks) > 0 && blocks[0].NumberU64() == 0 {
+		blocks = blocks[1:]
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		return fmt.Errorf("chain import error: %v", err)
+	}
+
+	fixtures, err := cfg.makeFixtures(chain, gspec, blocks)
+	if err != nil {
+		return err
+	}
+	for _, f := range fixtures {
+		data, err := f.encode()
+		if err != nil {
+			return fmt.Errorf("%s/%s: %v", f.method, f.name, err)
+		}
+		dir := filepath.Join(outdir, f.method)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, f.name+".io"), data, 0644); err != nil {
+			return err
+		}
+	}
+	fmt.Println(len(fixtures), "tests written to", outdir)
+	return nil
+}
+
+// makeFixtures creates the test cases for the given chain.
+func (cfg fixtureConfig) makeFixtures(chain *core.BlockChain, gspec *core.Genesis, blocks []*types.Block) ([]fixture, error) {
+	var (
+		fixtures []fixture
+		receipts int
+		accounts = balanceAccounts(gspec, blocks, cfg.maxBalances)
+		config   = chain.Config()
+	)
+	for i, block := range blocks {
+		num := hexutil.EncodeBig(block.Number())
+		blockReceipts := chain.GetReceiptsByHash(block.Hash())
+
+		if i%cfg.interval == 0 || i == len(blocks)-1 {
+			fixtures = append(fixtures, fixture{
+				method: "eth_getBlockByNumber",
+				name:   fmt.Sprintf("get-block-n%d", block.NumberU64()),
+				params: []interface{}{num, true},
+				result: rpcMarshalBlock(block, chain.GetTd(block.Hash(), block.NumberU64()), config),
+			})
+			statedb, err := chain.StateAt(block.Root())
+			if err != nil {
+				return nil, fmt.Errorf("state of block %d: %v", block.NumberU64(), err)
+			}
+			for _, addr := range accounts {
+				fixtures = append(fixtures, fixture{
+					method: "eth_getBalance",
+					name:   fmt.Sprintf("get-balance-%x-n%d", addr, block.NumberU64()),
+					params: []interface{}{addr, num},
+					result: (*hexutil.Big)(statedb.GetBalance(addr)),
+				})
+			}
+		}
+		if block.Bloom() != (types.Bloom{}) {
+			logs := []*types.Log{}
+			for _, r := range blockReceipts {
+				logs = append(logs, r.Logs...)
+			}
+			fixtures = append(fixtures, fixture{
+				method: "eth_getLogs",
+				name:   fmt.Sprintf("logs-n%d", block.NumberU64()),
+				params: []interface{}{map[string]interface{}{"fromBlock": num, "toBlock": num}},
+				result: logs,
+			})
+		}
+		for j, tx := range block.Transactions() {
+			if receipts >= cfg.maxReceipts {
+				break
+			}
+			fixtures = append(fixtures, fixture{
+				method: "eth_getTransactionReceipt",
+				name:   fmt.Sprintf("get-receipt-n%d-i%d", block.NumberU64(), j),
+				params: []interface{}{tx.Hash()},
+				result: rpcMarshalReceipt(block, tx, uint64(j), blockReceipts[j], config),
+			})
+			receipts++
+		}
+	}
+	return fixtures, nil
+}
+
+// balanceAccounts selects the accounts used in eth_getBalance tests: the genesis
+// allocations, followed by the senders of transactions in the chain.
+func balanceAccounts(gspec *core.Genesis, blocks []*types.Block, max int) []common.Address {
+	var (
+		seen     = make(map[common.Address]bool)
+		accounts []common.Address
+	)
+	add := func(addr common.Address) {
+		if !seen[addr] && len(accounts) < max {
+			seen[addr] = true
+			accounts = append(accounts, addr)
+		}
+	}
+	var alloc []common.Address
+	for addr := range gspec.Alloc {
+		alloc = append(alloc, addr)
+	}
+	sort.Slice(alloc, func(i, j int) bool { return alloc[i].Hex() < alloc[j].Hex() })
+	for _, addr := range alloc {
+		add(addr)
+	}
+	for _, block := range blocks {
+		for _, tx := range block.Transactions() {
+			if sender, err := txSender(tx); err == nil {
+				add(sender)
+			}
+		}
+	}
+	return accounts
+}
+
+// encode returns the test case in rpc-compat format.
+func (f fixture) encode() ([]byte, error) {
+	req, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  f.method,
+		"params":  f.params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"result":  f.result,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var out strings.Builder
+	fmt.Fprintf(&out, "// generated by hivechain export-fixtures\n")
+	fmt.Fprintf(&out, ">> %s\n", req)
+	fmt.Fprintf(&out, "<< %s\n", resp)
+	return []byte(out.String()), nil
+}
+
+// The functions below produce the same JSON as the go-ethereum RPC API.
+
+func rpcMarshalBlock(block *types.Block, td *big.Int, config *params.ChainConfig) map[string]interface{} {
+	head := block.Header()
+	fields := map[string]interface{}{
+		"number":           (*hexutil.Big)(head.Number),
+		"hash":             block.Hash(),
+		"parentHash":       head.ParentHash,
+		"nonce":            head.Nonce,
+		"mixHash":          head.MixDigest,
+		"sha3Uncles":       head.UncleHash,
+		"logsBloom":        head.Bloom,
+		"stateRoot":        head.Root,
+		"miner":            head.Coinbase,
+		"difficulty":       (*hexutil.Big)(head.Difficulty),
+		"extraData":        hexutil.Bytes(head.Extra),
+		"size":             hexutil.Uint64(block.Size()),
+		"gasLimit":         hexutil.Uint64(head.GasLimit),
+		"gasUsed":          hexutil.Uint64(head.GasUsed),
+		"timestamp":        hexutil.Uint64(head.Time),
+		"transactionsRoot": head.TxHash,
+		"receiptsRoot":     head.ReceiptHash,
+		"totalDifficulty":  (*hexutil.Big)(td),
+	}
+	if head.BaseFee != nil {
+		fields["baseFeePerGas"] = (*hexutil.Big)(head.BaseFee)
+	}
+	txs := make([]interface{}, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		txs[i] = rpcMarshalTransaction(block, tx, uint64(i), config)
+	}
+	fields["transactions"] = txs
+	uncles := make([]common.Hash, len(block.Uncles()))
+	for i, uncle := range block.Uncles() {
+		uncles[i] = uncle.Hash()
+	}
+	fields["uncles"] = uncles
+	return fields
+}
+
+func rpcMarshalTransaction(block *types.Block, tx *types.Transaction, index uint64, config *params.ChainConfig) map[string]interface{} {
+	signer := types.MakeSigner(config, block.Number())
+	from, _ := types.Sender(signer, tx)
+	v, r, s := tx.RawSignatureValues()
+	fields := map[string]interface{}{
+		"blockHash":        block.Hash(),
+		"blockNumber":      (*hexutil.Big)(block.Number()),
+		"from":             from,
+		"gas":              hexutil.Uint64(tx.Gas()),
+		"gasPrice":         (*hexutil.Big)(tx.GasPrice()),
+		"hash":             tx.Hash(),
+		"input":            hexutil.Bytes(tx.Data()),
+		"nonce":            hexutil.Uint64(tx.Nonce()),
+		"to":               tx.To(),
+		"transactionIndex": hexutil.Uint64(index),
+		"value":            (*hexutil.Big)(tx.Value()),
+		"type":             hexutil.Uint64(tx.Type()),
+		"v":                (*hexutil.Big)(v),
+		"r":                (*hexutil.Big)(r),
+		"s":                (*hexutil.Big)(s),
+	}
+	switch tx.Type() {
+	case types.LegacyTxType:
+		if id := tx.ChainId(); id.Sign() != 0 {
+			fields["chainId"] = (*hexutil.Big)(id)
+		}
+	case types.AccessListTxType:
+		fields["accessList"] = tx.AccessList()
+		fields["chainId"] = (*hexutil.Big)(tx.ChainId())
+	case types.DynamicFeeTxType:
+		fields["accessList"] = tx.AccessList()
+		fields["chainId"] = (*hexutil.Big)(tx.ChainId())
+		fields["maxFeePerGas"] = (*hexutil.Big)(tx.GasFeeCap())
+		fields["maxPriorityFeePerGas"] = (*hexutil.Big)(tx.GasTipCap())
+		if baseFee := block.BaseFee(); baseFee != nil {
+			price := math.BigMin(new(big.Int).Add(tx.GasTipCap(), baseFee), tx.GasFeeCap())
+			fields["gasPrice"] = (*hexutil.Big)(price)
+		} else {
+			fields["gasPrice"] = (*hexutil.Big)(tx.GasFeeCap())
+		}
+	}
+	return fields
+}
+
+func rpcMarshalReceipt(block *types.Block, tx *types.Transaction, index uint64, receipt *types.Receipt, config *params.ChainConfig) map[string]interface{} {
+	signer := types.MakeSigner(config, block.Number())
+	from, _ := types.Sender(signer, tx)
+	fields := map[string]interface{}{
+		"blockHash":         block.Hash(),
+		"blockNumber":       hexutil.Uint64(block.NumberU64()),
+		"transactionHash":   tx.Hash(),
+		"transactionIndex":  hexutil.Uint64(index),
+		"from":              from,
+		"to":                tx.To(),
+		"gasUsed":           hexutil.Uint64(receipt.GasUsed),
+		"cumulativeGasUsed": hexutil.Uint64(receipt.CumulativeGasUsed),
+		"contractAddress":   nil,
+		"logs":              receipt.Logs,
+		"logsBloom":         receipt.Bloom,
+		"type":              hexutil.Uint(tx.Type()),
+	}
+	if !config.IsLondon(block.Number()) {
+		fields["effectiveGasPrice"] = (*hexutil.Big)(tx.GasPrice())
+	} else {
+		baseFee := block.BaseFee()
+		gasPrice := new(big.Int).Add(baseFee, tx.EffectiveGasTipValue(baseFee))
+		fields["effectiveGasPrice"] = (*hexutil.Big)(gasPrice)
+	}
+	if len(receipt.PostState) > 0 {
+		fields["root"] = hexutil.Bytes(receipt.PostState)
+	} else {
+		fields["status"] = hexutil.Uint(receipt.Status)
+	}
+	if receipt.Logs == nil {
+		fields["logs"] = []*types.Log{}
+	}
+	if receipt.ContractAddress != (common.Address{}) {
+		fields["contractAddress"] = receipt.ContractAddress
+	}
+	return fields
+}
+
+func copyFile(src, dst string) error {
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dst, data, 0644)
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// readChain decodes all blocks in a chain.rlp file.
+func readChain(file string) ([]*types.Block, error) {
+	input, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer input.Close()
+
+	var blocks []*types.Block
+	s := rlp.NewStream(bufio.NewReader(input), 0)
+	for i := 0; ; i++ {
+		block := new(types.Block)
+		if err := s.Decode(block); err == io.EOF {
+			return blocks, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: block %d: %v", file, i, err)
+		}
+		blocks = append(blocks, block)
+	}
+}
+
+// chainStats is the summary printed by the 'stats' subcommand.
+type chainStats struct {
+	Blocks          int            `json:"blocks"`
+	FirstBlock      uint64         `json:"firstBlock"`
+	LastBlock       uint64         `json:"lastBlock"`
+	Transactions    int            `json:"transactions"`
+	TxTypes         map[string]int `json:"txTypes"`
+	ContractCreates int            `json:"contractCreations"`
+	GasUsed         uint64         `json:"gasUsed"`
+	GasLimit        uint64         `json:"gasLimit"`
+	MaxBlockGasUsed uint64         `json:"maxBlockGasUsed"`
+	AvgBlockGasUsed uint64         `json:"avgBlockGasUsed"`
+	AccountsTouched int            `json:"accountsTouched"`
+	UnknownSenders  int            `json:"unknownSenders,omitempty"`
+	State           *stateStats    `json:"state,omitempty"`
+}
+
+// stateStats estimates the size of the post-state of a chain.
+type stateStats struct {
+	Accounts     int `json:"accounts"`
+	Contracts    int `json:"contracts"`
+	CodeBytes    int `json:"codeBytes"`
+	StorageSlots int `json:"storageSlots"`
+	// Estimated size of the state data, i.e. accounts, code and storage
+	// without trie overhead.
+	EstimatedBytes int `json:"estimatedBytes"`
+}
+
+var txTypeNames = map[uint8]string{
+	types.LegacyTxType:     "legacy",
+	types.AccessListTxType: "access-list",
+	types.DynamicFeeTxType: "dynamic-fee",
+}
+
+// statsCommand displays statistics about the blocks in a chain.rlp file.
+func statsCommand(args []string) {
+	var (
+		genesis = flag.String("genesis", "", "If set, the chain is imported on top of this genesis.json to compute post-state statistics")
+	)
+	flag.CommandLine.Parse(args)
+	if flag.NArg() != 1 {
+		fatalf("Usage: hivechain stats [ options ] <chain.rlp>")
+	}
+
+	blocks, err := readChain(flag.Arg(0))
+	if err != nil {
+		fatal(err)
+	}
+	stats := computeChainStats(blocks)
+	if *genesis != "" {
+		gspec, err := loadGenesis(*genesis)
+		if err != nil {
+			fatal(err)
+		}
+		if stats.State, err = computeStateStats(gspec, blocks); err != nil {
+			fatal(err)
+		}
+	}
+	js, _ := json.MarshalIndent(stats, "", "  ")
+	fmt.Println(string(js))
+}
+
+func computeChainStats(blocks []*types.Block) *chainStats {
+	stats := &chainStats{Blocks: len(blocks), TxTypes: make(map[string]int)}
+	if len(blocks) > 0 {
+		stats.FirstBlock = blocks[0].NumberU64()
+		stats.LastBlock = blocks[len(blocks)-1].NumberU64()
+	}
+	touched := make(map[common.Address]struct{})
+	for _, block := range blocks {
+		stats.GasUsed += block.GasUsed()
+		stats.GasLimit += block.GasLimit()
+		if block.GasUsed() > stats.MaxBlockGasUsed {
+			stats.MaxBlockGasUsed = block.GasUsed()
+		}
+		touched[block.Coinbase()] = struct{}{}
+		for _, tx := range block.Transactions() {
+			stats.Transactions++
+			name, ok := txTypeNames[tx.Type()]
+			if !ok {
+				name = fmt.Sprintf("type-%d", tx.Type())
+			}
+			stats.TxTypes[name]++
+
+			if sender, err := txSender(tx); err == nil {
+				touched[sender] = struct{}{}
+			} else {
+				stats.UnknownSenders++
+			}
+			if tx.To() != nil {
+				touched[*tx.To()] = struct{}{}
+			} else {
+				stats.ContractCreates++
+			}
+		}
+	}
+	if len(blocks) > 0 {
+		stats.AvgBlockGasUsed = stats.GasUsed / uint64(len(blocks))
+	}
+	stats.AccountsTouched = len(touched)
+	return stats
+}
+
+// txSender recovers the sender of tx without knowing the chain config.
+func txSender(tx *types.Transaction) (common.Address, error) {
+	var signer types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		signer = types.LatestSignerForChainID(tx.ChainId())
+	}
+	return types.Sender(signer, tx)
+}
+
+// computeStateStats imports blocks on top of the genesis and measures the resulting state.
+func computeStateStats(gspec *core.Genesis, blocks []*types.Block) (*stateStats, error) {
+	db := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(db)
+	// Preimages are required for dumping the state.
+	cacheConfig := &core.CacheConfig{
+		TrieCleanLimit: 256,
+		TrieDirtyLimit: 256,
+		TrieTimeLimit:  5 * time.Minute,
+		SnapshotLimit:  256,
+		Preimages:      true,
+	}
+	chain, err := core.NewBlockChain(db, cacheConfig, gspec.Config, verifierEngine(gspec.Config, db), vm.Config{}, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	var insert []*types.Block
+	for _, b := range blocks {
+		if b.NumberU64() > 0 {
+			insert = append(insert, b)
+		}
+	}
+	if _, err := chain.InsertChain(insert); err != nil {
+		return nil, fmt.Errorf("chain import error: %v", err)
+	}
+	statedb, err := chain.State()
+	if err != nil {
+		return nil, err
+	}
+	dump := statedb.RawDump(&state.DumpConfig{})
+
+	stats := new(stateStats)
+	for _, acc := range dump.Accounts {
+		stats.Accounts++
+		// address, nonce, balance, root and code hash
+		stats.EstimatedBytes += common.AddressLength + 8 + 3*common.HashLength
+		if len(acc.Code) > 0 {
+			stats.Contracts++
+			stats.CodeBytes += len(acc.Code)
+			stats.EstimatedBytes += len(acc.Code)
+		}
+		stats.StorageSlots += len(acc.Storage)
+		stats.EstimatedBytes += len(acc.Storage) * 2 * common.HashLength
+	}
+	return stats, nil
+}
+
+// verifierEngine returns a consensus engine for importing existing blocks. PoW seals
+// are not verified.
+func verifierEngine(config *params.ChainConfig, db ethdb.Database) consensus.Engine {
+	if config.Clique != nil {
+		return clique.New(config.Clique, db)
+	}
+	return beacon.New(ethash.NewFaker())
+}
+
+// diffCommand compares two chain.rlp files, reporting the first block that differs.
+func diffCommand(args []string) {
+	flag.CommandLine.Parse(args)
+	if flag.NArg() != 2 {
+		fatalf("Usage: hivechain diff <a.rlp> <b.rlp>")
+	}
+	a, err := readChain(flag.Arg(0))
+	if err != nil {
+		fatal(err)
+	}
+	b, err := readChain(flag.Arg(1))
+	if err != nil {
+		fatal(err)
+	}
+
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i].Hash() == b[i].Hash() {
+			continue
+		}
+		fmt.Printf("blocks differ at index %d:\n", i)
+		fmt.Printf("  a: number %d, %x\n", a[i].NumberU64(), a[i].Hash())
+		fmt.Printf("  b: number %d, %x\n", b[i].NumberU64(), b[i].Hash())
+		for _, d := range diffBlocks(a[i], b[i]) {
+			fmt.Println("  " + d)
+		}
+		os.Exit(1)
+	}
+	if len(a) != len(b) {
+		if n := min(len(a), len(b)); n == 0 {
+			fmt.Printf("chains have different length: a has %d blocks, b has %d blocks\n", len(a), len(b))
+		} else {
+			fmt.Printf("chains are equal up to index %d, but have different length: a has %d blocks, b has %d blocks\n",
+				n-1, len(a), len(b))
+		}
+		os.Exit(1)
+	}
+	fmt.Printf("chains are equal (%d blocks)\n", len(a))
+}
+
+// diffBlocks returns a description of all header and body differences of a and b.
+func diffBlocks(a, b *types.Block) []string {
+	diffs := diffHeaders(a.Header(), b.Header())
+
+	atxs, btxs := a.Transactions(), b.Transactions()
+	if len(atxs) != len(btxs) {
+		diffs = append(diffs, fmt.Sprintf("body: tx count %d != %d", len(atxs), len(btxs)))
+	}
+	for i := 0; i < len(atxs) && i < len(btxs); i++ {
+		if atxs[i].Hash() != btxs[i].Hash() {
+			diffs = append(diffs, fmt.Sprintf("body: tx %d: %x != %x", i, atxs[i].Hash(), btxs[i].Hash()))
+			ajs, _ := json.Marshal(atxs[i])
+			bjs, _ := json.Marshal(btxs[i])
+			diffs = append(diffs, diffJSON(fmt.Sprintf("body: tx %d", i), ajs, bjs)...)
+		}
+	}
+	auncles, buncles := a.Uncles(), b.Uncles()
+	if len(auncles) != len(buncles) {
+		diffs = append(diffs, fmt.Sprintf("body: uncle count %d != %d", len(auncles), len(buncles)))
+	}
+	for i := 0; i < len(auncles) && i < len(buncles); i++ {
+		if auncles[i].Hash() != buncles[i].Hash() {
+			diffs = append(diffs, fmt.Sprintf("body: uncle %d: %x != %x", i, auncles[i].Hash(), buncles[i].Hash()))
+		}
+	}
+	return diffs
+}
+
+func diffHeaders(a, b *types.Header) []string {
+	ajs, _ := json.Marshal(a)
+	bjs, _ := json.Marshal(b)
+	return diffJSON("header", ajs, bjs)
+}
+
+// diffJSON compares the top-level fields of two JSON objects.
+func diffJSON(prefix string, a, b []byte) []string {
+	var am, bm map[string]interface{}
+	json.Unmarshal(a, &am)
+	json.Unmarshal(b, &bm)
+
+	keys := make(map[string]struct{})
+	for k := range am {
+		keys[k] = struct{}{}
+	}
+	for k := range bm {
+		keys[k] = struct{}{}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var diffs []string
+	for _, k := range sorted {
+		if k == "hash" {
+			continue
+		}
+		av, bv := am[k], bm[k]
+		if !reflect.DeepEqual(av, bv) {
+			diffs = append(diffs, fmt.Sprintf("%s: %s: %v != %v", prefix, k, av, bv))
+		}
+	}
+	return diffs
+}
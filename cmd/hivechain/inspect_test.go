@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const simplechainDir = "../../simulators/ethereum/sync/simplechain/"
+
+// This test computes the stats of the sync simulator chain, and checks the state
+// stats against its post-state dump.
+func TestChainStats(t *testing.T) {
+	blocks, err := readChain(simplechainDir + "chain.rlp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := computeChainStats(blocks)
+	if stats.Blocks != 3000 || stats.FirstBlock != 1 || stats.LastBlock != 3000 {
+		t.Errorf("wrong range: %d blocks %d-%d", stats.Blocks, stats.FirstBlock, stats.LastBlock)
+	}
+	if stats.Transactions != 30 || stats.TxTypes["legacy"] != 30 || stats.ContractCreates != 22 || stats.UnknownSenders != 0 {
+		t.Errorf("wrong tx stats: %d txs, types %v, %d creations, %d unknown senders",
+			stats.Transactions, stats.TxTypes, stats.ContractCreates, stats.UnknownSenders)
+	}
+	if stats.GasUsed != 2165287 || stats.MaxBlockGasUsed != 114277 || stats.AvgBlockGasUsed != stats.GasUsed/3000 {
+		t.Errorf("wrong gas stats: used %d, max %d, avg %d", stats.GasUsed, stats.MaxBlockGasUsed, stats.AvgBlockGasUsed)
+	}
+
+	gspec, err := loadGenesis(simplechainDir + "genesis.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := computeStateStats(gspec, blocks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The dump has the older format, code without 0x prefix.
+	var dump struct {
+		Accounts map[common.Address]struct {
+			Code    string            `json:"code"`
+			Storage map[string]string `json:"storage"`
+		} `json:"accounts"`
+	}
+	if err := common.LoadJSON(simplechainDir+"chain_poststate.json", &dump); err != nil {
+		t.Fatal(err)
+	}
+	want := stateStats{Accounts: len(dump.Accounts)}
+	for _, acc := range dump.Accounts {
+		if len(acc.Code) > 0 {
+			want.Contracts++
+			want.CodeBytes += len(common.FromHex(acc.Code))
+		}
+		want.StorageSlots += len(acc.Storage)
+	}
+	want.EstimatedBytes = want.Accounts*(common.AddressLength+8+3*common.HashLength) + want.CodeBytes + want.StorageSlots*2*common.HashLength
+	if *st != want {
+		t.Errorf("wrong state stats %+v, want %+v", *st, want)
+	}
+}
+
+func TestDiffBlocks(t *testing.T) {
+	blocks, err := readChain(simplechainDir + "chain.rlp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var withTxs []*types.Block
+	for _, b := range blocks {
+		if len(b.Transactions()) > 0 {
+			withTxs = append(withTxs, b)
+		}
+	}
+	if len(withTxs) < 2 {
+		t.Fatalf("%d blocks with transactions, want two", len(withTxs))
+	}
+	block, other := withTxs[0], withTxs[1].Transactions()[0]
+	txs := block.Transactions()
+	header := block.Header()
+	header.Extra = []byte("modified")
+
+	tests := []struct {
+		name string
+		b    *types.Block
+		want []string
+	}{
+		{
+			name: "equal",
+			b:    block,
+		},
+		{
+			name: "header",
+			b:    block.WithSeal(header),
+			want: []string{fmt.Sprintf("header: extraData: %s != %s", hexutil.Encode(block.Extra()), hexutil.Encode(header.Extra))},
+		},
+		{
+			name: "missing tx",
+			b:    block.WithBody(txs[:len(txs)-1], nil),
+			want: []string{fmt.Sprintf("body: tx count %d != %d", len(txs), len(txs)-1)},
+		},
+		{
+			name: "other tx",
+			b:    block.WithBody(append(types.Transactions{other}, txs[1:]...), nil),
+			want: []string{fmt.Sprintf("body: tx 0: %x != %x", txs[0].Hash(), other.Hash())},
+		},
+	}
+	for _, test := range tests {
+		diffs := diffBlocks(block, test.b)
+		if test.want == nil {
+			if len(diffs) != 0 {
+				t.Errorf("%s: unexpected diffs %q", test.name, diffs)
+			}
+			continue
+		}
+		if len(diffs) < len(test.want) {
+			t.Errorf("%s: got diffs %q, want %q", test.name, diffs, test.want)
+			continue
+		}
+		for i, want := range test.want {
+			if !strings.HasPrefix(diffs[i], want) {
+				t.Errorf("%s: diff %d is %q, want %q", test.name, i, diffs[i], want)
+			}
+		}
+	}
+}
@@ -4,10 +4,30 @@
 //
 //	hivechain generate -length 10 -genesis ./genesis.json -blocktime 30 -output .
 //
+// The 'generate-taiko' subcommand creates a taiko L2 chain with anchor transactions, and
+// the L1 chain which proposes and proves its blocks through the TaikoL1 contract:
+//
+//	hivechain generate-taiko -l1genesis l1.json -l1chain deploy.rlp -l2genesis l2.json \
+//	    -taikol1 0x... -taikol2 0x... -length 10 -output .
+//
 // The 'print' subcommand displays blocks in a chain.rlp file:
 //
 //	hivechain print -v chain.rlp
 //
+// The 'stats' subcommand displays statistics about a chain.rlp file. If a genesis is
+// given, the chain is imported to also report the size of its post-state:
+//
+//	hivechain stats -genesis genesis.json chain.rlp
+//
+// The 'diff' subcommand reports the first differing block of two chain.rlp files:
+//
+//	hivechain diff a.rlp b.rlp
+//
+// The 'export-fixtures' subcommand writes rpc-compat test cases for a chain. The expected
+// responses are recorded from an in-process go-ethereum blockchain which imports the chain:
+//
+//	hivechain export-fixtures -genesis genesis.json -output tests chain.rlp
+//
 // The 'print-genesis' subcommand displays the block header fields of a genesis.json file:
 //
 //	hivechain print-genesis genesis.json
@@ -25,13 +45,14 @@ import (
 	"io"
 	"os"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core/types"
 	ethlog "github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
-const usage = "Usage: hivechain generate|print|print-genesis|trim [ options ] ..."
+const usage = "Usage: hivechain generate|generate-taiko|print|print-genesis|stats|diff|export-fixtures|trim [ options ] ..."
 
 func main() {
 	// Initialize go-ethereum logging.
@@ -45,10 +66,18 @@ func main() {
 	switch os.Args[1] {
 	case "generate":
 		generateCommand(os.Args[2:])
+	case "generate-taiko":
+		generateTaikoCommand(os.Args[2:])
 	case "print":
 		printCommand(os.Args[2:])
 	case "print-genesis":
 		printGenesisCommand(os.Args[2:])
+	case "stats":
+		statsCommand(os.Args[2:])
+	case "diff":
+		diffCommand(os.Args[2:])
+	case "export-fixtures":
+		exportFixturesCommand(os.Args[2:])
 	case "trim":
 		trimCommand(os.Args[2:])
 	default:
@@ -190,6 +219,65 @@ func generateCommand(args []string) {
 	}
 }
 
+// generateTaikoCommand generates a taiko L1/L2 test chain pair.
+func generateTaikoCommand(args []string) {
+	var (
+		cfg         taikoGeneratorConfig
+		l1Genesis   = flag.String("l1genesis", "", "The path and filename to the L1 genesis.json")
+		l2Genesis   = flag.String("l2genesis", "", "The path and filename to the taiko-geth L2 genesis.json")
+		taikoL1     = flag.String("taikol1", "", "Address of the TaikoL1 contract")
+		taikoL2     = flag.String("taikol2", "", "Address of the TaikoL2 contract")
+		signerKey   = flag.String("l1signer", defaultProposerKey, "Private key of the L1 clique signer")
+		proposerKey = flag.String("proposer", defaultProposerKey, "Private key of the L1 proposer account")
+		proverKey   = flag.String("prover", defaultProverKey, "Private key of the L1 prover account")
+		outdir      = flag.String("output", ".", "Chain destination folder, chains are written to the l1 and l2 subfolders")
+	)
+	flag.StringVar(&cfg.l1Prefix, "l1chain", "", "chain.rlp with the L1 blocks deploying the protocol")
+	flag.IntVar(&cfg.blockCount, "length", 2, "The number of L2 blocks to generate")
+	flag.IntVar(&cfg.txCount, "tx-count", 1, "Number of txs per L2 block, besides the anchor")
+	flag.IntVar(&cfg.l1BlockTimeSec, "blocktime", 1, "The L1 block time in seconds")
+	flag.IntVar(&cfg.commitConfirmations, "commit-confirmations", 0, "TaikoL1 commitConfirmations, 0 disables commitBlock")
+	flag.Uint64Var(&cfg.anchorGasLimit, "anchor-gas", 250000, "TaikoL1 anchorTxGasLimit")
+	flag.IntVar(&cfg.zkProofsPerBlock, "zkproofs", 1, "TaikoL1 zkProofsPerBlock")
+	flag.CommandLine.Parse(args)
+
+	if *l1Genesis == "" || *l2Genesis == "" {
+		fatalf("Missing -l1genesis or -l2genesis option.")
+	}
+	if !common.IsHexAddress(*taikoL1) || !common.IsHexAddress(*taikoL2) {
+		fatalf("Missing or invalid -taikol1 or -taikol2 option.")
+	}
+	if cfg.l1BlockTimeSec < 1 {
+		fatalf("-blocktime must be at least 1")
+	}
+	cfg.taikoL1 = common.HexToAddress(*taikoL1)
+	cfg.taikoL2 = common.HexToAddress(*taikoL2)
+
+	var err error
+	if cfg.signerKey, err = loadKey(*signerKey); err != nil {
+		fatalf("invalid -l1signer: %v", err)
+	}
+	if cfg.proposerKey, err = loadKey(*proposerKey); err != nil {
+		fatalf("invalid -proposer: %v", err)
+	}
+	if cfg.proverKey, err = loadKey(*proverKey); err != nil {
+		fatalf("invalid -prover: %v", err)
+	}
+	gspec, err := loadGenesis(*l1Genesis)
+	if err != nil {
+		fatal(err)
+	}
+	cfg.l1Genesis = *gspec
+	if gspec, err = loadGenesis(*l2Genesis); err != nil {
+		fatal(err)
+	}
+	cfg.l2Genesis = *gspec
+
+	if err := cfg.writeTaikoChain(*outdir); err != nil {
+		fatal(err)
+	}
+}
+
 func fatalf(format string, args ...interface{}) {
 	fatal(fmt.Errorf(format, args...))
 }
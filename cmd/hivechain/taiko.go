@@ -0,0 +1,723 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/consensus/taiko"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	// goldenTouchKey signs the anchor transaction of every L2 block. It is a well-known
+	// key, the TaikoL2 contract only accepts anchor calls from this account.
+	goldenTouchKey, _ = crypto.HexToECDSA("92954368afd3caa1f3ce3ead0069c1af414054aefe1ef9aeacc1bf426222ce38")
+
+	// Default accounts of the hive taiko setup, see taiko/config.json.
+	defaultProposerKey = "2bdd21761a483f71054e14f5b827213567971c676928d9a1808cbfa4b7501200"
+	defaultProverKey   = "6bff9a8ffd7f94f43f4f5f642be8a3f32a94c1f316d90862884b2e276293b6ee"
+)
+
+// taikoABI contains the parts of the TaikoL1 and TaikoL2 interfaces used by the generator.
+const taikoABI = `[
+	{"type":"function","name":"commitBlock","inputs":[{"name":"commitSlot","type":"uint64"},{"name":"commitHash","type":"bytes32"}],"outputs":[]},
+	{"type":"function","name":"proposeBlock","inputs":[{"name":"inputs","type":"bytes[]"}],"outputs":[]},
+	{"type":"function","name":"proveBlock","inputs":[{"name":"blockId","type":"uint256"},{"name":"inputs","type":"bytes[]"}],"outputs":[]},
+	{"type":"function","name":"anchor","inputs":[{"name":"l1Height","type":"uint256"},{"name":"l1Hash","type":"bytes32"}],"outputs":[]},
+	{"type":"event","name":"BlockProposed","anonymous":false,"inputs":[
+		{"name":"id","type":"uint256","indexed":true},
+		{"name":"meta","type":"tuple","indexed":false,"components":[
+			{"name":"id","type":"uint256"},
+			{"name":"l1Height","type":"uint256"},
+			{"name":"l1Hash","type":"bytes32"},
+			{"name":"beneficiary","type":"address"},
+			{"name":"txListHash","type":"bytes32"},
+			{"name":"mixHash","type":"bytes32"},
+			{"name":"extraData","type":"bytes"},
+			{"name":"gasLimit","type":"uint64"},
+			{"name":"timestamp","type":"uint64"},
+			{"name":"commitHeight","type":"uint64"},
+			{"name":"commitSlot","type":"uint64"}
+		]}
+	]}
+]`
+
+// taikoBlockMetadata mirrors TaikoData.BlockMetadata.
+type taikoBlockMetadata struct {
+	Id           *big.Int
+	L1Height     *big.Int
+	L1Hash       [32]byte
+	Beneficiary  common.Address
+	TxListHash   [32]byte
+	MixHash      [32]byte
+	ExtraData    []byte
+	GasLimit     uint64
+	Timestamp    uint64
+	CommitHeight uint64
+	CommitSlot   uint64
+}
+
+// taikoBlockHeader mirrors LibBlockHeader.BlockHeader.
+type taikoBlockHeader struct {
+	ParentHash       [32]byte
+	OmmersHash       [32]byte
+	Beneficiary      common.Address
+	StateRoot        [32]byte
+	TransactionsRoot [32]byte
+	ReceiptsRoot     [32]byte
+	LogsBloom        [8][32]byte
+	Difficulty       *big.Int
+	Height           *big.Int
+	GasLimit         uint64
+	GasUsed          uint64
+	Timestamp        uint64
+	ExtraData        []byte
+	MixHash          [32]byte
+	Nonce            uint64
+	BaseFeePerGas    *big.Int
+}
+
+// taikoEvidence mirrors TaikoData.Evidence, the first input of proveBlock.
+type taikoEvidence struct {
+	Meta      taikoBlockMetadata
+	Header    taikoBlockHeader
+	Prover    common.Address
+	Proofs    [][]byte
+	CircuitId uint16
+}
+
+type taikoGeneratorConfig struct {
+	l1Genesis core.Genesis
+	l2Genesis core.Genesis
+	l1Prefix  string // chain.rlp holding the L1 blocks of the protocol deployment
+
+	taikoL1     common.Address
+	taikoL2     common.Address
+	signerKey   *ecdsa.PrivateKey // L1 clique signer
+	proposerKey *ecdsa.PrivateKey
+	proverKey   *ecdsa.PrivateKey
+
+	blockCount          int    // number of generated L2 blocks
+	txCount             int    // number of txs per L2 block, besides the anchor
+	l1BlockTimeSec      int    // L1 block time in seconds
+	commitConfirmations int    // L1 blocks between commitBlock and proposeBlock
+	anchorGasLimit      uint64 // gas limit of the anchor transaction
+	zkProofsPerBlock    int    // number of (dummy) zk proofs expected by proveBlock
+}
+
+// taikoChain is one side (L1 or L2) of the generated taiko network.
+type taikoChain struct {
+	db     ethdb.Database
+	engine consensus.Engine
+	config *params.ChainConfig
+	chain  *core.BlockChain
+	start  uint64 // first block number to export
+}
+
+func newTaikoChain(gspec *core.Genesis, engine, verifier consensus.Engine) (*taikoChain, error) {
+	db := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(db)
+	chain, err := core.NewBlockChain(db, nil, gspec.Config, verifier, vm.Config{}, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't create blockchain: %v", err)
+	}
+	return &taikoChain{db: db, engine: engine, config: gspec.Config, chain: chain, start: 1}, nil
+}
+
+// generate appends a single block to the chain. If gen fails, the block is not inserted.
+func (c *taikoChain) generate(gen func(*core.BlockGen) error) (block *types.Block, receipts types.Receipts, err error) {
+	// BlockGen panics when a transaction can't be applied.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("can't generate block %d: %v", c.chain.CurrentBlock().NumberU64()+1, r)
+		}
+	}()
+	var genErr error
+	blocks, blockReceipts := core.GenerateChain(c.config, c.chain.CurrentBlock(), c.engine, c.db, 1, func(i int, b *core.BlockGen) {
+		genErr = gen(b)
+	})
+	if genErr != nil {
+		return nil, nil, genErr
+	}
+	if _, err := c.chain.InsertChain(blocks); err != nil {
+		return nil, nil, fmt.Errorf("chain validation error: %v", err)
+	}
+	return blocks[0], blockReceipts[0], nil
+}
+
+// importPrefix inserts all blocks of the given chain.rlp file. These blocks
+// are not exported again.
+func (c *taikoChain) importPrefix(file string) error {
+	blocks, err := readChain(file)
+	if err != nil {
+		return err
+	}
+	if len(blocks) > 0 && blocks[0].NumberU64() == 0 {
+		blocks = blocks[1:]
+	}
+	if _, err := c.chain.InsertChain(blocks); err != nil {
+		return fmt.Errorf("can't import %s: %v", file, err)
+	}
+	c.start = c.chain.CurrentBlock().NumberU64() + 1
+	return nil
+}
+
+func (c *taikoChain) write(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	noModify := func(b *types.Block) *types.Block { return b }
+	if err := writeChain(c.chain, filepath.Join(dir, "chain.rlp"), c.start, noModify); err != nil {
+		return err
+	}
+	headstate, _ := c.chain.State()
+	dump := headstate.Dump(&state.DumpConfig{})
+	return ioutil.WriteFile(filepath.Join(dir, "chain_poststate.json"), dump, 0644)
+}
+
+// newL1Chain creates the L1 chain, including the blocks of the protocol deployment.
+func (cfg *taikoGeneratorConfig) newL1Chain() (*taikoChain, error) {
+	if cfg.l1Genesis.Config.Clique == nil {
+		return nil, errors.New("L1 genesis must use clique")
+	}
+	verifier := clique.New(cfg.l1Genesis.Config.Clique, rawdb.NewMemoryDatabase())
+	l1, err := newTaikoChain(&cfg.l1Genesis, &cliqueSeal{verifier, cfg.signerKey}, verifier)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.l1Prefix != "" {
+		if err := l1.importPrefix(cfg.l1Prefix); err != nil {
+			l1.chain.Stop()
+			return nil, err
+		}
+	}
+	return l1, nil
+}
+
+// newL2Chain creates the L2 chain. Its blocks are created and verified by the Taiko
+// consensus engine of taiko-geth, which is used for every chain config with the taiko
+// flag set.
+func (cfg *taikoGeneratorConfig) newL2Chain() (*taikoChain, *taikoL2Seal, error) {
+	if cfg.l2Genesis.GasLimit <= cfg.anchorGasLimit {
+		return nil, nil, fmt.Errorf("L2 gas limit %d too low for anchor gas limit %d", cfg.l2Genesis.GasLimit, cfg.anchorGasLimit)
+	}
+	l2Config := *cfg.l2Genesis.Config
+	l2Config.Taiko = true
+	cfg.l2Genesis.Config = &l2Config
+	seal := &taikoL2Seal{Engine: taiko.New()}
+	l2, err := newTaikoChain(&cfg.l2Genesis, seal, taiko.New())
+	if err != nil {
+		return nil, nil, err
+	}
+	return l2, seal, nil
+}
+
+// writeTaikoChain creates an L2 chain with anchor transactions and the matching
+// L1 chain which proposes and proves every L2 block.
+func (cfg *taikoGeneratorConfig) writeTaikoChain(outputPath string) error {
+	contracts, err := abi.JSON(strings.NewReader(taikoABI))
+	if err != nil {
+		return err
+	}
+	l1, err := cfg.newL1Chain()
+	if err != nil {
+		return err
+	}
+	defer l1.chain.Stop()
+	l2, l2Seal, err := cfg.newL2Chain()
+	if err != nil {
+		return err
+	}
+	defer l2.chain.Stop()
+
+	for i := 0; i < cfg.blockCount; i++ {
+		txList, err := cfg.makeL2TxList(l2)
+		if err != nil {
+			return err
+		}
+		meta, err := cfg.proposeBlock(l1, &contracts, txList)
+		if err != nil {
+			return fmt.Errorf("L2 block %d: %v", i+1, err)
+		}
+		log.Printf("proposed L2 block %d in L1 block %d", meta.Id, meta.L1Height.Uint64()+1)
+
+		block, receipts, err := cfg.buildL2Block(l2, l2Seal, &contracts, meta, txList)
+		if err != nil {
+			return fmt.Errorf("L2 block %d: %v", i+1, err)
+		}
+		if err := cfg.proveBlock(l1, &contracts, meta, l2.chain.GetBlock(block.ParentHash(), block.NumberU64()-1), block, receipts); err != nil {
+			return fmt.Errorf("L2 block %d: %v", i+1, err)
+		}
+		log.Printf("proved L2 block %d (%x)", meta.Id, block.Hash())
+	}
+
+	if err := l1.write(filepath.Join(outputPath, "l1")); err != nil {
+		return err
+	}
+	return l2.write(filepath.Join(outputPath, "l2"))
+}
+
+// makeL2TxList creates the transactions of the next L2 block, using the known
+// accounts allocated in the L2 genesis.
+func (cfg *taikoGeneratorConfig) makeL2TxList(l2 *taikoChain) (types.Transactions, error) {
+	head := l2.chain.CurrentBlock()
+	statedb, err := l2.chain.State()
+	if err != nil {
+		return nil, err
+	}
+	gasPrice := new(big.Int)
+	if l2.config.IsLondon(new(big.Int).Add(head.Number(), common.Big1)) {
+		gasPrice = misc.CalcBaseFee(l2.config, head.Header())
+	}
+	var (
+		txs    types.Transactions
+		signer = types.LatestSigner(l2.config)
+	)
+	// The accounts are sorted, so the same chain is generated on every run.
+	var accounts []common.Address
+	for addr := range knownAccounts {
+		if _, ok := cfg.l2Genesis.Alloc[addr]; ok {
+			accounts = append(accounts, addr)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool { return bytes.Compare(accounts[i][:], accounts[j][:]) < 0 })
+	if len(accounts) == 0 {
+		return nil, nil
+	}
+	// The accounts take turns sending transactions.
+	nonces := make([]uint64, len(accounts))
+	for i, addr := range accounts {
+		nonces[i] = statedb.GetNonce(addr)
+	}
+	for j := 0; j < cfg.txCount; j++ {
+		i := j % len(accounts)
+		addr, nonce := accounts[i], nonces[i]
+		var dst common.Address
+		copy(dst[:], crypto.Keccak256(addr[:], big.NewInt(int64(nonce)).Bytes()))
+		tx := types.NewTransaction(nonce, dst, big.NewInt(1), params.TxGas, gasPrice, nil)
+		signedTx, err := types.SignTx(tx, signer, knownAccounts[addr])
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, signedTx)
+		nonces[i]++
+	}
+	return txs, nil
+}
+
+// proposeBlock commits (if required) and proposes the tx list on L1. It returns the
+// metadata assigned by the TaikoL1 contract.
+func (cfg *taikoGeneratorConfig) proposeBlock(l1 *taikoChain, contracts *abi.ABI, txList types.Transactions) (*taikoBlockMetadata, error) {
+	txListBytes, err := rlp.EncodeToBytes(txList)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		beneficiary = crypto.PubkeyToAddress(cfg.proposerKey.PublicKey)
+		txListHash  = crypto.Keccak256Hash(txListBytes)
+		input       = taikoBlockMetadata{
+			Id:          new(big.Int),
+			L1Height:    new(big.Int),
+			Beneficiary: beneficiary,
+			TxListHash:  txListHash,
+			GasLimit:    cfg.l2Genesis.GasLimit - cfg.anchorGasLimit,
+		}
+	)
+	if cfg.commitConfirmations > 0 {
+		commitHash := crypto.Keccak256Hash(beneficiary[:], txListHash[:])
+		data, err := contracts.Pack("commitBlock", input.CommitSlot, commitHash)
+		if err != nil {
+			return nil, err
+		}
+		block, err := cfg.callTaikoL1(l1, cfg.proposerKey, data)
+		if err != nil {
+			return nil, fmt.Errorf("commitBlock: %v", err)
+		}
+		input.CommitHeight = block.NumberU64()
+		for i := 1; i < cfg.commitConfirmations; i++ {
+			if _, _, err := l1.generate(cfg.l1BlockModifier(nil)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	metaArgs := abi.Arguments{contracts.Events["BlockProposed"].Inputs[1]}
+	metaInput, err := metaArgs.Pack(input)
+	if err != nil {
+		return nil, err
+	}
+	data, err := contracts.Pack("proposeBlock", [][]byte{metaInput, txListBytes})
+	if err != nil {
+		return nil, err
+	}
+	block, err := cfg.callTaikoL1(l1, cfg.proposerKey, data)
+	if err != nil {
+		return nil, fmt.Errorf("proposeBlock: %v", err)
+	}
+
+	// Find the BlockProposed event in the receipt.
+	receipts := l1.chain.GetReceiptsByHash(block.Hash())
+	event := contracts.Events["BlockProposed"]
+	for _, l := range receipts[0].Logs {
+		if l.Address != cfg.taikoL1 || len(l.Topics) == 0 || l.Topics[0] != event.ID {
+			continue
+		}
+		out, err := event.Inputs.NonIndexed().Unpack(l.Data)
+		if err != nil {
+			return nil, err
+		}
+		return abi.ConvertType(out[0], new(taikoBlockMetadata)).(*taikoBlockMetadata), nil
+	}
+	return nil, errors.New("no BlockProposed event in proposeBlock receipt")
+}
+
+// buildL2Block creates the L2 block of a proposal, the way the driver would.
+func (cfg *taikoGeneratorConfig) buildL2Block(l2 *taikoChain, seal *taikoL2Seal, contracts *abi.ABI, meta *taikoBlockMetadata, txList types.Transactions) (*types.Block, types.Receipts, error) {
+	parent := l2.chain.CurrentBlock()
+	if meta.Timestamp <= parent.Time() {
+		return nil, nil, fmt.Errorf("L1 timestamp %d not after L2 parent timestamp %d", meta.Timestamp, parent.Time())
+	}
+	data, err := contracts.Pack("anchor", meta.L1Height, common.Hash(meta.L1Hash))
+	if err != nil {
+		return nil, nil, err
+	}
+	// The golden touch account may not exist yet, which makes BlockGen.TxNonce fail.
+	statedb, err := l2.chain.State()
+	if err != nil {
+		return nil, nil, err
+	}
+	anchorNonce := statedb.GetNonce(crypto.PubkeyToAddress(goldenTouchKey.PublicKey))
+	// The L2 header carries the mixHash chosen by L1. No generated transaction reads
+	// PREVRANDAO, so it's fine to set it when the block is sealed.
+	seal.mixDigest = meta.MixHash
+	block, receipts, err := l2.generate(func(gen *core.BlockGen) error {
+		gen.OffsetTime(int64(meta.Timestamp) - int64(nextBlockTime(parent)))
+		gen.SetDifficulty(common.Big0)
+		gen.SetCoinbase(meta.Beneficiary)
+		gasPrice := new(big.Int)
+		if l2.config.IsLondon(gen.Number()) {
+			gasPrice = gen.BaseFee()
+		}
+		anchor := types.NewTransaction(anchorNonce, cfg.taikoL2, common.Big0, cfg.anchorGasLimit, gasPrice, data)
+		signedAnchor, err := signAnchorTx(anchor, l2.config.ChainID)
+		if err != nil {
+			return err
+		}
+		gen.AddTx(signedAnchor)
+		for _, tx := range txList {
+			gen.AddTx(tx)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if receipts[0].Status != types.ReceiptStatusSuccessful {
+		return nil, nil, errors.New("anchor transaction failed")
+	}
+	return block, receipts, nil
+}
+
+// proveBlock submits a proof for the given L2 block to TaikoL1.
+func (cfg *taikoGeneratorConfig) proveBlock(l1 *taikoChain, contracts *abi.ABI, meta *taikoBlockMetadata, parent, block *types.Block, receipts types.Receipts) error {
+	anchorTx, err := block.Transactions()[0].MarshalBinary()
+	if err != nil {
+		return err
+	}
+	anchorReceipt, err := receipts[0].MarshalBinary()
+	if err != nil {
+		return err
+	}
+	txProof, err := trieProof(block.Transactions(), 0)
+	if err != nil {
+		return err
+	}
+	receiptProof, err := trieProof(receipts, 0)
+	if err != nil {
+		return err
+	}
+	proofs := make([][]byte, cfg.zkProofsPerBlock, cfg.zkProofsPerBlock+2)
+	for i := range proofs {
+		// TaikoL1 of the hive setup accepts any zk proof, see taiko-image/LibZKP.sol.
+		proofs[i] = make([]byte, 100)
+	}
+	proofs = append(proofs, txProof, receiptProof)
+
+	evidence := taikoEvidence{
+		Meta:   *meta,
+		Header: toTaikoBlockHeader(block.Header()),
+		Prover: crypto.PubkeyToAddress(cfg.proverKey.PublicKey),
+		Proofs: proofs,
+	}
+	evidenceInput, err := abi.Arguments{{Type: evidenceType()}}.Pack(evidence)
+	if err != nil {
+		return err
+	}
+	data, err := contracts.Pack("proveBlock", meta.Id, [][]byte{evidenceInput, anchorTx, anchorReceipt})
+	if err != nil {
+		return err
+	}
+	if _, err := cfg.callTaikoL1(l1, cfg.proverKey, data); err != nil {
+		return fmt.Errorf("proveBlock: %v", err)
+	}
+	return nil
+}
+
+// callTaikoL1 adds a new L1 block containing a call to the TaikoL1 contract.
+// It fails if the call is reverted.
+func (cfg *taikoGeneratorConfig) callTaikoL1(l1 *taikoChain, key *ecdsa.PrivateKey, data []byte) (*types.Block, error) {
+	var tx *types.Transaction
+	block, receipts, err := l1.generate(cfg.l1BlockModifier(func(gen *core.BlockGen) error {
+		sender := crypto.PubkeyToAddress(key.PublicKey)
+		gasPrice := big.NewInt(params.GWei)
+		if l1.config.IsLondon(gen.Number()) {
+			gasPrice = gen.BaseFee()
+		}
+		tx = types.NewTransaction(gen.TxNonce(sender), cfg.taikoL1, common.Big0, 5_000_000, gasPrice, data)
+		signedTx, err := types.SignTx(tx, types.LatestSigner(l1.config), key)
+		if err != nil {
+			return err
+		}
+		gen.AddTx(signedTx)
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	if receipts[0].Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("transaction reverted in L1 block %d", block.NumberU64())
+	}
+	return block, nil
+}
+
+func (cfg *taikoGeneratorConfig) l1BlockModifier(gen func(*core.BlockGen) error) func(*core.BlockGen) error {
+	return func(b *core.BlockGen) error {
+		b.OffsetTime(int64(cfg.l1BlockTimeSec) - 10)
+		// OffsetTime recomputes the difficulty, which clique can't do without
+		// access to the chain. There's a single signer, so it's always in-turn.
+		b.SetDifficulty(big.NewInt(2))
+		if gen != nil {
+			return gen(b)
+		}
+		return nil
+	}
+}
+
+// nextBlockTime returns the timestamp core.GenerateChain assigns to a child of parent.
+func nextBlockTime(parent *types.Block) uint64 {
+	if parent.Time() == 0 {
+		return 10
+	}
+	return parent.Time() + 10
+}
+
+func toTaikoBlockHeader(h *types.Header) taikoBlockHeader {
+	var bloom [8][32]byte
+	for i := range bloom {
+		copy(bloom[i][:], h.Bloom[i*32:(i+1)*32])
+	}
+	baseFee := new(big.Int)
+	if h.BaseFee != nil {
+		baseFee.Set(h.BaseFee)
+	}
+	return taikoBlockHeader{
+		ParentHash:       h.ParentHash,
+		OmmersHash:       h.UncleHash,
+		Beneficiary:      h.Coinbase,
+		StateRoot:        h.Root,
+		TransactionsRoot: h.TxHash,
+		ReceiptsRoot:     h.ReceiptHash,
+		LogsBloom:        bloom,
+		Difficulty:       h.Difficulty,
+		Height:           h.Number,
+		GasLimit:         h.GasLimit,
+		GasUsed:          h.GasUsed,
+		Timestamp:        h.Time,
+		ExtraData:        h.Extra,
+		MixHash:          h.MixDigest,
+		Nonce:            h.Nonce.Uint64(),
+		BaseFeePerGas:    baseFee,
+	}
+}
+
+func evidenceType() abi.Type {
+	meta := []abi.ArgumentMarshaling{
+		{Name: "id", Type: "uint256"},
+		{Name: "l1Height", Type: "uint256"},
+		{Name: "l1Hash", Type: "bytes32"},
+		{Name: "beneficiary", Type: "address"},
+		{Name: "txListHash", Type: "bytes32"},
+		{Name: "mixHash", Type: "bytes32"},
+		{Name: "extraData", Type: "bytes"},
+		{Name: "gasLimit", Type: "uint64"},
+		{Name: "timestamp", Type: "uint64"},
+		{Name: "commitHeight", Type: "uint64"},
+		{Name: "commitSlot", Type: "uint64"},
+	}
+	header := []abi.ArgumentMarshaling{
+		{Name: "parentHash", Type: "bytes32"},
+		{Name: "ommersHash", Type: "bytes32"},
+		{Name: "beneficiary", Type: "address"},
+		{Name: "stateRoot", Type: "bytes32"},
+		{Name: "transactionsRoot", Type: "bytes32"},
+		{Name: "receiptsRoot", Type: "bytes32"},
+		{Name: "logsBloom", Type: "bytes32[8]"},
+		{Name: "difficulty", Type: "uint256"},
+		{Name: "height", Type: "uint128"},
+		{Name: "gasLimit", Type: "uint64"},
+		{Name: "gasUsed", Type: "uint64"},
+		{Name: "timestamp", Type: "uint64"},
+		{Name: "extraData", Type: "bytes"},
+		{Name: "mixHash", Type: "bytes32"},
+		{Name: "nonce", Type: "uint64"},
+		{Name: "baseFeePerGas", Type: "uint256"},
+	}
+	typ, err := abi.NewType("tuple", "", []abi.ArgumentMarshaling{
+		{Name: "meta", Type: "tuple", Components: meta},
+		{Name: "header", Type: "tuple", Components: header},
+		{Name: "prover", Type: "address"},
+		{Name: "proofs", Type: "bytes[]"},
+		{Name: "circuitId", Type: "uint16"},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// trieProof returns the RLP encoded merkle proof of the i'th item in list.
+func trieProof(list types.DerivableList, i int) ([]byte, error) {
+	tr := trie.NewEmpty(trie.NewDatabase(rawdb.NewMemoryDatabase()))
+	var buf bytes.Buffer
+	for j := 0; j < list.Len(); j++ {
+		buf.Reset()
+		list.EncodeIndex(j, &buf)
+		key := rlp.AppendUint64(nil, uint64(j))
+		if err := tr.TryUpdate(key, common.CopyBytes(buf.Bytes())); err != nil {
+			return nil, err
+		}
+	}
+	var proof proofList
+	if err := tr.Prove(rlp.AppendUint64(nil, uint64(i)), 0, &proof); err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes([][]byte(proof))
+}
+
+// proofList collects trie proof nodes in order, from root to leaf.
+type proofList [][]byte
+
+func (l *proofList) Put(key []byte, value []byte) error {
+	*l = append(*l, common.CopyBytes(value))
+	return nil
+}
+
+func (l *proofList) Delete(key []byte) error {
+	panic("not supported")
+}
+
+// signAnchorTx signs the anchor transaction with the golden touch key, using the
+// fixed nonce k = 1 (or k = 2 if that fails) required by TaikoL1.
+func signAnchorTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signer := types.NewEIP155Signer(chainID)
+	hash := signer.Hash(tx)
+	for k := int64(1); k <= 2; k++ {
+		sig, ok := signWithK(hash[:], goldenTouchKey, big.NewInt(k))
+		if ok {
+			return tx.WithSignature(signer, sig)
+		}
+	}
+	return nil, errors.New("can't sign anchor transaction")
+}
+
+// signWithK creates a [R || S || V] signature of hash using the given nonce k.
+func signWithK(hash []byte, key *ecdsa.PrivateKey, k *big.Int) ([]byte, bool) {
+	var (
+		curve = crypto.S256()
+		n     = curve.Params().N
+	)
+	rx, ry := curve.ScalarBaseMult(k.Bytes())
+	r := new(big.Int).Mod(rx, n)
+	if r.Sign() == 0 {
+		return nil, false
+	}
+	// s = k^-1 * (hash + r * d) mod n
+	s := new(big.Int).Mul(r, key.D)
+	s.Add(s, new(big.Int).SetBytes(hash))
+	s.Mul(s, new(big.Int).ModInverse(k, n))
+	s.Mod(s, n)
+	if s.Sign() == 0 {
+		return nil, false
+	}
+	v := byte(ry.Bit(0))
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		s.Sub(n, s)
+		v ^= 1
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+	sig[64] = v
+	return sig, true
+}
+
+// cliqueSeal wraps the clique engine, signing every produced block with key.
+type cliqueSeal struct {
+	consensus.Engine
+	key *ecdsa.PrivateKey
+}
+
+// FinalizeAndAssemble implements consensus.Engine, adding the clique signature.
+func (e *cliqueSeal) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, receipts []*types.Receipt) (*types.Block, error) {
+	block, err := e.Engine.FinalizeAndAssemble(chain, header, state, txs, uncles, receipts)
+	if err != nil {
+		return nil, err
+	}
+	h := block.Header()
+	h.Extra = make([]byte, 32+crypto.SignatureLength)
+	sig, err := crypto.Sign(clique.SealHash(h).Bytes(), e.key)
+	if err != nil {
+		return nil, err
+	}
+	copy(h.Extra[32:], sig)
+	return block.WithSeal(h), nil
+}
+
+// taikoL2Seal wraps the Taiko engine, setting the mixHash chosen by TaikoL1.
+type taikoL2Seal struct {
+	consensus.Engine
+	mixDigest common.Hash
+}
+
+// FinalizeAndAssemble implements consensus.Engine.
+func (e *taikoL2Seal) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, receipts []*types.Receipt) (*types.Block, error) {
+	header.MixDigest = e.mixDigest
+	return e.Engine.FinalizeAndAssemble(chain, header, state, txs, uncles, receipts)
+}
+
+func loadKey(hex string) (*ecdsa.PrivateKey, error) {
+	return crypto.HexToECDSA(strings.TrimPrefix(hex, "0x"))
+}
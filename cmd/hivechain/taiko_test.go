@@ -0,0 +1,167 @@
+package main
+
+import (
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func testTaikoConfig(t *testing.T) *taikoGeneratorConfig {
+	proposerKey, _ := loadKey(defaultProposerKey)
+	proverKey, _ := loadKey(defaultProverKey)
+	signer := crypto.PubkeyToAddress(proposerKey.PublicKey)
+	funds := new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.Ether))
+
+	l1Config := *params.AllCliqueProtocolChanges
+	l1Config.ChainID = big.NewInt(31336)
+	l1Config.Clique = &params.CliqueConfig{Period: 1, Epoch: 30000}
+	l1Genesis := core.Genesis{
+		Config:     &l1Config,
+		GasLimit:   30_000_000,
+		Difficulty: big.NewInt(1),
+		ExtraData:  append(append(make([]byte, 32), signer[:]...), make([]byte, crypto.SignatureLength)...),
+		Alloc: core.GenesisAlloc{
+			signer: {Balance: funds},
+			crypto.PubkeyToAddress(proverKey.PublicKey): {Balance: funds},
+		},
+	}
+
+	l2Config := *params.TaikoChainConfig
+	l2Config.ChainID = big.NewInt(167001)
+	l2Genesis := core.Genesis{
+		Config:     &l2Config,
+		GasLimit:   5_000_000,
+		Difficulty: common.Big0,
+		Alloc:      make(core.GenesisAlloc),
+	}
+	for addr := range knownAccounts {
+		l2Genesis.Alloc[addr] = core.GenesisAccount{Balance: funds}
+	}
+
+	return &taikoGeneratorConfig{
+		l1Genesis:        l1Genesis,
+		l2Genesis:        l2Genesis,
+		taikoL1:          common.HexToAddress("0x1000000000000000000000000000000000000001"),
+		taikoL2:          common.HexToAddress("0x1000000000000000000000000000000000000002"),
+		signerKey:        proposerKey,
+		proposerKey:      proposerKey,
+		proverKey:        proverKey,
+		blockCount:       3,
+		txCount:          4,
+		l1BlockTimeSec:   12,
+		anchorGasLimit:   250000,
+		zkProofsPerBlock: 1,
+	}
+}
+
+// This test builds L2 blocks the way generate-taiko does, and proves them on an L1
+// chain without the TaikoL1 contract.
+func TestGenerateTaikoL2(t *testing.T) {
+	cfg := testTaikoConfig(t)
+	contracts, err := abi.JSON(strings.NewReader(taikoABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l1, err := cfg.newL1Chain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l1.chain.Stop()
+	l2, seal, err := cfg.newL2Chain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l2.chain.Stop()
+
+	var (
+		l2Signer    = types.LatestSigner(l2.config)
+		goldenTouch = crypto.PubkeyToAddress(goldenTouchKey.PublicKey)
+		senders     = make(map[common.Address]int)
+	)
+	for i := 1; i <= cfg.blockCount; i++ {
+		txList, err := cfg.makeL2TxList(l2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(txList) != cfg.txCount {
+			t.Fatalf("block %d: got %d txs, want %d", i, len(txList), cfg.txCount)
+		}
+		l1Head := l1.chain.CurrentBlock()
+		meta := &taikoBlockMetadata{
+			Id:          big.NewInt(int64(i)),
+			L1Height:    l1Head.Number(),
+			L1Hash:      l1Head.Hash(),
+			Beneficiary: crypto.PubkeyToAddress(cfg.proposerKey.PublicKey),
+			MixHash:     common.BigToHash(big.NewInt(int64(i))),
+			GasLimit:    cfg.l2Genesis.GasLimit - cfg.anchorGasLimit,
+			Timestamp:   l2.chain.CurrentBlock().Time() + 12,
+		}
+		block, receipts, err := cfg.buildL2Block(l2, seal, &contracts, meta, txList)
+		if err != nil {
+			t.Fatalf("block %d: %v", i, err)
+		}
+		if block.NumberU64() != uint64(i) || block.MixDigest() != meta.MixHash || block.Time() != meta.Timestamp {
+			t.Fatalf("block %d: wrong header %d, mixHash %v, time %d", i, block.NumberU64(), block.MixDigest(), block.Time())
+		}
+		txs := block.Transactions()
+		if len(txs) != cfg.txCount+1 {
+			t.Fatalf("block %d: got %d txs, want anchor and %d txs", i, len(txs), cfg.txCount)
+		}
+		if from, err := types.Sender(l2Signer, txs[0]); err != nil || from != goldenTouch || *txs[0].To() != cfg.taikoL2 {
+			t.Fatalf("block %d: first tx is not an anchor tx (from %v, to %v, err %v)", i, from, txs[0].To(), err)
+		}
+		var prev common.Address
+		for j, tx := range txs[1:] {
+			from, err := types.Sender(l2Signer, tx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if j > 0 && from == prev {
+				t.Errorf("block %d: txs %d and %d have the same sender %v", i, j-1, j, from)
+			}
+			senders[from]++
+			prev = from
+		}
+
+		l1Number := l1.chain.CurrentBlock().NumberU64()
+		parent := l2.chain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+		if err := cfg.proveBlock(l1, &contracts, meta, parent, block, receipts); err != nil {
+			t.Fatalf("block %d: %v", i, err)
+		}
+		if n := l1.chain.CurrentBlock().NumberU64(); n != l1Number+1 {
+			t.Fatalf("block %d: L1 head %d after proveBlock, want %d", i, n, l1Number+1)
+		}
+	}
+	if len(senders) != len(knownAccounts) {
+		t.Errorf("txs sent by %d accounts, want %d", len(senders), len(knownAccounts))
+	}
+
+	dir := t.TempDir()
+	if err := l2.write(dir); err != nil {
+		t.Fatal(err)
+	}
+	blocks, err := readChain(filepath.Join(dir, "chain.rlp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != cfg.blockCount {
+		t.Fatalf("wrote %d L2 blocks, want %d", len(blocks), cfg.blockCount)
+	}
+}
+
+// This test checks that generate-taiko fails when TaikoL1 doesn't accept the proposal.
+func TestGenerateTaikoNoContract(t *testing.T) {
+	cfg := testTaikoConfig(t)
+	err := cfg.writeTaikoChain(t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "no BlockProposed event") {
+		t.Fatalf("wrong error %v", err)
+	}
+}
@@ -1,6 +1,8 @@
 module github.com/ethereum/hive
 
-go 1.18
+go 1.21
+
+replace github.com/ethereum/go-ethereum v1.10.26 => github.com/taikoxyz/taiko-geth v0.0.0-20221223061332-67c60b50b123
 
 require (
 	github.com/davecgh/go-spew v1.1.1
@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/signal"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -33,6 +34,7 @@ func main() {
 		simDevMode            = flag.Bool("dev", false, "Only starts the simulator API endpoint (listening at 127.0.0.1:3000 by default) without starting any simulators.")
 		simDevModeAPIEndpoint = flag.String("dev.addr", "127.0.0.1:3000", "Endpoint that the simulator API listens on")
 		useCredHelper         = flag.Bool("docker.cred-helper", false, "configure docker authentication using locally-configured credential helper")
+		simEnv                = make(envFlag)
 
 		clients = flag.String("client", "go-ethereum", "Comma separated `list` of clients to use. Client names in the list may be given as\n"+
 			"just the client name, or a client_branch specifier. If a branch name is supplied,\n"+
@@ -45,6 +47,9 @@ func main() {
 			"never opens the RPC port.")
 	)
 
+	flag.Var(simEnv, "sim.env", "Sets an environment `variable` of simulators, e.g. HIVE_MY_VAR=value.\n"+
+		"Variable names must start with HIVE_. The option can be given multiple times.")
+
 	// Parse the flags and configure the logger.
 	flag.Parse()
 	log15.Root().SetHandler(log15.LvlFilterHandler(log15.Lvl(*loglevelFlag), log15.StreamHandler(os.Stderr, log15.TerminalFormat())))
@@ -108,6 +113,7 @@ func main() {
 		SimTestPattern:     *simTestPattern,
 		SimParallelism:     *simParallelism,
 		SimDurationLimit:   *simTimeLimit,
+		SimEnv:             simEnv,
 		ClientStartTimeout: *clientTimeout,
 	}
 	runner := libhive.NewRunner(inv, builder, cb)
@@ -146,6 +152,30 @@ func fatal(args ...interface{}) {
 	os.Exit(1)
 }
 
+// envFlag collects KEY=VALUE assignments of environment variables.
+type envFlag map[string]string
+
+func (f envFlag) String() string {
+	vars := make([]string, 0, len(f))
+	for k, v := range f {
+		vars = append(vars, k+"="+v)
+	}
+	sort.Strings(vars)
+	return strings.Join(vars, " ")
+}
+
+func (f envFlag) Set(value string) error {
+	k, v, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("invalid assignment %q, want KEY=VALUE", value)
+	}
+	if !strings.HasPrefix(k, "HIVE_") {
+		return fmt.Errorf("invalid variable name %q, must start with HIVE_", k)
+	}
+	f[k] = v
+	return nil
+}
+
 func splitAndTrim(input, sep string) []string {
 	list := strings.Split(input, sep)
 	for i := range list {
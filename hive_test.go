@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestEnvFlag(t *testing.T) {
+	f := make(envFlag)
+	for _, v := range []string{"HIVE_A=1", "HIVE_B=x=y", "HIVE_EMPTY="} {
+		if err := f.Set(v); err != nil {
+			t.Fatalf("Set(%q) failed: %v", v, err)
+		}
+	}
+	if s, want := f.String(), "HIVE_A=1 HIVE_B=x=y HIVE_EMPTY="; s != want {
+		t.Errorf("wrong String() result %q, want %q", s, want)
+	}
+
+	for _, v := range []string{"NOT_HIVE=1", "hive_lower=1", "HIVE", "=1", "HIVE_NOVALUE"} {
+		if err := f.Set(v); err == nil {
+			t.Errorf("Set(%q) succeeded, want error", v)
+		}
+	}
+	if len(f) != 3 {
+		t.Errorf("invalid assignments were added: %v", f)
+	}
+}
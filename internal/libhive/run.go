@@ -204,14 +204,14 @@ func (r *Runner) run(ctx context.Context, sim string, env SimEnv) (SimResult, er
 	defer shutdownServer(server)
 
 	// Create the simulator container.
-	opts := ContainerOptions{
-		Env: map[string]string{
-			"HIVE_SIMULATOR":    "http://" + server.Addr().String(),
-			"HIVE_PARALLELISM":  strconv.Itoa(env.SimParallelism),
-			"HIVE_LOGLEVEL":     strconv.Itoa(env.SimLogLevel),
-			"HIVE_TEST_PATTERN": env.SimTestPattern,
-		},
+	opts := ContainerOptions{Env: make(map[string]string)}
+	for k, v := range env.SimEnv {
+		opts.Env[k] = v
 	}
+	opts.Env["HIVE_SIMULATOR"] = "http://" + server.Addr().String()
+	opts.Env["HIVE_PARALLELISM"] = strconv.Itoa(env.SimParallelism)
+	opts.Env["HIVE_LOGLEVEL"] = strconv.Itoa(env.SimLogLevel)
+	opts.Env["HIVE_TEST_PATTERN"] = env.SimTestPattern
 	containerID, err := r.container.CreateContainer(ctx, r.simImages[sim], opts)
 	if err != nil {
 		return SimResult{}, err
@@ -36,6 +36,8 @@ type SimEnv struct {
 	SimLogLevel    int
 	SimParallelism int
 	SimTestPattern string
+	// Additional environment variables of the simulator container.
+	SimEnv map[string]string
 
 	// This is the time limit for the simulation run.
 	// There is no default limit.
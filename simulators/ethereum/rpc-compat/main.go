@@ -39,14 +39,30 @@ var (
 		"genesis.json": "./tests/genesis.json",
 		"chain.rlp":    "./tests/chain.rlp",
 	}
+
+	taikoTestsDir = "./tests-taiko"
 )
 
 type test struct {
-	Name string
-	Data []byte
+	Name      string
+	Data      []byte
+	Tolerance *tolerance
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "record" {
+		if err := recordMain(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	taikoEnv, taikoSecret, err := taikoParams(taikoTestsDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "can't load taiko genesis:", err)
+		os.Exit(1)
+	}
+
 	suite := hivesim.Suite{
 		Name: "rpc-compat",
 		Description: `
@@ -61,7 +77,21 @@ conformance with the execution API specification.`[1:],
 		Parameters:  clientEnv,
 		Files:       files,
 		Run: func(t *hivesim.T, c *hivesim.Client) {
-			runAllTests(t, c, c.Type)
+			runAllTests(t, c, "tests", c.Type)
+		},
+		AlwaysRun: true,
+	})
+	suite.Add(&hivesim.ClientTestSpec{
+		Role:        "taiko-geth",
+		Name:        "client launch (taiko)",
+		Description: `This test launches the taiko L2 client and collects its logs.`,
+		Parameters:  taikoEnv,
+		Files:       taikoFiles(),
+		Run: func(t *hivesim.T, c *hivesim.Client) {
+			if err := proposeTaikoBlocks(c, taikoSecret, taikoProposedBlocks); err != nil {
+				t.Fatalf("can't propose blocks: %v", err)
+			}
+			runAllTests(t, c, taikoTestsDir, c.Type)
 		},
 		AlwaysRun: true,
 	})
@@ -69,16 +99,26 @@ conformance with the execution API specification.`[1:],
 	hivesim.MustRunSuite(sim, suite)
 }
 
-func runAllTests(t *hivesim.T, c *hivesim.Client, clientName string) {
+// taikoFiles returns the files for taiko-geth. The L2 chain is optional, the proposed
+// blocks of the taiko_ namespace tests are built on top of it.
+func taikoFiles() map[string]string {
+	chain := filepath.Join(taikoTestsDir, "chain.rlp")
+	if _, err := os.Stat(chain); err != nil {
+		return nil
+	}
+	return map[string]string{"chain.rlp": chain}
+}
+
+func runAllTests(t *hivesim.T, c *hivesim.Client, dir string, clientName string) {
 	_, testPattern := t.Sim.TestPattern()
 	re := regexp.MustCompile(testPattern)
 
-	tests := loadTests(t, "tests", re)
+	tests := loadTests(t, dir, re)
 	for _, test := range tests {
 		t.Run(hivesim.TestSpec{
 			Name: fmt.Sprintf("%s (%s)", test.Name, clientName),
 			Run: func(t *hivesim.T) {
-				if err := runTest(t, c, test.Data); err != nil {
+				if err := runTest(t, c, test.Data, test.Tolerance); err != nil {
 					t.Fatal(err)
 				}
 			},
@@ -86,7 +126,7 @@ func runAllTests(t *hivesim.T, c *hivesim.Client, clientName string) {
 	}
 }
 
-func runTest(t *hivesim.T, c *hivesim.Client, data []byte) error {
+func runTest(t *hivesim.T, c *hivesim.Client, data []byte, tol *tolerance) error {
 	var (
 		client = &http.Client{
 			Timeout: 5 * time.Second,
@@ -120,20 +160,24 @@ func runTest(t *hivesim.T, c *hivesim.Client, data []byte) error {
 				return fmt.Errorf("invalid test, response before request")
 			}
 			want := []byte(strings.TrimSpace(line)[3:]) // trim leading "<< "
+			got, want, err := tol.apply(resp, want)
+			if err != nil {
+				return err
+			}
 			// Now compare.
-			d, err := diff.New().Compare(resp, want)
+			d, err := diff.New().Compare(got, want)
 			if err != nil {
 				return fmt.Errorf("failed to unmarshal value: %s\n", err)
 			}
 			// If there is a discrepancy, return error.
 			if d.Modified() {
-				var got map[string]interface{}
-				json.Unmarshal(resp, &got)
+				var gotObj map[string]interface{}
+				json.Unmarshal(got, &gotObj)
 				config := formatter.AsciiFormatterConfig{
 					ShowArrayIndex: true,
 					Coloring:       false,
 				}
-				formatter := formatter.NewAsciiFormatter(got, config)
+				formatter := formatter.NewAsciiFormatter(gotObj, config)
 				diffString, _ := formatter.Format(d)
 				return fmt.Errorf("response differs from expected:\n%s", diffString)
 			}
@@ -202,7 +246,7 @@ func (rt *loggingRoundTrip) RoundTrip(req *http.Request) (*http.Response, error)
 // loadTests walks the given directory looking for *.io files to load.
 func loadTests(t *hivesim.T, root string, re *regexp.Regexp) []test {
 	tests := make([]test, 0)
-	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			t.Logf("unable to walk path: %s", err)
 			return err
@@ -222,8 +266,15 @@ func loadTests(t *hivesim.T, root string, re *regexp.Regexp) []test {
 		if err != nil {
 			return err
 		}
-		tests = append(tests, test{strings.TrimLeft(pathname, "/"), data})
+		tol, err := loadTolerance(strings.TrimSuffix(path, ".io") + ".tolerance.json")
+		if err != nil {
+			return err
+		}
+		tests = append(tests, test{strings.TrimLeft(pathname, "/"), data, tol})
 		return nil
 	})
+	if err != nil {
+		t.Fatalf("unable to load tests: %v", err)
+	}
 	return tests
 }
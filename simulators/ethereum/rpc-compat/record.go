@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// recordMain implements the 'record' command, which creates test files by sending the
+// requests of existing tests to a reference client:
+//
+//	rpc-compat record -url http://127.0.0.1:8545 -output tests requests
+//
+// All *.io files below the requests directory are processed. Their '<<' lines are
+// dropped, and the response of the reference client is inserted after every '>>'
+// line. Comments and tolerance files are kept.
+func recordMain(args []string) error {
+	var (
+		flags  = flag.NewFlagSet("record", flag.ExitOnError)
+		url    = flags.String("url", "http://127.0.0.1:8545", "RPC endpoint of the reference client")
+		outdir = flags.String("output", "tests", "Output directory")
+	)
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: rpc-compat record [ options ] <requests-dir>")
+	}
+	root := flags.Arg(0)
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	var count int
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		dest := filepath.Join(*outdir, rel)
+		switch {
+		case strings.HasSuffix(path, ".tolerance.json"):
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return writeFile(dest, data)
+		case strings.HasSuffix(path, ".io"):
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			out, err := recordTest(client, *url, data)
+			if err != nil {
+				return fmt.Errorf("%s: %v", rel, err)
+			}
+			count++
+			return writeFile(dest, out)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("recorded %d tests to %s\n", count, *outdir)
+	return nil
+}
+
+// recordTest runs the requests of a test script and returns the script with the
+// received responses.
+func recordTest(client *http.Client, url string, script []byte) ([]byte, error) {
+	var out bytes.Buffer
+	for _, line := range strings.Split(string(script), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case len(line) == 0:
+			continue
+		case strings.HasPrefix(line, "//"):
+			out.WriteString(line + "\n")
+		case strings.HasPrefix(line, ">> "):
+			resp, err := postHttp(client, url, []byte(line[3:]))
+			if err != nil {
+				return nil, err
+			}
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, resp); err != nil {
+				return nil, fmt.Errorf("invalid response to %s: %v", line[3:], err)
+			}
+			out.WriteString(line + "\n")
+			out.WriteString("<< " + compact.String() + "\n")
+		case strings.HasPrefix(line, "<< "):
+			// Old responses are replaced.
+			continue
+		default:
+			return nil, fmt.Errorf("invalid line in test script: %s", line)
+		}
+	}
+	return out.Bytes(), nil
+}
+
+func writeFile(file string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0644)
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/hive/hivesim"
+)
+
+// taikoProposedBlocks is the number of blocks proposed through the engine API before the
+// taiko tests run. The L1 origin tests expect them to be blocks 1 and 2.
+const taikoProposedBlocks = 2
+
+// taikoTxList is the transaction list of the proposed blocks. taiko-geth doesn't build
+// blocks from empty lists, so it contains an unsigned transaction, which is skipped.
+const taikoTxList = "0xcccb8080825208808080808080"
+
+// taikoParams returns the client parameters of taiko-geth. The network ID selects the
+// built-in L2 genesis of taiko-geth, it is the chain ID of the genesis of the test chain.
+// The JWT secret of the engine API is created for the run.
+func taikoParams(dir string) (hivesim.Params, []byte, error) {
+	file := filepath.Join(dir, "genesis.json")
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	var genesis struct {
+		Config struct {
+			ChainID *big.Int `json:"chainId"`
+		} `json:"config"`
+	}
+	if err := json.Unmarshal(data, &genesis); err != nil {
+		return nil, nil, fmt.Errorf("invalid genesis %s: %v", file, err)
+	}
+	if genesis.Config.ChainID == nil {
+		return nil, nil, fmt.Errorf("genesis %s has no chain ID", file)
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, nil, err
+	}
+	params := hivesim.Params{
+		"HIVE_NODETYPE":         "archive",
+		"HIVE_NETWORK_ID":       genesis.Config.ChainID.String(),
+		"HIVE_TAIKO_JWT_SECRET": hex.EncodeToString(secret),
+	}
+	return params, secret, nil
+}
+
+// taikoBlock is the part of a block used to build its child.
+type taikoBlock struct {
+	Number    hexUint64 `json:"number"`
+	Hash      string    `json:"hash"`
+	Timestamp hexUint64 `json:"timestamp"`
+}
+
+type hexUint64 uint64
+
+func (n *hexUint64) UnmarshalJSON(input []byte) error {
+	var s string
+	if err := json.Unmarshal(input, &s); err != nil {
+		return err
+	}
+	v, err := strconv.ParseUint(s, 0, 64)
+	*n = hexUint64(v)
+	return err
+}
+
+// proposeTaikoBlocks inserts n blocks through the engine API, the way the taiko driver
+// inserts proposed blocks, so that taiko-geth records their L1 origins. There is no L1
+// chain, block i refers to L1 block 100+i with a made-up hash.
+func proposeTaikoBlocks(c *hivesim.Client, secret []byte, n int) error {
+	var (
+		client = &http.Client{Timeout: 5 * time.Second}
+		rpc    = fmt.Sprintf("http://%s", net.JoinHostPort(c.IP.String(), "8545"))
+		engine = &engineClient{client, fmt.Sprintf("http://%s", net.JoinHostPort(c.IP.String(), "8551")), secret}
+	)
+	for i := 0; i < n; i++ {
+		var head taikoBlock
+		if err := call(client, rpc, nil, &head, "eth_getBlockByNumber", "latest", false); err != nil {
+			return err
+		}
+		id := uint64(head.Number) + 1
+		l1Height := 100 + id
+		timestamp := fmt.Sprintf("%#x", uint64(head.Timestamp)+1)
+		mixHash := fmt.Sprintf("0x%064x", id)
+		attrs := map[string]interface{}{
+			"timestamp":             timestamp,
+			"prevRandao":            mixHash,
+			"suggestedFeeRecipient": "0x0000000000000000000000000000000000000000",
+			"blockMetadata": map[string]interface{}{
+				"beneficiary":    "0x0000000000000000000000000000000000000000",
+				"gasLimit":       6000000,
+				"timestamp":      timestamp,
+				"mixHash":        mixHash,
+				"extraData":      "0x",
+				"txList":         taikoTxList,
+				"highestBlockID": id,
+			},
+			"l1Origin": map[string]interface{}{
+				"blockID":       fmt.Sprintf("%#x", id),
+				"l1BlockHeight": fmt.Sprintf("%#x", l1Height),
+				"l1BlockHash":   fmt.Sprintf("0x%064x", l1Height),
+				"throwaway":     false,
+			},
+		}
+		var fc forkchoiceResponse
+		if err := engine.call(&fc, "engine_forkchoiceUpdatedV1", map[string]string{"headBlockHash": head.Hash}, attrs); err != nil {
+			return err
+		}
+		if fc.PayloadStatus.Status != "VALID" || fc.PayloadID == nil {
+			return fmt.Errorf("block %d not built: status %s", id, fc.PayloadStatus.Status)
+		}
+		var payload json.RawMessage
+		if err := engine.call(&payload, "engine_getPayloadV1", *fc.PayloadID); err != nil {
+			return err
+		}
+		var block struct {
+			BlockHash string `json:"blockHash"`
+		}
+		if err := json.Unmarshal(payload, &block); err != nil {
+			return err
+		}
+		var status struct {
+			Status string `json:"status"`
+		}
+		if err := engine.call(&status, "engine_newPayloadV1", payload); err != nil {
+			return err
+		}
+		if status.Status != "VALID" {
+			return fmt.Errorf("block %d not inserted: status %s", id, status.Status)
+		}
+		state := map[string]string{"headBlockHash": block.BlockHash, "safeBlockHash": block.BlockHash}
+		if err := engine.call(&fc, "engine_forkchoiceUpdatedV1", state, nil); err != nil {
+			return err
+		}
+		if fc.PayloadStatus.Status != "VALID" {
+			return fmt.Errorf("block %d not made head: status %s", id, fc.PayloadStatus.Status)
+		}
+	}
+	return nil
+}
+
+type forkchoiceResponse struct {
+	PayloadStatus struct {
+		Status string `json:"status"`
+	} `json:"payloadStatus"`
+	PayloadID *string `json:"payloadId"`
+}
+
+// engineClient calls the engine API, which is authenticated with JWT tokens.
+type engineClient struct {
+	client *http.Client
+	url    string
+	secret []byte
+}
+
+func (e *engineClient) call(result interface{}, method string, params ...interface{}) error {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	claims := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"iat":%d}`, time.Now().Unix())))
+	mac := hmac.New(sha256.New, e.secret)
+	mac.Write([]byte(header + "." + claims))
+	token := header + "." + claims + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	return call(e.client, e.url, http.Header{"Authorization": {"Bearer " + token}}, result, method, params...)
+}
+
+// call performs a JSON-RPC call and decodes its result.
+func call(c *http.Client, url string, header http.Header, result interface{}, method string, params ...interface{}) error {
+	req, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequest("POST", url, bytes.NewReader(req))
+	if err != nil {
+		return err
+	}
+	httpReq.Header = header.Clone()
+	if httpReq.Header == nil {
+		httpReq.Header = make(http.Header)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := c.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("%s: %v", method, err)
+	}
+	defer resp.Body.Close()
+	var msg struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		return fmt.Errorf("%s: invalid response: %v", method, err)
+	}
+	if msg.Error != nil {
+		return fmt.Errorf("%s: error %d: %s", method, msg.Error.Code, msg.Error.Message)
+	}
+	return json.Unmarshal(msg.Result, result)
+}
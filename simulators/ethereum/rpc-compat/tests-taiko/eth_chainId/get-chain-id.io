@@ -0,0 +1,3 @@
+// retrieves the chain id of the taiko L2 network
+>> {"jsonrpc":"2.0","id":1,"method":"eth_chainId"}
+<< {"jsonrpc":"2.0","id":1,"result":"0x28c59"}
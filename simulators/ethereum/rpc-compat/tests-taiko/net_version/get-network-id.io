@@ -0,0 +1,3 @@
+// retrieves the network id of the taiko L2 network
+>> {"jsonrpc":"2.0","id":1,"method":"net_version"}
+<< {"jsonrpc":"2.0","id":1,"result":"167001"}
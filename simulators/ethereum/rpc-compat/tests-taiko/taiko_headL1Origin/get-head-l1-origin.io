@@ -0,0 +1,3 @@
+// the head L1 origin is the L1 origin of the last proposed block
+>> {"jsonrpc":"2.0","id":1,"method":"taiko_headL1Origin"}
+<< {"jsonrpc":"2.0","id":1,"result":{"blockID":"0x2","l2BlockHash":"0x0000000000000000000000000000000000000000000000000000000000000000","l1BlockHeight":"0x66","l1BlockHash":"0x0000000000000000000000000000000000000000000000000000000000000066","throwaway":false}}
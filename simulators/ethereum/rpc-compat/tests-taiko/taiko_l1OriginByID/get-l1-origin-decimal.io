@@ -0,0 +1,3 @@
+// the block ID can also be given as a decimal number
+>> {"jsonrpc":"2.0","id":1,"method":"taiko_l1OriginByID","params":["2"]}
+<< {"jsonrpc":"2.0","id":1,"result":{"blockID":"0x2","l2BlockHash":"0x0000000000000000000000000000000000000000000000000000000000000000","l1BlockHeight":"0x66","l1BlockHash":"0x0000000000000000000000000000000000000000000000000000000000000066","throwaway":false}}
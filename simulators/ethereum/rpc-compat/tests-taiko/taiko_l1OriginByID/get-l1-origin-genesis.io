@@ -0,0 +1,3 @@
+// the genesis block has no L1 origin
+>> {"jsonrpc":"2.0","id":1,"method":"taiko_l1OriginByID","params":["0x0"]}
+<< {"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"not found"}}
@@ -0,0 +1,3 @@
+// the block ID must be a number
+>> {"jsonrpc":"2.0","id":1,"method":"taiko_l1OriginByID","params":["latest"]}
+<< {"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"invalid argument 0"}}
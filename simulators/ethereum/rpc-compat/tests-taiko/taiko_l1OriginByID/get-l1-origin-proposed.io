@@ -0,0 +1,3 @@
+// requests the L1 origin of the first proposed block
+>> {"jsonrpc":"2.0","id":1,"method":"taiko_l1OriginByID","params":["0x1"]}
+<< {"jsonrpc":"2.0","id":1,"result":{"blockID":"0x1","l2BlockHash":"0x0000000000000000000000000000000000000000000000000000000000000000","l1BlockHeight":"0x65","l1BlockHash":"0x0000000000000000000000000000000000000000000000000000000000000065","throwaway":false}}
@@ -0,0 +1,3 @@
+// requests the L1 origin of a block far beyond the head
+>> {"jsonrpc":"2.0","id":1,"method":"taiko_l1OriginByID","params":["0xffffff"]}
+<< {"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"not found"}}
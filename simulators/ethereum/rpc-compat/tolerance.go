@@ -0,0 +1,256 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+)
+
+// tolerance describes which differences between the expected and actual response of a
+// test are acceptable. It is loaded from a <test>.tolerance.json file next to the test.
+//
+// Fields are addressed by dot-separated paths into the response object, e.g.
+// "result.transactions.*.blockHash". The "*" element matches all array elements or
+// object keys.
+type tolerance struct {
+	// Ignore lists fields which are not compared at all.
+	Ignore []string `json:"ignore"`
+	// Ranges lists numeric fields which may have any value in the given range.
+	// Bounds can be decimal or hex numbers.
+	Ranges map[string]numericRange `json:"ranges"`
+	// Unordered lists arrays in which the order of elements does not matter.
+	Unordered []string `json:"unordered"`
+}
+
+type numericRange struct {
+	Min string `json:"min"`
+	Max string `json:"max"`
+}
+
+// loadTolerance reads a tolerance file. It returns nil if the file does not exist.
+func loadTolerance(file string) (*tolerance, error) {
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var tol tolerance
+	if err := json.Unmarshal(data, &tol); err != nil {
+		return nil, fmt.Errorf("invalid tolerance file %s: %v", file, err)
+	}
+	for path, r := range tol.Ranges {
+		if _, ok := parseNumber(r.Min); !ok && r.Min != "" {
+			return nil, fmt.Errorf("invalid tolerance file %s: bad min value for %s", file, path)
+		}
+		if _, ok := parseNumber(r.Max); !ok && r.Max != "" {
+			return nil, fmt.Errorf("invalid tolerance file %s: bad max value for %s", file, path)
+		}
+	}
+	return &tol, nil
+}
+
+// apply normalizes the response got and the expected response want, such that all
+// tolerated differences disappear. Values outside of an allowed range, and paths which
+// match nothing in the response, are reported as an error.
+func (tol *tolerance) apply(got, want []byte) ([]byte, []byte, error) {
+	if tol == nil {
+		return got, want, nil
+	}
+	var g, w interface{}
+	if err := json.Unmarshal(got, &g); err != nil {
+		return nil, nil, fmt.Errorf("invalid response: %v", err)
+	}
+	if err := json.Unmarshal(want, &w); err != nil {
+		return nil, nil, fmt.Errorf("invalid expected response: %v", err)
+	}
+	if err := tol.checkPaths(g); err != nil {
+		return nil, nil, err
+	}
+
+	for _, path := range tol.Ignore {
+		g = deletePath(g, splitPath(path))
+		w = deletePath(w, splitPath(path))
+	}
+	for path, r := range tol.Ranges {
+		var rangeErr error
+		g = updatePath(g, splitPath(path), func(v interface{}) interface{} {
+			if err := r.check(v); err != nil && rangeErr == nil {
+				rangeErr = fmt.Errorf("%s: %v", path, err)
+			}
+			return nil
+		})
+		if rangeErr != nil {
+			return nil, nil, rangeErr
+		}
+		// Values in range are considered equal, drop them from both sides.
+		w = updatePath(w, splitPath(path), func(interface{}) interface{} { return nil })
+	}
+	for _, path := range tol.Unordered {
+		g = updatePath(g, splitPath(path), sortArray)
+		w = updatePath(w, splitPath(path), sortArray)
+	}
+
+	gotOut, _ := json.Marshal(g)
+	wantOut, _ := json.Marshal(w)
+	return gotOut, wantOut, nil
+}
+
+// checkPaths verifies that every path of the tolerance matches a value of the response.
+// Otherwise, the tolerance would hide nothing and the test would pass for the wrong
+// reason, e.g. when the response is an error instead of a result.
+func (tol *tolerance) checkPaths(v interface{}) error {
+	paths := append([]string{}, tol.Ignore...)
+	for path := range tol.Ranges {
+		paths = append(paths, path)
+	}
+	paths = append(paths, tol.Unordered...)
+	sort.Strings(paths)
+	for _, path := range paths {
+		if !hasPath(v, splitPath(path)) {
+			return fmt.Errorf("tolerance path %s not in response", path)
+		}
+	}
+	return nil
+}
+
+// check verifies that v is a number within the range.
+func (r numericRange) check(v interface{}) error {
+	n, ok := parseNumber(v)
+	if !ok {
+		return fmt.Errorf("value %v is not a number", v)
+	}
+	if min, ok := parseNumber(r.Min); ok && n.Cmp(min) < 0 {
+		return fmt.Errorf("value %v below minimum %s", v, r.Min)
+	}
+	if max, ok := parseNumber(r.Max); ok && n.Cmp(max) > 0 {
+		return fmt.Errorf("value %v above maximum %s", v, r.Max)
+	}
+	return nil
+}
+
+// parseNumber parses JSON numbers and decimal or hex number strings.
+func parseNumber(v interface{}) (*big.Int, bool) {
+	switch v := v.(type) {
+	case float64:
+		n, _ := big.NewFloat(v).Int(nil)
+		return n, true
+	case string:
+		if strings.HasPrefix(v, "0x") {
+			return new(big.Int).SetString(v[2:], 16)
+		}
+		return new(big.Int).SetString(v, 10)
+	}
+	return nil, false
+}
+
+func splitPath(path string) []string {
+	return strings.Split(path, ".")
+}
+
+// deletePath removes the value at path from v.
+func deletePath(v interface{}, path []string) interface{} {
+	if len(path) == 0 {
+		return v
+	}
+	last := len(path) == 1
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k := range v {
+			if path[0] != "*" && path[0] != k {
+				continue
+			}
+			if last {
+				delete(v, k)
+			} else {
+				v[k] = deletePath(v[k], path[1:])
+			}
+		}
+	case []interface{}:
+		if last {
+			// Array elements are not removed, since that would shift the indexes.
+			return updatePath(v, path, func(interface{}) interface{} { return nil })
+		}
+		for i := range v {
+			if path[0] == "*" || path[0] == fmt.Sprint(i) {
+				v[i] = deletePath(v[i], path[1:])
+			}
+		}
+	}
+	return v
+}
+
+// hasPath reports whether path matches any value in v.
+func hasPath(v interface{}, path []string) bool {
+	if len(path) == 0 {
+		return true
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, elem := range v {
+			if (path[0] == "*" || path[0] == k) && hasPath(elem, path[1:]) {
+				return true
+			}
+		}
+	case []interface{}:
+		for i, elem := range v {
+			if (path[0] == "*" || path[0] == fmt.Sprint(i)) && hasPath(elem, path[1:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// updatePath replaces all values matching path with the result of fn.
+func updatePath(v interface{}, path []string, fn func(interface{}) interface{}) interface{} {
+	if len(path) == 0 {
+		return fn(v)
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k := range v {
+			if path[0] == "*" || path[0] == k {
+				v[k] = updatePath(v[k], path[1:], fn)
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			if path[0] == "*" || path[0] == fmt.Sprint(i) {
+				v[i] = updatePath(v[i], path[1:], fn)
+			}
+		}
+	}
+	return v
+}
+
+// sortArray sorts the elements of a JSON array by their encoding.
+func sortArray(v interface{}) interface{} {
+	arr, ok := v.([]interface{})
+	if !ok {
+		return v
+	}
+	keys := make([]string, len(arr))
+	for i, elem := range arr {
+		enc, _ := json.Marshal(elem)
+		keys[i] = string(enc)
+	}
+	sort.Sort(byKey{arr, keys})
+	return arr
+}
+
+type byKey struct {
+	values []interface{}
+	keys   []string
+}
+
+func (s byKey) Len() int           { return len(s.values) }
+func (s byKey) Less(i, j int) bool { return s.keys[i] < s.keys[j] }
+func (s byKey) Swap(i, j int) {
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToleranceApply(t *testing.T) {
+	tol := &tolerance{
+		Ignore:    []string{"result.hash", "result.txs.*.blockHash"},
+		Ranges:    map[string]numericRange{"result.gasUsed": {Min: "0x10", Max: "100"}},
+		Unordered: []string{"result.peers"},
+	}
+	var (
+		got  = `{"result":{"hash":"0x01","gasUsed":"0x20","peers":["b","a"],"txs":[{"blockHash":"0x1","nonce":"0x0"}]}}`
+		want = `{"result":{"hash":"0x02","gasUsed":"0x11","peers":["a","b"],"txs":[{"blockHash":"0x2","nonce":"0x0"}]}}`
+	)
+	g, w, err := tol.apply([]byte(got), []byte(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(g) != string(w) {
+		t.Fatalf("normalized responses differ:\ngot:  %s\nwant: %s", g, w)
+	}
+
+	outOfRange := strings.Replace(got, `"gasUsed":"0x20"`, `"gasUsed":"0x65"`, 1)
+	if _, _, err := tol.apply([]byte(outOfRange), []byte(want)); err == nil {
+		t.Fatal("expected error for value out of range")
+	}
+}
+
+func TestToleranceMissingPath(t *testing.T) {
+	tests := []struct {
+		tol  *tolerance
+		resp string
+	}{
+		{&tolerance{Ignore: []string{"result.hash"}}, `{"error":{"code":-32000,"message":"not found"}}`},
+		{&tolerance{Ignore: []string{"result.txs.*.blockHash"}}, `{"result":{"txs":[{"nonce":"0x0"}]}}`},
+		{&tolerance{Ranges: map[string]numericRange{"result.gasUsed": {Max: "0x10"}}}, `{"result":{}}`},
+		{&tolerance{Unordered: []string{"result.1"}}, `{"result":["a"]}`},
+	}
+	for _, test := range tests {
+		_, _, err := test.tol.apply([]byte(test.resp), []byte(test.resp))
+		if err == nil || !strings.Contains(err.Error(), "not in response") {
+			t.Errorf("tolerance %+v on %s: wrong error %v", test.tol, test.resp, err)
+		}
+	}
+}
@@ -3,10 +3,10 @@ package main
 import (
 	"fmt"
 	"math/big"
-	"strings"
 
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/hive/hivesim"
+	"github.com/ethereum/hive/simulators/ethereum/rpc/rpctest"
 )
 
 var (
@@ -37,61 +37,6 @@ var files = map[string]string{
 	"/genesis.json": "./init/genesis.json",
 }
 
-type testSpec struct {
-	Name  string
-	About string
-	Run   func(*TestEnv)
-}
-
-var tests = []testSpec{
-	// HTTP RPC tests.
-	{Name: "http/BalanceAndNonceAt", Run: balanceAndNonceAtTest},
-	{Name: "http/CanonicalChain", Run: canonicalChainTest},
-	{Name: "http/CodeAt", Run: CodeAtTest},
-	{Name: "http/ContractDeployment", Run: deployContractTest},
-	{Name: "http/ContractDeploymentOutOfGas", Run: deployContractOutOfGasTest},
-	{Name: "http/EstimateGas", Run: estimateGasTest},
-	{Name: "http/GenesisBlockByHash", Run: genesisBlockByHashTest},
-	{Name: "http/GenesisBlockByNumber", Run: genesisBlockByNumberTest},
-	{Name: "http/GenesisHeaderByHash", Run: genesisHeaderByHashTest},
-	{Name: "http/GenesisHeaderByNumber", Run: genesisHeaderByNumberTest},
-	{Name: "http/Receipt", Run: receiptTest},
-	{Name: "http/SyncProgress", Run: syncProgressTest},
-	{Name: "http/TransactionCount", Run: transactionCountTest},
-	{Name: "http/TransactionInBlock", Run: transactionInBlockTest},
-	{Name: "http/TransactionReceipt", Run: TransactionReceiptTest},
-
-	// HTTP ABI tests.
-	{Name: "http/ABICall", Run: callContractTest},
-	{Name: "http/ABITransact", Run: transactContractTest},
-
-	// WebSocket RPC tests.
-	{Name: "ws/BalanceAndNonceAt", Run: balanceAndNonceAtTest},
-	{Name: "ws/CanonicalChain", Run: canonicalChainTest},
-	{Name: "ws/CodeAt", Run: CodeAtTest},
-	{Name: "ws/ContractDeployment", Run: deployContractTest},
-	{Name: "ws/ContractDeploymentOutOfGas", Run: deployContractOutOfGasTest},
-	{Name: "ws/EstimateGas", Run: estimateGasTest},
-	{Name: "ws/GenesisBlockByHash", Run: genesisBlockByHashTest},
-	{Name: "ws/GenesisBlockByNumber", Run: genesisBlockByNumberTest},
-	{Name: "ws/GenesisHeaderByHash", Run: genesisHeaderByHashTest},
-	{Name: "ws/GenesisHeaderByNumber", Run: genesisHeaderByNumberTest},
-	{Name: "ws/Receipt", Run: receiptTest},
-	{Name: "ws/SyncProgress", Run: syncProgressTest},
-	{Name: "ws/TransactionCount", Run: transactionCountTest},
-	{Name: "ws/TransactionInBlock", Run: transactionInBlockTest},
-	{Name: "ws/TransactionReceipt", Run: TransactionReceiptTest},
-
-	// WebSocket subscription tests.
-	{Name: "ws/NewHeadSubscription", Run: newHeadSubscriptionTest},
-	{Name: "ws/LogSubscription", Run: logSubscriptionTest},
-	{Name: "ws/TransactionInBlockSubscription", Run: transactionInBlockSubscriptionTest},
-
-	// WebSocket ABI tests.
-	{Name: "ws/ABICall", Run: callContractTest},
-	{Name: "ws/ABITransact", Run: transactContractTest},
-}
-
 func main() {
 	suite := hivesim.Suite{
 		Name: "rpc",
@@ -162,10 +107,15 @@ func runLESTests(t *hivesim.T, serverNode *hivesim.Client) {
 // runAllTests runs the tests against a client instance.
 // Most tests simply wait for tx inclusion in a block so we can run many tests concurrently.
 func runAllTests(t *hivesim.T, c *hivesim.Client, clientName string) {
-	vault := newVault()
+	chain := &rpctest.Chain{
+		Genesis:   loadGenesis(),
+		Vault:     newVault(),
+		VaultAddr: predeployedVaultAddr,
+		GasPrice:  gasPrice,
+	}
 
 	s := newSemaphore(16)
-	for _, test := range tests {
+	for _, test := range rpctest.Tests {
 		test := test
 		s.get()
 		go func() {
@@ -174,14 +124,7 @@ func runAllTests(t *hivesim.T, c *hivesim.Client, clientName string) {
 				Name:        fmt.Sprintf("%s (%s)", test.Name, clientName),
 				Description: test.About,
 				Run: func(t *hivesim.T) {
-					switch test.Name[:strings.IndexByte(test.Name, '/')] {
-					case "http":
-						runHTTP(t, c, vault, test.Run)
-					case "ws":
-						runWS(t, c, vault, test.Run)
-					default:
-						panic("bad test prefix in name " + test.Name)
-					}
+					rpctest.Run(t, c, chain, test)
 				},
 			})
 		}()
@@ -1,4 +1,4 @@
-package main
+package rpctest
 
 import (
 	"context"
@@ -17,7 +17,7 @@ import (
 	"github.com/ethereum/hive/simulators/ethereum/rpc/testcontract"
 )
 
-//go:generate abigen -abi ./contractABI.json -pkg testcontract -type Contract -out ./testcontract/contract.go
+//go:generate abigen -abi ../contractABI.json -pkg testcontract -type Contract -out ../testcontract/contract.go
 
 // callContractTest uses the generated ABI binding to call methods in the
 // pre-deployed contract.
@@ -68,11 +68,10 @@ func callContractTest(t *TestEnv) {
 // waits for logs.
 func transactContractTest(t *TestEnv) {
 	var (
-		address = t.Vault.createAccount(t, big.NewInt(params.Ether))
+		address = t.Vault.CreateAccount(t, big.NewInt(params.Ether))
 		nonce   = uint64(0)
 
 		expectedContractAddress = crypto.CreateAddress(address, nonce)
-		gasPrice                = big.NewInt(30 * params.GWei)
 		gasLimit                = uint64(1200000)
 
 		contractABI, _ = abi.JSON(strings.NewReader(predeployedContractABI))
@@ -80,8 +79,8 @@ func transactContractTest(t *TestEnv) {
 		addrArg        = address
 	)
 
-	rawTx := types.NewContractCreation(nonce, big0, gasLimit, gasPrice, deployCode)
-	deployTx, err := t.Vault.signTransaction(address, rawTx)
+	rawTx := types.NewContractCreation(nonce, big0, gasLimit, t.Chain.GasPrice, deployCode)
+	deployTx, err := t.Vault.SignTransaction(address, rawTx)
 	nonce++
 	if err != nil {
 		t.Fatalf("Unable to sign deploy tx: %v", err)
@@ -96,7 +95,7 @@ func transactContractTest(t *TestEnv) {
 
 	// fetch transaction receipt for contract address
 	var contractAddress common.Address
-	receipt, err := waitForTxConfirmations(t, deployTx.Hash(), 5)
+	receipt, err := t.WaitForTxConfirmations(deployTx.Hash(), 5)
 	if err != nil {
 		t.Fatalf("Unable to retrieve receipt: %v", err)
 	}
@@ -114,8 +113,8 @@ func transactContractTest(t *TestEnv) {
 		t.Fatalf("Unable to prepare tx payload: %v", err)
 	}
 
-	eventsTx := types.NewTransaction(nonce, predeployedContractAddr, big0, 500000, gasPrice, payload)
-	tx, err := t.Vault.signTransaction(address, eventsTx)
+	eventsTx := types.NewTransaction(nonce, predeployedContractAddr, big0, 500000, t.Chain.GasPrice, payload)
+	tx, err := t.Vault.SignTransaction(address, eventsTx)
 	nonce++
 	if err != nil {
 		t.Fatalf("Unable to sign deploy tx: %v", err)
@@ -127,7 +126,7 @@ func transactContractTest(t *TestEnv) {
 	t.Logf("Waiting for receipt for events tx %v", tx.Hash())
 
 	// wait for transaction
-	receipt, err = waitForTxConfirmations(t, tx.Hash(), 0)
+	receipt, err = t.WaitForTxConfirmations(tx.Hash(), 0)
 	if err != nil {
 		t.Fatalf("Unable to send transaction to events method: %v", err)
 	}
@@ -153,11 +152,10 @@ func transactContractTest(t *TestEnv) {
 // waits for logs. It uses subscription to track logs.
 func transactContractSubscriptionTest(t *TestEnv) {
 	var (
-		address = t.Vault.createAccountWithSubscription(t, big.NewInt(params.Ether))
+		address = t.Vault.CreateAccount(t, big.NewInt(params.Ether))
 		nonce   = uint64(0)
 
 		expectedContractAddress = crypto.CreateAddress(address, nonce)
-		gasPrice                = big.NewInt(30 * params.GWei)
 		gasLimit                = uint64(1200000)
 
 		contractABI, _ = abi.JSON(strings.NewReader(predeployedContractABI))
@@ -168,8 +166,8 @@ func transactContractSubscriptionTest(t *TestEnv) {
 	)
 
 	// deploy contract
-	rawTx := types.NewContractCreation(nonce, big0, gasLimit, gasPrice, deployCode)
-	deployTx, err := t.Vault.signTransaction(address, rawTx)
+	rawTx := types.NewContractCreation(nonce, big0, gasLimit, t.Chain.GasPrice, deployCode)
+	deployTx, err := t.Vault.SignTransaction(address, rawTx)
 	nonce++
 	if err != nil {
 		t.Fatalf("Unable to sign deploy tx: %v", err)
@@ -183,7 +181,7 @@ func transactContractSubscriptionTest(t *TestEnv) {
 	t.Logf("Deploy ABI Test contract transaction: 0x%x", deployTx.Hash())
 
 	// fetch transaction receipt for contract address
-	receipt, err := waitForTxConfirmations(t, deployTx.Hash(), 5)
+	receipt, err := t.WaitForTxConfirmations(deployTx.Hash(), 5)
 	if err != nil {
 		t.Fatalf("Unable to retrieve receipt: %v", err)
 	}
@@ -217,7 +215,7 @@ func transactContractSubscriptionTest(t *TestEnv) {
 	opts := &bind.TransactOpts{
 		From:   address,
 		Nonce:  new(big.Int).SetUint64(nonce),
-		Signer: t.Vault.signTransaction,
+		Signer: t.Vault.SignTransaction,
 	}
 	tx, err := contract.Events(opts, intArg, addrArg)
 	if err != nil {
@@ -0,0 +1,254 @@
+// Package rpctest contains the JSON-RPC tests of the rpc simulator. The tests are
+// parameterised by the chain they run against, so they can be shared by simulators
+// of other Ethereum based chains.
+package rpctest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/hive/hivesim"
+	"github.com/kr/pretty"
+)
+
+// default timeout for RPC calls
+var rpcTimeout = 10 * time.Second
+
+// Vault creates funded accounts for the tests, and signs their transactions.
+type Vault interface {
+	// CreateAccount creates a new account holding the given amount of wei.
+	// It fails the test when the account could not be funded.
+	CreateAccount(t *TestEnv, amount *big.Int) common.Address
+	// SignTransaction signs tx with the key of a vault account.
+	SignTransaction(sender common.Address, tx *types.Transaction) (*types.Transaction, error)
+}
+
+// Chain describes the chain the tests run against.
+type Chain struct {
+	Genesis *core.Genesis
+	Vault   Vault
+	// VaultAddr receives the value transfers sent by the tests.
+	VaultAddr common.Address
+	// GasPrice is the gas price of the transactions sent by the tests.
+	GasPrice *big.Int
+
+	// WaitReceipt waits until the transaction is included and the given number of
+	// blocks are built on top of it. It defaults to WaitForTxConfirmations.
+	WaitReceipt func(t *TestEnv, txHash common.Hash, n uint64) (*types.Receipt, error)
+}
+
+// TestSpec is a test which runs against the RPC endpoints of a client.
+// The name of the test starts with "http/" or "ws/" to select the endpoint.
+type TestSpec struct {
+	Name  string
+	About string
+	Run   func(*TestEnv)
+}
+
+// TestEnv is the environment of a single test.
+type TestEnv struct {
+	*hivesim.T
+	RPC   *rpc.Client
+	Eth   *ethclient.Client
+	Vault Vault
+	Chain *Chain
+
+	// This holds most recent context created by the Ctx method.
+	// Every time Ctx is called, it creates a new context with the default
+	// timeout and cancels the previous one.
+	lastCtx    context.Context
+	lastCancel context.CancelFunc
+}
+
+// Run runs the test using the endpoint selected by the prefix of its name.
+func Run(t *hivesim.T, c *hivesim.Client, chain *Chain, test TestSpec) {
+	switch test.Name[:strings.IndexByte(test.Name, '/')] {
+	case "http":
+		RunHTTP(t, c, chain, test.Run)
+	case "ws":
+		RunWS(t, c, chain, test.Run)
+	default:
+		panic("bad test prefix in name " + test.Name)
+	}
+}
+
+// RunHTTP runs the given test function using the HTTP RPC client.
+func RunHTTP(t *hivesim.T, c *hivesim.Client, chain *Chain, fn func(*TestEnv)) {
+	// This sets up debug logging of the requests and responses.
+	client := &http.Client{
+		Transport: &loggingRoundTrip{
+			t:     t,
+			inner: http.DefaultTransport,
+		},
+	}
+
+	rpcClient, _ := rpc.DialHTTPWithClient(fmt.Sprintf("http://%v:8545/", c.IP), client)
+	defer rpcClient.Close()
+	env := &TestEnv{
+		T:     t,
+		RPC:   rpcClient,
+		Eth:   ethclient.NewClient(rpcClient),
+		Vault: chain.Vault,
+		Chain: chain,
+	}
+	fn(env)
+	if env.lastCtx != nil {
+		env.lastCancel()
+	}
+}
+
+// RunWS runs the given test function using the WebSocket RPC client.
+func RunWS(t *hivesim.T, c *hivesim.Client, chain *Chain, fn func(*TestEnv)) {
+	ctx, done := context.WithTimeout(context.Background(), 5*time.Second)
+	rpcClient, err := rpc.DialWebsocket(ctx, fmt.Sprintf("ws://%v:8546/", c.IP), "")
+	done()
+	if err != nil {
+		t.Fatal("WebSocket connection failed:", err)
+	}
+	defer rpcClient.Close()
+
+	env := &TestEnv{
+		T:     t,
+		RPC:   rpcClient,
+		Eth:   ethclient.NewClient(rpcClient),
+		Vault: chain.Vault,
+		Chain: chain,
+	}
+	fn(env)
+	if env.lastCtx != nil {
+		env.lastCancel()
+	}
+}
+
+// CallContext is a helper method that forwards a raw RPC request to
+// the underlying RPC client. This can be used to call RPC methods
+// that are not supported by the ethclient.Client.
+func (t *TestEnv) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return t.RPC.CallContext(ctx, result, method, args...)
+}
+
+// Ctx returns a context with the default timeout.
+// For subsequent calls to Ctx, it also cancels the previous context.
+func (t *TestEnv) Ctx() context.Context {
+	if t.lastCtx != nil {
+		t.lastCancel()
+	}
+	t.lastCtx, t.lastCancel = context.WithTimeout(context.Background(), rpcTimeout)
+	return t.lastCtx
+}
+
+// WaitForTxConfirmations waits for the receipt of a transaction and n blocks on
+// top of it, using the WaitReceipt function of the chain if it has one.
+func (t *TestEnv) WaitForTxConfirmations(txHash common.Hash, n uint64) (*types.Receipt, error) {
+	if t.Chain.WaitReceipt != nil {
+		return t.Chain.WaitReceipt(t, txHash, n)
+	}
+	return WaitForTxConfirmations(t, txHash, n)
+}
+
+// WaitForTxConfirmations is a naive generic function that works in all situations.
+// A better solution is to use logs to wait for confirmations.
+func WaitForTxConfirmations(t *TestEnv, txHash common.Hash, n uint64) (*types.Receipt, error) {
+	var (
+		receipt    *types.Receipt
+		startBlock *types.Block
+		err        error
+	)
+
+	for i := 0; i < 90; i++ {
+		receipt, err = t.Eth.TransactionReceipt(t.Ctx(), txHash)
+		if err != nil && err != ethereum.NotFound {
+			return nil, err
+		}
+		if receipt != nil {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	if receipt == nil {
+		return nil, ethereum.NotFound
+	}
+
+	if startBlock, err = t.Eth.BlockByNumber(t.Ctx(), nil); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < 90; i++ {
+		currentBlock, err := t.Eth.BlockByNumber(t.Ctx(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if startBlock.NumberU64()+n >= currentBlock.NumberU64() {
+			if checkReceipt, err := t.Eth.TransactionReceipt(t.Ctx(), txHash); checkReceipt != nil {
+				if bytes.Compare(receipt.PostState, checkReceipt.PostState) == 0 {
+					return receipt, nil
+				} else { // chain reorg
+					WaitForTxConfirmations(t, txHash, n)
+				}
+			} else {
+				return nil, err
+			}
+		}
+
+		time.Sleep(time.Second)
+	}
+
+	return nil, ethereum.NotFound
+}
+
+// loggingRoundTrip writes requests and responses to the test log.
+type loggingRoundTrip struct {
+	t     *hivesim.T
+	inner http.RoundTripper
+}
+
+func (rt *loggingRoundTrip) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Read and log the request body.
+	reqBytes, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	rt.t.Logf(">>  %s", bytes.TrimSpace(reqBytes))
+	reqCopy := *req
+	reqCopy.Body = ioutil.NopCloser(bytes.NewReader(reqBytes))
+
+	// Do the round trip.
+	resp, err := rt.inner.RoundTrip(&reqCopy)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Read and log the response bytes.
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	respCopy := *resp
+	respCopy.Body = ioutil.NopCloser(bytes.NewReader(respBytes))
+	rt.t.Logf("<<  %s", bytes.TrimSpace(respBytes))
+	return &respCopy, nil
+}
+
+// Diff checks whether x and y are deeply equal, returning a description
+// of their differences if they are not equal.
+func Diff(x, y interface{}) (d string) {
+	for _, l := range pretty.Diff(x, y) {
+		d += l + "\n"
+	}
+	return d
+}
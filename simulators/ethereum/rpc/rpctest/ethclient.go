@@ -1,4 +1,4 @@
-package main
+package rpctest
 
 import (
 	"bytes"
@@ -88,7 +88,7 @@ func CodeAtTest(t *TestEnv) {
 // estimateGasTest fetches the estimated gas usage for a call to the events method.
 func estimateGasTest(t *TestEnv) {
 	var (
-		address        = t.Vault.createAccount(t, big.NewInt(params.Ether))
+		address        = t.Vault.CreateAccount(t, big.NewInt(params.Ether))
 		contractABI, _ = abi.JSON(strings.NewReader(predeployedContractABI))
 		intArg         = big.NewInt(rand.Int63())
 	)
@@ -110,7 +110,7 @@ func estimateGasTest(t *TestEnv) {
 	// send the actual tx and test gas usage
 	txGas := estimated + 100000
 	rawTx := types.NewTransaction(0, *msg.To, msg.Value, txGas, big.NewInt(32*params.GWei), msg.Data)
-	tx, err := t.Vault.signTransaction(address, rawTx)
+	tx, err := t.Vault.SignTransaction(address, rawTx)
 	if err != nil {
 		t.Fatalf("Could not sign transaction: %v", err)
 	}
@@ -119,7 +119,7 @@ func estimateGasTest(t *TestEnv) {
 		t.Fatalf("Could not send tx: %v", err)
 	}
 
-	receipt, err := waitForTxConfirmations(t, tx.Hash(), 1)
+	receipt, err := t.WaitForTxConfirmations(tx.Hash(), 1)
 	if err != nil {
 		t.Fatalf("Could not wait for confirmations: %v", err)
 	}
@@ -139,9 +139,9 @@ func estimateGasTest(t *TestEnv) {
 // address are updated correct.
 func balanceAndNonceAtTest(t *TestEnv) {
 	var (
-		sourceAddr  = t.Vault.createAccount(t, big.NewInt(params.Ether))
+		sourceAddr  = t.Vault.CreateAccount(t, big.NewInt(params.Ether))
 		sourceNonce = uint64(0)
-		targetAddr  = t.Vault.createAccount(t, nil)
+		targetAddr  = t.Vault.CreateAccount(t, nil)
 	)
 
 	// Get current balance
@@ -168,8 +168,8 @@ func balanceAndNonceAtTest(t *TestEnv) {
 		amount   = big.NewInt(1234)
 		gasLimit = uint64(50000)
 	)
-	rawTx := types.NewTransaction(sourceNonce, targetAddr, amount, gasLimit, gasPrice, nil)
-	valueTx, err := t.Vault.signTransaction(sourceAddr, rawTx)
+	rawTx := types.NewTransaction(sourceNonce, targetAddr, amount, gasLimit, t.Chain.GasPrice, nil)
+	valueTx, err := t.Vault.SignTransaction(sourceAddr, rawTx)
 	if err != nil {
 		t.Fatalf("Unable to sign value tx: %v", err)
 	}
@@ -202,10 +202,23 @@ func balanceAndNonceAtTest(t *TestEnv) {
 		t.Fatalf("Unable to retrieve balance: %v", err)
 	}
 
-	// expected balance is previous balance - tx amount - tx fee (gasUsed * gasPrice)
+	// expected balance is previous balance - tx amount - tx fee (gasUsed * gasPrice),
+	// where the gas price is the base fee plus the tip paid in the block
+	gasPrice := valueTx.GasPrice()
+	header, err := t.Eth.HeaderByHash(t.Ctx(), receipt.BlockHash)
+	if err != nil {
+		t.Fatalf("Unable to fetch block %x: %v", receipt.BlockHash, err)
+	}
+	if header.BaseFee != nil {
+		tip, err := valueTx.EffectiveGasTip(header.BaseFee)
+		if err != nil {
+			t.Fatalf("Transaction 0x%x doesn't pay the base fee %d: %v", valueTx.Hash(), header.BaseFee, err)
+		}
+		gasPrice = new(big.Int).Add(header.BaseFee, tip)
+	}
 	exp := new(big.Int).Set(sourceAddressBalanceBefore)
 	exp.Sub(exp, amount)
-	exp.Sub(exp, new(big.Int).Mul(big.NewInt(int64(receipt.GasUsed)), valueTx.GasPrice()))
+	exp.Sub(exp, new(big.Int).Mul(big.NewInt(int64(receipt.GasUsed)), gasPrice))
 
 	if exp.Cmp(accountBalanceAfter) != 0 {
 		t.Errorf("Expected sender account to have a balance of %d, got %d", exp, accountBalanceAfter)
@@ -229,13 +242,13 @@ func balanceAndNonceAtTest(t *TestEnv) {
 // it against the genesis file to determine if block fields are
 // returned correct.
 func genesisHeaderByHashTest(t *TestEnv) {
-	gblock := loadGenesis()
+	gblock := t.Chain.Genesis.ToBlock()
 
 	headerByHash, err := t.Eth.HeaderByHash(t.Ctx(), gblock.Hash())
 	if err != nil {
 		t.Fatalf("Unable to fetch block %x: %v", gblock.Hash(), err)
 	}
-	if d := diff(gblock.Header(), headerByHash); d != "" {
+	if d := Diff(gblock.Header(), headerByHash); d != "" {
 		t.Fatal("genesis header reported by node differs from expected header:\n", d)
 	}
 }
@@ -244,13 +257,13 @@ func genesisHeaderByHashTest(t *TestEnv) {
 // it against the genesis file to determine if block fields are
 // returned correct.
 func genesisHeaderByNumberTest(t *TestEnv) {
-	gblock := loadGenesis()
+	gblock := t.Chain.Genesis.ToBlock()
 
 	headerByNum, err := t.Eth.HeaderByNumber(t.Ctx(), big0)
 	if err != nil {
 		t.Fatalf("Unable to fetch genesis block: %v", err)
 	}
-	if d := diff(gblock.Header(), headerByNum); d != "" {
+	if d := Diff(gblock.Header(), headerByNum); d != "" {
 		t.Fatal("genesis header reported by node differs from expected header:\n", d)
 	}
 }
@@ -258,13 +271,13 @@ func genesisHeaderByNumberTest(t *TestEnv) {
 // genesisBlockByHashTest fetched the known genesis block and compares it against
 // the genesis file to determine if block fields are returned correct.
 func genesisBlockByHashTest(t *TestEnv) {
-	gblock := loadGenesis()
+	gblock := t.Chain.Genesis.ToBlock()
 
 	blockByHash, err := t.Eth.BlockByHash(t.Ctx(), gblock.Hash())
 	if err != nil {
 		t.Fatalf("Unable to fetch block %x: %v", gblock.Hash(), err)
 	}
-	if d := diff(gblock.Header(), blockByHash.Header()); d != "" {
+	if d := Diff(gblock.Header(), blockByHash.Header()); d != "" {
 		t.Fatal("genesis header reported by node differs from expected header:\n", d)
 	}
 }
@@ -273,13 +286,13 @@ func genesisBlockByHashTest(t *TestEnv) {
 // that is known through the genesis.json file and tests if block
 // fields matches the fields defined in the genesis file.
 func genesisBlockByNumberTest(t *TestEnv) {
-	gblock := loadGenesis()
+	gblock := t.Chain.Genesis.ToBlock()
 
 	blockByNum, err := t.Eth.BlockByNumber(t.Ctx(), big0)
 	if err != nil {
 		t.Fatalf("Unable to fetch genesis block: %v", err)
 	}
-	if d := diff(gblock.Header(), blockByNum.Header()); d != "" {
+	if d := Diff(gblock.Header(), blockByNum.Header()); d != "" {
 		t.Fatal("genesis header reported by node differs from expected header:\n", d)
 	}
 }
@@ -353,15 +366,15 @@ func canonicalChainTest(t *TestEnv) {
 // on the contract address contain the expected values (as set in the ctor).
 func deployContractTest(t *TestEnv) {
 	var (
-		address = t.Vault.createAccount(t, big.NewInt(params.Ether))
+		address = t.Vault.CreateAccount(t, big.NewInt(params.Ether))
 		nonce   = uint64(0)
 
 		expectedContractAddress = crypto.CreateAddress(address, nonce)
 		gasLimit                = uint64(1200000)
 	)
 
-	rawTx := types.NewContractCreation(nonce, big0, gasLimit, gasPrice, deployCode)
-	deployTx, err := t.Vault.signTransaction(address, rawTx)
+	rawTx := types.NewContractCreation(nonce, big0, gasLimit, t.Chain.GasPrice, deployCode)
+	deployTx, err := t.Vault.SignTransaction(address, rawTx)
 	if err != nil {
 		t.Fatalf("Unable to sign deploy tx: %v", err)
 	}
@@ -375,7 +388,7 @@ func deployContractTest(t *TestEnv) {
 
 	// fetch transaction receipt for contract address
 	var contractAddress common.Address
-	receipt, err := waitForTxConfirmations(t, deployTx.Hash(), 5)
+	receipt, err := t.WaitForTxConfirmations(deployTx.Hash(), 5)
 	if err != nil {
 		t.Fatalf("Unable to retrieve receipt: %v", err)
 	}
@@ -427,7 +440,7 @@ func deployContractTest(t *TestEnv) {
 // the contract address.
 func deployContractOutOfGasTest(t *TestEnv) {
 	var (
-		address         = t.Vault.createAccount(t, big.NewInt(params.Ether))
+		address         = t.Vault.CreateAccount(t, big.NewInt(params.Ether))
 		nonce           = uint64(0)
 		contractAddress = crypto.CreateAddress(address, nonce)
 		gasLimit        = uint64(240000) // insufficient gas
@@ -435,8 +448,8 @@ func deployContractOutOfGasTest(t *TestEnv) {
 	t.Logf("calculated contract address: %x", contractAddress)
 
 	// Deploy the contract.
-	rawTx := types.NewContractCreation(nonce, big0, gasLimit, gasPrice, deployCode)
-	deployTx, err := t.Vault.signTransaction(address, rawTx)
+	rawTx := types.NewContractCreation(nonce, big0, gasLimit, t.Chain.GasPrice, deployCode)
+	deployTx, err := t.Vault.SignTransaction(address, rawTx)
 	if err != nil {
 		t.Fatalf("unable to sign deploy tx: %v", err)
 	}
@@ -446,7 +459,7 @@ func deployContractOutOfGasTest(t *TestEnv) {
 	}
 
 	// Wait for the transaction receipt.
-	receipt, err := waitForTxConfirmations(t, deployTx.Hash(), 5)
+	receipt, err := t.WaitForTxConfirmations(deployTx.Hash(), 5)
 	if err != nil {
 		t.Fatalf("unable to fetch tx receipt: %v", err)
 	}
@@ -478,7 +491,7 @@ func deployContractOutOfGasTest(t *TestEnv) {
 func receiptTest(t *TestEnv) {
 	var (
 		contractABI, _ = abi.JSON(strings.NewReader(predeployedContractABI))
-		address        = t.Vault.createAccount(t, big.NewInt(params.Ether))
+		address        = t.Vault.CreateAccount(t, big.NewInt(params.Ether))
 		nonce          = uint64(0)
 
 		intArg = big.NewInt(rand.Int63())
@@ -489,8 +502,8 @@ func receiptTest(t *TestEnv) {
 		t.Fatalf("Unable to prepare tx payload: %v", err)
 	}
 
-	rawTx := types.NewTransaction(nonce, predeployedContractAddr, big0, 500000, gasPrice, payload)
-	tx, err := t.Vault.signTransaction(address, rawTx)
+	rawTx := types.NewTransaction(nonce, predeployedContractAddr, big0, 500000, t.Chain.GasPrice, payload)
+	tx, err := t.Vault.SignTransaction(address, rawTx)
 	if err != nil {
 		t.Fatalf("Unable to sign deploy tx: %v", err)
 	}
@@ -500,7 +513,7 @@ func receiptTest(t *TestEnv) {
 	}
 
 	// wait for transaction
-	receipt, err := waitForTxConfirmations(t, tx.Hash(), 0)
+	receipt, err := t.WaitForTxConfirmations(tx.Hash(), 0)
 	if err != nil {
 		t.Fatalf("Unable to retrieve tx receipt: %v", err)
 	}
@@ -588,7 +601,7 @@ func syncProgressTest(t *TestEnv) {
 // and retrieves transaction details by block hash and position.
 func transactionInBlockTest(t *TestEnv) {
 	var (
-		key         = t.Vault.createAccount(t, big.NewInt(params.Ether))
+		key         = t.Vault.CreateAccount(t, big.NewInt(params.Ether))
 		nonce       = uint64(0)
 		blockNumber = new(big.Int)
 	)
@@ -598,10 +611,10 @@ func transactionInBlockTest(t *TestEnv) {
 
 		block, err := t.Eth.BlockByNumber(t.Ctx(), blockNumber)
 		if err == ethereum.NotFound { // end of chain
-			rawTx := types.NewTransaction(nonce, predeployedVaultAddr, big1, 100000, gasPrice, nil)
+			rawTx := types.NewTransaction(nonce, t.Chain.VaultAddr, big1, 100000, t.Chain.GasPrice, nil)
 			nonce++
 
-			tx, err := t.Vault.signTransaction(key, rawTx)
+			tx, err := t.Vault.SignTransaction(key, rawTx)
 			if err != nil {
 				t.Fatalf("Unable to sign deploy tx: %v", err)
 			}
@@ -637,10 +650,10 @@ func transactionInBlockSubscriptionTest(t *TestEnv) {
 		t.Fatalf("Unable to subscribe to new heads: %v", err)
 	}
 
-	key := t.Vault.createAccount(t, big.NewInt(params.Ether))
+	key := t.Vault.CreateAccount(t, big.NewInt(params.Ether))
 	for i := 0; i < 5; i++ {
-		rawTx := types.NewTransaction(uint64(i), predeployedVaultAddr, big1, 100000, gasPrice, nil)
-		tx, err := t.Vault.signTransaction(key, rawTx)
+		rawTx := types.NewTransaction(uint64(i), t.Chain.VaultAddr, big1, 100000, t.Chain.GasPrice, nil)
+		tx, err := t.Vault.SignTransaction(key, rawTx)
 		if err != nil {
 			t.Fatalf("Unable to sign deploy tx: %v", err)
 		}
@@ -716,7 +729,7 @@ func logSubscriptionTest(t *TestEnv) {
 
 	var (
 		contractABI, _ = abi.JSON(strings.NewReader(predeployedContractABI))
-		address        = t.Vault.createAccount(t, big.NewInt(params.Ether))
+		address        = t.Vault.CreateAccount(t, big.NewInt(params.Ether))
 		nonce          = uint64(0)
 
 		arg0 = big.NewInt(rand.Int63())
@@ -724,8 +737,8 @@ func logSubscriptionTest(t *TestEnv) {
 	)
 
 	payload, _ := contractABI.Pack("events", arg0, arg1)
-	rawTx := types.NewTransaction(nonce, predeployedContractAddr, big0, 500000, gasPrice, payload)
-	tx, err := t.Vault.signTransaction(address, rawTx)
+	rawTx := types.NewTransaction(nonce, predeployedContractAddr, big0, 500000, t.Chain.GasPrice, payload)
+	tx, err := t.Vault.SignTransaction(address, rawTx)
 	if err != nil {
 		t.Fatalf("Unable to sign deploy tx: %v", err)
 	}
@@ -793,12 +806,12 @@ func validatePredeployContractLogs(t *TestEnv, tx *types.Transaction, logs []typ
 
 func transactionCountTest(t *TestEnv) {
 	var (
-		key = t.Vault.createAccount(t, big.NewInt(params.Ether))
+		key = t.Vault.CreateAccount(t, big.NewInt(params.Ether))
 	)
 
 	for i := 0; i < 60; i++ {
-		rawTx := types.NewTransaction(uint64(i), predeployedVaultAddr, big1, 100000, gasPrice, nil)
-		tx, err := t.Vault.signTransaction(key, rawTx)
+		rawTx := types.NewTransaction(uint64(i), t.Chain.VaultAddr, big1, 100000, t.Chain.GasPrice, nil)
+		tx, err := t.Vault.SignTransaction(key, rawTx)
 		if err != nil {
 			t.Fatalf("Unable to sign deploy tx: %v", err)
 		}
@@ -829,11 +842,11 @@ func transactionCountTest(t *TestEnv) {
 // TransactionReceiptTest sends a transaction and tests the receipt fields.
 func TransactionReceiptTest(t *TestEnv) {
 	var (
-		key = t.Vault.createAccount(t, big.NewInt(params.Ether))
+		key = t.Vault.CreateAccount(t, big.NewInt(params.Ether))
 	)
 
-	rawTx := types.NewTransaction(uint64(0), common.Address{}, big1, 100000, gasPrice, nil)
-	tx, err := t.Vault.signTransaction(key, rawTx)
+	rawTx := types.NewTransaction(uint64(0), common.Address{}, big1, 100000, t.Chain.GasPrice, nil)
+	tx, err := t.Vault.SignTransaction(key, rawTx)
 	if err != nil {
 		t.Fatalf("Unable to sign deploy tx: %v", err)
 	}
@@ -14,6 +14,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/hive/simulators/ethereum/rpc/rpctest"
 )
 
 var (
@@ -27,7 +28,7 @@ var (
 )
 
 // vault creates accounts for testing and funds them. An instance of the vault contract is
-// deployed in the genesis block. When creating a new account using createAccount, the
+// deployed in the genesis block. When creating a new account using CreateAccount, the
 // account is funded by sending a transaction to this contract.
 //
 // The purpose of the vault is allowing tests to run concurrently without worrying about
@@ -67,9 +68,9 @@ func (v *vault) findKey(addr common.Address) *ecdsa.PrivateKey {
 	return v.accounts[addr]
 }
 
-// signTransaction signs the given transaction with the test account and returns it.
+// SignTransaction signs the given transaction with the test account and returns it.
 // It uses the EIP155 signing rules.
-func (v *vault) signTransaction(sender common.Address, tx *types.Transaction) (*types.Transaction, error) {
+func (v *vault) SignTransaction(sender common.Address, tx *types.Transaction) (*types.Transaction, error) {
 	key := v.findKey(sender)
 	if key == nil {
 		return nil, fmt.Errorf("sender account %v not in vault", sender)
@@ -80,7 +81,7 @@ func (v *vault) signTransaction(sender common.Address, tx *types.Transaction) (*
 
 // createAndFundAccount creates a new account that is funded from the vault contract.
 // It will panic when the account could not be created and funded.
-func (v *vault) createAccountWithSubscription(t *TestEnv, amount *big.Int) common.Address {
+func (v *vault) createAccountWithSubscription(t *rpctest.TestEnv, amount *big.Int) common.Address {
 	if amount == nil {
 		amount = new(big.Int)
 	}
@@ -159,9 +160,9 @@ func (v *vault) createAccountWithSubscription(t *TestEnv, amount *big.Int) commo
 	return address
 }
 
-// createAccount creates a new account that is funded from the vault contract.
+// CreateAccount creates a new account that is funded from the vault contract.
 // It will panic when the account could not be created and funded.
-func (v *vault) createAccount(t *TestEnv, amount *big.Int) common.Address {
+func (v *vault) CreateAccount(t *rpctest.TestEnv, amount *big.Int) common.Address {
 	if amount == nil {
 		amount = new(big.Int)
 	}
@@ -200,7 +201,7 @@ func (v *vault) createAccount(t *TestEnv, amount *big.Int) common.Address {
 	panic(fmt.Sprintf("could not fund account %v in transaction %v", address, tx.Hash()))
 }
 
-func (v *vault) makeFundingTx(t *TestEnv, recipient common.Address, amount *big.Int) *types.Transaction {
+func (v *vault) makeFundingTx(t *rpctest.TestEnv, recipient common.Address, amount *big.Int) *types.Transaction {
 	vault, _ := abi.JSON(strings.NewReader(predeployedVaultABI))
 	payload, err := vault.Pack("sendSome", recipient, amount)
 	if err != nil {
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/hive/hivesim"
+	"github.com/ethereum/hive/taiko"
+	"github.com/stretchr/testify/require"
+)
+
+var tests = []*taiko.MatrixTest{
+	{
+		Name:        "Send ETH from L1 to L2",
+		Description: "Sends ether through the L1 token vault and processes the message on L2.",
+		Run:         sendEtherL1ToL2,
+	},
+	{
+		Name:        "Send ERC20 from L2 to L1",
+		Description: "Sends the L2 test token through the L2 token vault and processes the message on L1.",
+		Run:         sendERC20L2ToL1,
+	},
+	{
+		Name:        "Retry and release failed message",
+		Description: "A message whose call fails on L2 becomes retriable, then fails, and its ether is released on L1.",
+		Run:         retryAndReleaseFailedMessage,
+	},
+}
+
+func main() {
+	suite := hivesim.Suite{
+		Name:        "taiko-bridge",
+		Description: "Tests of the bridge and token vault contracts between L1 and L2.",
+	}
+	suite.Add(&hivesim.TestSpec{
+		Name: "Client launch",
+		Description: `This test launches the client and runs the test tool.
+		Results from the test tool are reported as individual sub-tests.`,
+		Run: func(t *hivesim.T) { runAllTests(t) },
+	})
+	sim := hivesim.New()
+	hivesim.MustRun(sim, suite)
+}
+
+var (
+	defaultTimeout = 10 * time.Minute
+	// gas limit of processing a message on the destination chain. Since it is
+	// non-zero, any account can process the message.
+	messageGasLimit = big.NewInt(1000000)
+	accountFunding  = new(big.Int).Mul(big.NewInt(10), big.NewInt(params.Ether))
+)
+
+func sendEtherL1ToL2(t *hivesim.T, clients *taiko.ClientsByRole) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartDevnet(devnetSpec(env))
+	defer env.StopDevnet()
+
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	l1Cli, l2Cli := l1.MustEthClient(t), l2.MustEthClient(t)
+	sender := env.L1Vault.CreateAccount(ctx, l1Cli, accountFunding)
+	// Only used to process the message when there is no relayer client.
+	relayer := env.L2Vault.CreateAccount(ctx, l2Cli, accountFunding)
+
+	// send
+	amount := big.NewInt(params.Ether)
+	vault, err := l1.TokenVaultClient()
+	require.NoError(t, err)
+	opts := env.L1Vault.KeyedTransactor(sender)
+	opts.Value = amount
+	tx, err := vault.SendEther(opts, env.Conf.L2.ChainID, sender, messageGasLimit, common.Big0, sender, "")
+	require.NoError(t, err)
+	receipt, err := taiko.WaitReceiptOK(ctx, l1Cli, tx.Hash())
+	require.NoError(t, err)
+	srcBridge, err := l1.BridgeClient()
+	require.NoError(t, err)
+	msg, msgHash, err := srcBridge.SentMessage(receipt)
+	require.NoError(t, err)
+	require.Equal(t, 0, amount.Cmp(msg.DepositValue))
+	sent, err := srcBridge.IsMessageSent(ctx, msgHash)
+	require.NoError(t, err)
+	require.True(t, sent)
+
+	processMessage(t, env, l1, l2, env.L2Vault, relayer, msg, msgHash, receipt.BlockNumber.Uint64(), taiko.MessageStatusDone)
+	balance, err := l2Cli.BalanceAt(ctx, sender, nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, amount.Cmp(balance), "L2 balance %v, want %v", balance, amount)
+}
+
+func sendERC20L2ToL1(t *hivesim.T, clients *taiko.ClientsByRole) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartDevnet(devnetSpec(env))
+	defer env.StopDevnet()
+
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	l1Cli, l2Cli := l1.MustEthClient(t), l2.MustEthClient(t)
+	sender := env.L2Vault.CreateAccount(ctx, l2Cli, accountFunding)
+	// Only used to process the message when there is no relayer client.
+	relayer := env.L1Vault.CreateAccount(ctx, l1Cli, accountFunding)
+
+	// The test token is minted to the deployer in the L2 genesis.
+	token := l2.TestERC20Address()
+	amount := big.NewInt(100)
+	require.NoError(t, env.L2Vault.TransferERC20(ctx, l2Cli, token, sender, amount))
+
+	// send
+	vault, err := l2.TokenVaultClient()
+	require.NoError(t, err)
+	erc20, err := l2.ERC20Client(token)
+	require.NoError(t, err)
+	tx, err := erc20.Approve(env.L2Vault.KeyedTransactor(sender), vault.Address, amount)
+	require.NoError(t, err)
+	_, err = taiko.WaitReceiptOK(ctx, l2Cli, tx.Hash())
+	require.NoError(t, err)
+	tx, err = vault.SendERC20(env.L2Vault.KeyedTransactor(sender), env.Conf.L1.ChainID, sender, token, amount, messageGasLimit, common.Big0, sender, "")
+	require.NoError(t, err)
+	receipt, err := taiko.WaitReceiptOK(ctx, l2Cli, tx.Hash())
+	require.NoError(t, err)
+	srcBridge, err := l2.BridgeClient()
+	require.NoError(t, err)
+	msg, msgHash, err := srcBridge.SentMessage(receipt)
+	require.NoError(t, err)
+
+	// L2 blocks become known to L1 once they are verified.
+	processMessage(t, env, l2, l1, env.L1Vault, relayer, msg, msgHash, receipt.BlockNumber.Uint64(), taiko.MessageStatusDone)
+
+	dstVault, err := l1.TokenVaultClient()
+	require.NoError(t, err)
+	bridged, err := dstVault.CanonicalToBridged(ctx, env.Conf.L2.ChainID, token)
+	require.NoError(t, err)
+	require.NotEqual(t, common.Address{}, bridged, "bridged token not deployed")
+	bridgedERC20, err := l1.ERC20Client(bridged)
+	require.NoError(t, err)
+	balance, err := bridgedERC20.BalanceOf(ctx, sender)
+	require.NoError(t, err)
+	require.Equal(t, 0, amount.Cmp(balance), "bridged token balance %v, want %v", balance, amount)
+}
+
+func retryAndReleaseFailedMessage(t *hivesim.T, clients *taiko.ClientsByRole) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartDevnet(devnetSpec(env))
+	defer env.StopDevnet()
+
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	l1Cli, l2Cli := l1.MustEthClient(t), l2.MustEthClient(t)
+	owner := env.L1Vault.CreateAccount(ctx, l1Cli, accountFunding)
+	// The owner retries the message on L2, so it needs funds there too.
+	env.L2Vault.InsertKey(env.L1Vault.FindKey(owner))
+	require.NoError(t, env.L2Vault.FundAccount(ctx, l2Cli, owner, accountFunding))
+
+	// The message calls the test token with invalid calldata, which always reverts.
+	callValue := big.NewInt(params.Ether)
+	msg := taiko.BridgeMessage{
+		Id:            common.Big0,
+		Sender:        owner,
+		SrcChainId:    env.Conf.L1.ChainID,
+		DestChainId:   env.Conf.L2.ChainID,
+		Owner:         owner,
+		To:            l2.TestERC20Address(),
+		RefundAddress: owner,
+		DepositValue:  common.Big0,
+		CallValue:     callValue,
+		ProcessingFee: common.Big0,
+		GasLimit:      messageGasLimit,
+		Data:          []byte{0xde, 0xad, 0xbe, 0xef},
+	}
+	srcBridge, err := l1.BridgeClient()
+	require.NoError(t, err)
+	opts := env.L1Vault.KeyedTransactor(owner)
+	opts.Value = callValue
+	tx, err := srcBridge.SendMessage(opts, msg)
+	require.NoError(t, err)
+	receipt, err := taiko.WaitReceiptOK(ctx, l1Cli, tx.Hash())
+	require.NoError(t, err)
+	sentMsg, msgHash, err := srcBridge.SentMessage(receipt)
+	require.NoError(t, err)
+
+	// The first processing attempt fails, and the message becomes retriable.
+	processMessage(t, env, l1, l2, env.L2Vault, owner, sentMsg, msgHash, receipt.BlockNumber.Uint64(), taiko.MessageStatusRetriable)
+
+	// The last retry fails too, which marks the message as failed.
+	dstBridge, err := l2.BridgeClient()
+	require.NoError(t, err)
+	tx, err = dstBridge.RetryMessage(env.L2Vault.KeyedTransactor(owner), *sentMsg, true)
+	require.NoError(t, err)
+	receipt, err = taiko.WaitReceiptOK(ctx, l2Cli, tx.Hash())
+	require.NoError(t, err)
+	require.NoError(t, taiko.WaitMessageStatus(ctx, dstBridge, msgHash, taiko.MessageStatusFailed))
+
+	// Release the ether on L1 using the proof of failure.
+	waitHeaderSynced(t, env, l1, receipt.BlockNumber.Uint64())
+	proof, err := taiko.FailureProof(ctx, l1, l2, msgHash)
+	require.NoError(t, err)
+	before, err := l1Cli.BalanceAt(ctx, owner, nil)
+	require.NoError(t, err)
+	tx, err = srcBridge.ReleaseEther(env.L1Vault.KeyedTransactor(owner), *sentMsg, proof)
+	require.NoError(t, err)
+	receipt, err = taiko.WaitReceiptOK(ctx, l1Cli, tx.Hash())
+	require.NoError(t, err)
+	after, err := l1Cli.BalanceAt(ctx, owner, nil)
+	require.NoError(t, err)
+
+	want := new(big.Int).Add(before, callValue)
+	want.Sub(want, txFee(t, ctx, l1Cli, tx, receipt))
+	require.Equal(t, 0, want.Cmp(after), "L1 balance after release %v, want %v", after, want)
+}
+
+// devnetSpec returns the devnet of the tests. A relayer is started when the relayer
+// client is available, otherwise the tests process messages themselves.
+func devnetSpec(env *taiko.TestEnv) *taiko.DevnetSpec {
+	spec := taiko.SingleNodeSpec()
+	spec.Relayer = env.Clients.Relayer != nil
+	return spec
+}
+
+// processMessage waits until the message sent on src reaches the want status on dst.
+// Without a relayer client, the message is processed by the processor account of
+// the dst vault.
+func processMessage(t *hivesim.T, env *taiko.TestEnv, src, dst *taiko.ELNode, vault *taiko.Vault, processor common.Address,
+	msg *taiko.BridgeMessage, msgHash common.Hash, srcHeight uint64, want taiko.MessageStatus) {
+	ctx := env.Context
+	dstBridge, err := dst.BridgeClient()
+	require.NoError(t, err)
+
+	waitHeaderSynced(t, env, dst, srcHeight)
+	if env.Clients.Relayer == nil {
+		proof, err := taiko.SignalProof(ctx, src, dst, msgHash)
+		require.NoError(t, err)
+		tx, err := dstBridge.ProcessMessage(vault.KeyedTransactor(processor), *msg, proof)
+		require.NoError(t, err)
+		_, err = taiko.WaitReceiptOK(ctx, dst.MustEthClient(t), tx.Hash())
+		require.NoError(t, err)
+		require.NoError(t, taiko.WaitMessageStatus(ctx, dstBridge, msgHash, want))
+		return
+	}
+	// The relayer waits for confirmations of the source chain, keep producing blocks
+	// until it has processed the message.
+	for {
+		status, err := dstBridge.MessageStatus(ctx, msgHash)
+		require.NoError(t, err)
+		if status == want {
+			return
+		}
+		if status != taiko.MessageStatusNew {
+			t.Fatalf("message %v has status %v, want %v", msgHash, status, want)
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("message %v not processed by relayer", msgHash)
+		default:
+		}
+		env.GenSomeL2Blocks(t, 1)
+	}
+}
+
+// waitHeaderSynced proposes L2 blocks until the block at srcHeight of the other chain
+// is known on dst. L2 learns about L1 blocks through the anchor transaction, L1 learns
+// about L2 blocks when they are verified.
+func waitHeaderSynced(t *hivesim.T, env *taiko.TestEnv, dst *taiko.ELNode, srcHeight uint64) {
+	ctx := env.Context
+	for {
+		synced, err := taiko.LatestSyncedHeight(ctx, dst)
+		require.NoError(t, err)
+		if synced >= srcHeight {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("header %d not synced, latest synced height %d", srcHeight, synced)
+		default:
+		}
+		env.GenSomeL2Blocks(t, 1)
+	}
+}
+
+// txFee returns the amount paid by the sender of tx for gas.
+func txFee(t *hivesim.T, ctx context.Context, cli *ethclient.Client, tx *types.Transaction, receipt *types.Receipt) *big.Int {
+	price := tx.GasPrice()
+	if tx.Type() == types.DynamicFeeTxType {
+		header, err := cli.HeaderByHash(ctx, receipt.BlockHash)
+		require.NoError(t, err)
+		price = new(big.Int).Add(tx.EffectiveGasTipValue(header.BaseFee), header.BaseFee)
+	}
+	return new(big.Int).Mul(price, new(big.Int).SetUint64(receipt.GasUsed))
+}
+
+func runAllTests(t *hivesim.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+	taiko.RunTests(t, ctx, &taiko.RunTestsParams{
+		MatrixTests: tests,
+		Concurrency: 3,
+	})
+}
@@ -7,9 +7,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/hive/hivesim"
@@ -21,7 +23,7 @@ import (
 	"github.com/taikoxyz/taiko-client/testutils"
 )
 
-var tests = []*hivesim.TestSpec{
+var tests = []*taiko.MatrixTest{
 	{
 		Name:        "Generate the first taiko block",
 		Description: "Tests related to the first generated taiko block",
@@ -47,6 +49,21 @@ var tests = []*hivesim.TestSpec{
 		Description: "Commits and proposes a validly encoded transaction list which including an invalid transaction.",
 		Run:         proposeTxListIncludingInvalidTx,
 	},
+	{
+		Name:        "L2 network partitions",
+		Description: "L2 nodes are partitioned from their peers while the chain advances, and catch up through p2p or L1 derivation.",
+		Run:         l2Partitions,
+	},
+	{
+		Name:        "Multiple proposers and L2 nodes",
+		Description: "Several proposers compete for proposing L2 blocks, while the L2 nodes sync through p2p and L1.",
+		Run:         multiProposers,
+	},
+	{
+		Name:        "Protocol parameter matrix",
+		Description: "Deploys the L1 contracts with different protocol parameters, and checks that blocks are proposed and verified with each of them.",
+		Run:         protocolMatrix,
+	},
 	{
 		Name:        "Failed to propose by ws because there are too many pending transaction",
 		Description: "Total size of pending transactions affects the execution of propose, connected with taiko-geth by ws rpc will fail, when by http will success.",
@@ -71,10 +88,10 @@ func main() {
 
 var defaultTimeout = 10 * time.Minute
 
-func firstTaikoBlock(t *hivesim.T) {
+func firstTaikoBlock(t *hivesim.T, clients *taiko.ClientsByRole) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*defaultTimeout)
 	defer cancel()
-	env := taiko.NewTestEnv(ctx, t)
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
 	env.StartSingleNodeNet()
 	defer env.StopSingleNodeNet()
 
@@ -110,16 +127,16 @@ func VerifyFirstTaikoBlockOnL1(env *taiko.TestEnv) func(*hivesim.T) {
 		blockHash, err := taiko.GetBlockHashByNumber(ctx, l2, common.Big1, true)
 		require.NoError(t, err)
 		require.NoError(t, taiko.WaitProveEvent(ctx, l1, blockHash))
-		require.NoError(t, taiko.WaitStateChange(l1, func(psv *bindings.LibUtilsStateVariables) bool {
+		require.NoError(t, taiko.WaitStateChange(ctx, l1, func(psv *bindings.LibUtilsStateVariables) bool {
 			return psv.LatestVerifiedHeight == 1
 		}))
 	}
 }
 
-func syncTaikoBlock(t *hivesim.T) {
+func syncTaikoBlock(t *hivesim.T, clients *taiko.ClientsByRole) {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 	defer cancel()
-	env := taiko.NewTestEnv(ctx, t)
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
 	env.StartSingleNodeNet()
 	defer env.StopSingleNodeNet()
 
@@ -213,16 +230,291 @@ func crossSync(env *taiko.TestEnv) func(*hivesim.T) {
 		env.NewDriverNode(d.GetL1ELNode(0), newL2, taiko.WithEnableL2P2P())
 		heightOfResume := heightOfOneByOne + blockCnt
 		require.NoError(t, taiko.WaitHeight(ctx, newL2, taiko.GreaterEqual(heightOfResume)))
+		// 6. newL2 must have the same chain as the devnet L2 node
+		l2s := []*taiko.ELNode{d.GetL2ELNode(0), newL2}
+		require.NoError(t, taiko.CheckL2Consistency(ctx, d.GetL1ELNode(0), l2s, heightOfResume))
+	}
+}
+
+func l2Partitions(t *hivesim.T, clients *taiko.ClientsByRole) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartSingleNodeNet()
+	defer env.StopSingleNodeNet()
+
+	// The followers are peered with the devnet L2 node over their own network, so they
+	// can be partitioned while the simulator still reaches them.
+	network := env.NewL2Network("taiko-l2")
+	defer network.Remove()
+	require.NoError(t, network.Join(ctx, env.Net.GetL2ELNode(0)))
+	env.GenSomeL2Blocks(t, 3)
+
+	t.Run(hivesim.TestSpec{
+		Name:        "Catch up by p2p after rejoining",
+		Description: "A follower is partitioned and its driver stopped while the chain advances. After rejoining, the restarted driver syncs the verified blocks through p2p.",
+		Run:         rejoinSyncByP2P(env, network),
+	})
+	t.Run(hivesim.TestSpec{
+		Name:        "Catch up by L1 derivation while partitioned",
+		Description: "A follower without p2p sync keeps deriving the chain from L1 while it is partitioned from its peers.",
+		Run:         partitionedSyncByL1(env, network),
+	})
+	t.Run(hivesim.TestSpec{
+		Name:        "Fall back to L1 without peers",
+		Description: "A driver with p2p sync is restarted while its L2 node has no peers. Beacon sync makes no progress, the driver falls back to L1 derivation.",
+		Run:         fallbackWithoutPeers(env, network),
+	})
+	t.Run(hivesim.TestSpec{
+		Name:        "Fall back to L1 with a conflicting peer",
+		Description: "The only peer of a new follower serves an L2 chain derived from a forked L1 chain. The follower must not adopt it, and derives the devnet chain from L1.",
+		Run:         conflictingPeer(env),
+	})
+}
+
+// startFollower starts a full-sync L2 node peered over network, and its driver.
+func startFollower(env *taiko.TestEnv, network *taiko.L2Network, p2p bool) (*taiko.ELNode, *taiko.Node) {
+	t := env.T
+	l2 := env.NewFullSyncL2ELNode()
+	require.NoError(t, network.Join(env.Context, l2))
+	return l2, startDriver(env, l2, p2p)
+}
+
+func startDriver(env *taiko.TestEnv, l2 *taiko.ELNode, p2p bool) *taiko.Node {
+	var opts []taiko.NodeOption
+	if p2p {
+		opts = append(opts, taiko.WithEnableL2P2P())
+	}
+	return env.NewDriverNode(env.Net.GetL1ELNode(0), l2, opts...)
+}
+
+// waitCaughtUp waits until the follower has the chain of the devnet L2 node up to its
+// current head, and returns the head.
+func waitCaughtUp(t *hivesim.T, env *taiko.TestEnv, follower *taiko.ELNode) uint64 {
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	head := l2Head(t, env, l2)
+	require.NoError(t, taiko.CheckL2Consistency(env.Context, l1, []*taiko.ELNode{l2, follower}, head))
+	return head
+}
+
+// generateVerifiedBlocks creates cnt L2 blocks, and waits until they are verified.
+func generateVerifiedBlocks(t *hivesim.T, env *taiko.TestEnv, cnt uint64) uint64 {
+	env.GenSomeL2Blocks(t, cnt)
+	head := l2Head(t, env, env.Net.GetL2ELNode(0))
+	require.NoError(t, taiko.WaitStateChange(env.Context, env.Net.GetL1ELNode(0), func(psv *bindings.LibUtilsStateVariables) bool {
+		return psv.LatestVerifiedHeight >= head
+	}))
+	return head
+}
+
+func rejoinSyncByP2P(env *taiko.TestEnv, network *taiko.L2Network) func(*hivesim.T) {
+	return func(t *hivesim.T) {
+		ctx := env.Context
+		follower, driver := startFollower(env, network, true)
+		before := waitCaughtUp(t, env, follower)
+
+		require.NoError(t, network.Partition(ctx, follower))
+		require.NoError(t, t.Sim.StopClient(t.SuiteID, t.TestID, driver.Container))
+		verified := generateVerifiedBlocks(t, env, 10)
+
+		require.NoError(t, network.Rejoin(ctx, follower))
+		startDriver(env, follower, true)
+		waitCaughtUp(t, env, follower)
+		// Blocks synced through p2p have no L1 origin on the follower.
+		synced := blocksWithoutL1Origin(t, env, follower, before+1, verified)
+		t.Logf("%d of the blocks %d-%d synced through p2p", synced, before+1, verified)
+		require.NotZero(t, synced, "no verified block synced through p2p")
+	}
+}
+
+func partitionedSyncByL1(env *taiko.TestEnv, network *taiko.L2Network) func(*hivesim.T) {
+	return func(t *hivesim.T) {
+		ctx := env.Context
+		follower, _ := startFollower(env, network, false)
+		waitCaughtUp(t, env, follower)
+
+		require.NoError(t, network.Partition(ctx, follower))
+		env.GenSomeL2Blocks(t, 10)
+		waitCaughtUp(t, env, follower)
+		require.NoError(t, taiko.CheckL1Origins(ctx, env.Net.GetL1ELNode(0), follower))
+
+		require.NoError(t, network.Rejoin(ctx, follower))
+		env.GenSomeL2Blocks(t, 3)
+		waitCaughtUp(t, env, follower)
+	}
+}
+
+func fallbackWithoutPeers(env *taiko.TestEnv, network *taiko.L2Network) func(*hivesim.T) {
+	return func(t *hivesim.T) {
+		ctx := env.Context
+		follower, driver := startFollower(env, network, true)
+		before := waitCaughtUp(t, env, follower)
+
+		require.NoError(t, network.Partition(ctx, follower))
+		require.NoError(t, t.Sim.StopClient(t.SuiteID, t.TestID, driver.Container))
+		verified := generateVerifiedBlocks(t, env, 10)
+
+		startDriver(env, follower, true)
+		waitCaughtUp(t, env, follower)
+		require.Zero(t, blocksWithoutL1Origin(t, env, follower, before+1, verified),
+			"blocks synced through p2p without peers")
+		require.NoError(t, network.Rejoin(ctx, follower))
+	}
+}
+
+func conflictingPeer(env *taiko.TestEnv) func(*hivesim.T) {
+	return func(t *hivesim.T) {
+		ctx := env.Context
+		l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+		l2Cli, err := l2.EthClient()
+		require.NoError(t, err)
+		sender := env.L2Vault.CreateAccount(ctx, l2Cli, big.NewInt(params.Ether))
+		l1Cli, err := l1.EthClient()
+		require.NoError(t, err)
+		proposer := env.L1Vault.CreateAccount(ctx, l1Cli, new(big.Int).Mul(big.NewInt(10), big.NewInt(params.Ether)))
+		proposerKey := common.Bytes2Hex(crypto.FromECDSA(env.L1Vault.FindKey(proposer)))
+
+		// The bad peer follows the devnet chain until L1 is forked. Then it follows the
+		// fork, where its own proposer proposes other L2 blocks. The accounts used on the
+		// fork are funded before, so they exist on both chains.
+		bad := env.NewFullSyncL2ELNode()
+		badDriver := env.NewDriverNode(l1, bad)
+		require.NoError(t, taiko.WaitHeight(ctx, bad, taiko.GreaterEqual(l2Head(t, env, l2))))
+		reorg := env.NewL1Reorg()
+		defer reorg.Stop()
+		require.NoError(t, reorg.Partition(ctx))
+		require.NoError(t, t.Sim.StopClient(t.SuiteID, t.TestID, badDriver.Container))
+		forkHeight := l2Head(t, env, bad)
+		env.NewDriverNode(reorg.Fork, bad)
+		env.NewProposerNode(reorg.Fork, bad, taiko.WithProposerPrivateKey(proposerKey))
+		sendConflictingTxs(t, env, bad, sender, 3)
+		require.NoError(t, taiko.WaitHeight(ctx, bad, taiko.GreaterEqual(forkHeight+1)))
+		verified := generateVerifiedBlocks(t, env, 5)
+		require.Greater(t, verified, forkHeight)
+
+		network := env.NewL2Network("taiko-l2-bad")
+		defer network.Remove()
+		require.NoError(t, network.Join(ctx, bad))
+		follower, _ := startFollower(env, network, true)
+		waitCaughtUp(t, env, follower)
+		// All blocks were derived from L1.
+		require.NoError(t, taiko.CheckL1Origins(ctx, l1, follower))
+	}
+}
+
+// sendConflictingTxs sends cnt transactions from sender to the L2 node, with the
+// nonces of its chain. The transactions are not known to the devnet L2 node.
+func sendConflictingTxs(t *hivesim.T, env *taiko.TestEnv, n *taiko.ELNode, sender common.Address, cnt int) {
+	ctx := env.Context
+	cli, err := n.EthClient()
+	require.NoError(t, err)
+	nonce, err := cli.PendingNonceAt(ctx, sender)
+	require.NoError(t, err)
+	gasPrice, err := cli.SuggestGasPrice(ctx)
+	require.NoError(t, err)
+	for i := 0; i < cnt; i++ {
+		tx, err := env.L2Vault.SignTransaction(sender, types.NewTx(&types.LegacyTx{
+			Nonce:    nonce + uint64(i),
+			Gas:      params.TxGas,
+			GasPrice: gasPrice,
+			To:       &sender,
+			Value:    common.Big1,
+		}))
+		require.NoError(t, err)
+		require.NoError(t, cli.SendTransaction(ctx, tx))
+	}
+}
+
+// blocksWithoutL1Origin returns the number of blocks from..to which have no L1 origin
+// on the L2 node.
+func blocksWithoutL1Origin(t *hivesim.T, env *taiko.TestEnv, n *taiko.ELNode, from, to uint64) int {
+	cnt := 0
+	for i := from; i <= to; i++ {
+		origin, err := taiko.L1OriginByID(env.Context, n, new(big.Int).SetUint64(i))
+		if err != nil && err.Error() != ethereum.NotFound.Error() {
+			require.NoError(t, err, "L1 origin of block %d", i)
+		}
+		if origin == nil {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+func l2Head(t *hivesim.T, env *taiko.TestEnv, n *taiko.ELNode) uint64 {
+	cli, err := n.EthClient()
+	require.NoError(t, err)
+	head, err := cli.BlockNumber(env.Context)
+	require.NoError(t, err)
+	return head
+}
+
+func multiProposers(t *hivesim.T, clients *taiko.ClientsByRole) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartDevnet(&taiko.DevnetSpec{
+		L2NodeTypes: []string{"full", "full", "snap"},
+		Proposers:   3,
+		Provers:     1,
+		EnableL2P2P: true,
+	})
+	defer env.StopDevnet()
+
+	blockCnt := uint64(10)
+	env.GenSomeL2Blocks(t, blockCnt)
+
+	// All L2 nodes must end up with the same chain.
+	require.NoError(t, taiko.CheckL2Consistency(ctx, env.Net.GetL1ELNode(0), env.Net.L2Engines, blockCnt))
+}
+
+// protocolParamSets are the protocol parameters of protocolMatrix.
+var protocolParamSets = []taiko.ProtocolParams{
+	{taiko.ParamBlockMaxGasLimit: "3000000", taiko.ParamCommitConfirmations: "0"},
+	{taiko.ParamBlockMaxGasLimit: "6000000", taiko.ParamCommitConfirmations: "2"},
+	{taiko.ParamProofTimeCap: "10 seconds"},
+}
+
+func protocolMatrix(t *hivesim.T, clients *taiko.ClientsByRole) {
+	for _, params := range protocolParamSets {
+		params := params
+		t.Run(hivesim.TestSpec{
+			Name: "Protocol " + params.String(),
+			Run: func(t *hivesim.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+				defer cancel()
+				env := taiko.NewTestEnvWithClients(ctx, t, clients)
+				require.NotNil(t, env.Clients.Contract, "protocol matrix needs the taiko-protocol client")
+				spec := taiko.SingleNodeSpec()
+				spec.Protocol = params
+				env.StartDevnet(spec)
+				defer env.StopDevnet()
+
+				if v, ok := params[taiko.ParamBlockMaxGasLimit]; ok {
+					require.Equal(t, v, env.TaikoConf.BlockMaxGasLimit.String())
+				}
+				if v, ok := params[taiko.ParamCommitConfirmations]; ok {
+					require.Equal(t, v, env.TaikoConf.CommitConfirmations.String())
+				}
+				blockCnt := uint64(3)
+				env.GenSomeL2Blocks(t, blockCnt)
+				l1 := env.Net.GetL1ELNode(0)
+				require.NoError(t, taiko.WaitStateChange(env.Context, l1, func(psv *bindings.LibUtilsStateVariables) bool {
+					return psv.LatestVerifiedHeight >= blockCnt
+				}))
+			},
+		})
 	}
 }
 
 // Since there is no prover, state.LatestVerifiedId is always 0,
 // so you will get an error when you propose the LibConstants.K_MAX_NUM_BLOCKS block
-func tooManyPendingBlocks(t *hivesim.T) {
+func tooManyPendingBlocks(t *hivesim.T, clients *taiko.ClientsByRole) {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 	defer cancel()
 
-	env := taiko.NewTestEnv(ctx, t)
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
 	env.StartL1L2Driver(taiko.WithELNodeType("full"))
 
 	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
@@ -255,11 +547,11 @@ func canPropose(t *hivesim.T, env *taiko.TestEnv, taikoL1 *bindings.TaikoL1Clien
 
 // proposeInvalidTxListBytes commits and proposes an invalid transaction list
 // bytes to TaikoL1 contract.
-func proposeInvalidTxListBytes(t *hivesim.T) {
+func proposeInvalidTxListBytes(t *hivesim.T, clients *taiko.ClientsByRole) {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 	defer cancel()
 
-	env := taiko.NewTestEnv(ctx, t)
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
 	env.StartSingleNodeNet()
 
 	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
@@ -278,17 +570,17 @@ func proposeInvalidTxListBytes(t *hivesim.T) {
 	require.NoError(t, p.ProposeTxList(env.Context, meta, commitTx, invalidTxListBytes, 1))
 	// new L1 block should be generated.
 	require.NoError(t, taiko.WaitHeight(ctx, l1, taiko.GreaterEqual(1)))
-	nextBlockIDUpdated(t, l1)
+	nextBlockIDUpdated(t, ctx, l1)
 	verifiedBlockNoChange(t, l1)
 }
 
 // proposeTxListIncludingInvalidTx commits and proposes a validly encoded
 // transaction list which including an invalid transaction.
-func proposeTxListIncludingInvalidTx(t *hivesim.T) {
+func proposeTxListIncludingInvalidTx(t *hivesim.T, clients *taiko.ClientsByRole) {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 	defer cancel()
 
-	env := taiko.NewTestEnv(ctx, t)
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
 	env.StartSingleNodeNet()
 
 	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
@@ -305,13 +597,13 @@ func proposeTxListIncludingInvalidTx(t *hivesim.T) {
 	require.NoError(t, p.ProposeTxList(env.Context, meta, commitTx, txListBytes, 1))
 	// new L1 block should be generated.
 	require.NoError(t, taiko.WaitHeight(ctx, l1, taiko.GreaterEqual(1)))
-	nextBlockIDUpdated(t, l1)
+	nextBlockIDUpdated(t, ctx, l1)
 	newPendingL2BlockGenerated(t, env, invalidTx)
 	verifiedBlockNoChange(t, l1)
 }
 
-func nextBlockIDUpdated(t *hivesim.T, l1 *taiko.ELNode) {
-	require.NoError(t, taiko.WaitStateChange(l1, func(psv *bindings.LibUtilsStateVariables) bool {
+func nextBlockIDUpdated(t *hivesim.T, ctx context.Context, l1 *taiko.ELNode) {
+	require.NoError(t, taiko.WaitStateChange(ctx, l1, func(psv *bindings.LibUtilsStateVariables) bool {
 		if psv.NextBlockId == 2 {
 			return true
 		}
@@ -358,11 +650,11 @@ func generateInvalidTransaction(env *taiko.TestEnv) *types.Transaction {
 	return tx
 }
 
-func generateLargeTxLists(t *hivesim.T) {
+func generateLargeTxLists(t *hivesim.T, clients *taiko.ClientsByRole) {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 	defer cancel()
 
-	env := taiko.NewTestEnv(ctx, t)
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
 	env.StartL1L2(taiko.WithELNodeType("full"))
 
 	l2ethCli, err := env.Net.GetL2ELNode(0).EthClient()
@@ -388,7 +680,7 @@ func runAllTests(t *hivesim.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
 	defer cancel()
 	taiko.RunTests(t, ctx, &taiko.RunTestsParams{
-		Tests:       tests,
+		MatrixTests: tests,
 		Concurrency: 15,
 	})
 }
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/beacon"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/hive/hivesim"
+	"github.com/ethereum/hive/taiko"
+	"github.com/stretchr/testify/require"
+	"github.com/taikoxyz/taiko-client/bindings"
+	taikorpc "github.com/taikoxyz/taiko-client/pkg/rpc"
+)
+
+var (
+	// blockGasLimit is the gas limit of the blocks built by the tests, on top of the
+	// gas limit of the anchor transaction.
+	blockGasLimit = uint64(6_000_000)
+	// gasPrice is the gas price of legacy transactions, on chains without base fee.
+	gasPrice = big.NewInt(30 * params.GWei)
+	gasTip   = big.NewInt(params.GWei)
+)
+
+// Env is the environment of an engine API test: an L1 node with the protocol deployed,
+// and a taiko-geth node without driver, whose blocks are only built by the test.
+type Env struct {
+	*taiko.TestEnv
+	L1, L2       *taiko.ELNode
+	L1Eth, L2Eth *ethclient.Client
+	TaikoL2      *bindings.TaikoL2Client
+	Engine       *TestEngineClient
+}
+
+func newEnv(ctx context.Context, t *hivesim.T, clients *taiko.ClientsByRole) *Env {
+	te := taiko.NewTestEnvWithClients(ctx, t, clients)
+	te.StartL1L2()
+	e := &Env{
+		TestEnv: te,
+		L1:      te.Net.GetL1ELNode(0),
+		L2:      te.Net.GetL2ELNode(0),
+	}
+	var err error
+	e.L1Eth, err = e.L1.EthClient()
+	require.NoError(t, err)
+	e.L2Eth, err = e.L2.EthClient()
+	require.NoError(t, err)
+	e.TaikoL2, err = e.L2.TaikoL2Client()
+	require.NoError(t, err)
+	engine, err := taikorpc.DialEngineClientWithBackoff(ctx, e.L2.EngineEndpoint(), string(common.FromHex(te.Conf.L2.JWTSecret)))
+	require.NoError(t, err)
+	e.Engine = NewTestEngineClient(e, engine)
+	return e
+}
+
+// Close stops the nodes of the test.
+func (e *Env) Close() {
+	e.L1Eth.Close()
+	e.L2Eth.Close()
+	e.StopDevnet()
+}
+
+// head returns the head of the L2 chain.
+func (e *Env) head() *types.Header {
+	h, err := e.L2Eth.HeaderByNumber(e.Context, nil)
+	require.NoError(e.T, err)
+	return h
+}
+
+// l1Head returns the head of the L1 chain, which the blocks of the test are
+// anchored to.
+func (e *Env) l1Head() *types.Header {
+	h, err := e.L1Eth.HeaderByNumber(e.Context, nil)
+	require.NoError(e.T, err)
+	return h
+}
+
+// anchorTx creates the anchor transaction of the child of parent, which refers to the
+// L1 block l1 and is signed with key. Valid anchor transactions are signed by the
+// golden touch account.
+func (e *Env) anchorTx(parent, l1 *types.Header, key *taiko.Account) *types.Transaction {
+	t := e.T
+	nonce, err := e.L2Eth.NonceAt(e.Context, key.Address, parent.Number)
+	require.NoError(t, err)
+	signer := types.LatestSignerForChainID(e.Conf.L2.ChainID)
+	opts := &bind.TransactOpts{
+		From: key.Address,
+		Signer: func(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return types.SignTx(tx, signer, key.PrivateKey)
+		},
+		Nonce:    new(big.Int).SetUint64(nonce),
+		Context:  e.Context,
+		GasPrice: common.Big0,
+		GasLimit: e.TaikoConf.AnchorTxGasLimit.Uint64(),
+		NoSend:   true,
+	}
+	tx, err := e.TaikoL2.Anchor(opts, l1.Number, l1.Hash())
+	require.NoError(t, err)
+	return tx
+}
+
+// validAnchorTx creates the anchor transaction of the child of parent.
+func (e *Env) validAnchorTx(parent, l1 *types.Header) *types.Transaction {
+	return e.anchorTx(parent, l1, e.Conf.L2.Throwawayer)
+}
+
+// transferTxs creates n transfers from the prefunded proposer account, which can be
+// included in the child of parent.
+func (e *Env) transferTxs(parent *types.Header, n int) []*types.Transaction {
+	t := e.T
+	from := e.Conf.L2.Proposer
+	nonce, err := e.L2Eth.NonceAt(e.Context, from.Address, parent.Number)
+	require.NoError(t, err)
+	signer := types.LatestSignerForChainID(e.Conf.L2.ChainID)
+	txs := make([]*types.Transaction, n)
+	for i := range txs {
+		to := common.BytesToAddress(crypto.Keccak256(parent.Hash().Bytes(), big.NewInt(int64(i)).Bytes()))
+		var data types.TxData
+		if parent.BaseFee == nil {
+			data = &types.LegacyTx{
+				Nonce:    nonce + uint64(i),
+				GasPrice: gasPrice,
+				Gas:      params.TxGas,
+				To:       &to,
+				Value:    big1,
+			}
+		} else {
+			feeCap := new(big.Int).Mul(parent.BaseFee, big.NewInt(2))
+			data = &types.DynamicFeeTx{
+				ChainID:   e.Conf.L2.ChainID,
+				Nonce:     nonce + uint64(i),
+				GasTipCap: gasTip,
+				GasFeeCap: feeCap.Add(feeCap, gasTip),
+				Gas:       params.TxGas,
+				To:        &to,
+				Value:     big1,
+			}
+		}
+		tx, err := types.SignTx(types.NewTx(data), signer, from.PrivateKey)
+		require.NoError(t, err)
+		txs[i] = tx
+	}
+	return txs
+}
+
+var big1 = big.NewInt(1)
+
+// payloadAttributes returns the attributes of a block on top of parent, which is
+// derived from the L1 block l1 and contains txs.
+func (e *Env) payloadAttributes(parent, l1 *types.Header, txs []*types.Transaction) *beacon.PayloadAttributesV1 {
+	txList, err := rlp.EncodeToBytes(txs)
+	require.NoError(e.T, err)
+	timestamp := uint64(time.Now().Unix())
+	if timestamp <= parent.Time {
+		timestamp = parent.Time + 1
+	}
+	var (
+		id       = new(big.Int).Add(parent.Number, common.Big1)
+		mixHash  = common.BytesToHash(crypto.Keccak256(l1.Hash().Bytes(), id.Bytes()))
+		gasLimit = blockGasLimit + e.TaikoConf.AnchorTxGasLimit.Uint64()
+		coinbase = e.Conf.L2.SuggestedFeeRecipient.Address
+	)
+	return &beacon.PayloadAttributesV1{
+		Timestamp:             timestamp,
+		Random:                mixHash,
+		SuggestedFeeRecipient: coinbase,
+		BlockMetadata: &beacon.BlockMetadata{
+			HighestBlockID: id,
+			Beneficiary:    coinbase,
+			GasLimit:       gasLimit,
+			Timestamp:      timestamp,
+			TxList:         txList,
+			MixHash:        mixHash,
+			ExtraData:      []byte{},
+		},
+		L1Origin: &rawdb.L1Origin{
+			BlockID:       id,
+			L1BlockHeight: l1.Number,
+			L1BlockHash:   l1.Hash(),
+		},
+	}
+}
+
+// buildPayload lets taiko-geth build a block on top of parent, without inserting it.
+func (e *Env) buildPayload(parent *types.Header, attrs *beacon.PayloadAttributesV1) *beacon.ExecutableDataV1 {
+	r := e.Engine.TestEngineForkchoiceUpdatedV1(&beacon.ForkchoiceStateV1{HeadBlockHash: parent.Hash()}, attrs)
+	r.ExpectPayloadStatus(Valid)
+	p := e.Engine.TestEngineGetPayloadV1(r.ExpectPayloadID())
+	p.ExpectNumber(parent.Number.Uint64() + 1)
+	return p.Payload
+}
+
+// insertPayload executes the payload, and makes it the head of the chain.
+func (e *Env) insertPayload(p *beacon.ExecutableDataV1) {
+	r := e.Engine.TestEngineNewPayloadV1(p)
+	r.ExpectStatus(Valid)
+	r.ExpectLatestValidHash(&p.BlockHash)
+	e.setHead(p.BlockHash)
+}
+
+// setHead makes the block with the given hash the head of the chain.
+func (e *Env) setHead(hash common.Hash) {
+	fc := &beacon.ForkchoiceStateV1{HeadBlockHash: hash, SafeBlockHash: hash}
+	r := e.Engine.TestEngineForkchoiceUpdatedV1(fc, nil)
+	r.ExpectPayloadStatus(Valid)
+	r.ExpectNoPayloadID()
+	e.TestHeaderByNumber(nil).ExpectHash(hash)
+}
+
+// expectRejected expects taiko-geth to reject a block with the given attributes,
+// either while building it with an invalid payload attributes error, or with an
+// INVALID status when it is executed.
+func (e *Env) expectRejected(parent *types.Header, attrs *beacon.PayloadAttributesV1) {
+	r := e.Engine.TestEngineForkchoiceUpdatedV1(&beacon.ForkchoiceStateV1{HeadBlockHash: parent.Hash()}, attrs)
+	if r.Error != nil {
+		r.ExpectErrorCode(InvalidPayloadAttributes)
+		return
+	}
+	r.ExpectPayloadStatus(Valid)
+	p := e.Engine.TestEngineGetPayloadV1(r.ExpectPayloadID())
+	p.ExpectNoError()
+	e.Engine.TestEngineNewPayloadV1(p.Payload).ExpectStatus(Invalid)
+}
+
+// customizePayload returns a copy of the payload with other transactions. The block
+// hash of the copy is computed from its contents.
+func customizePayload(p *beacon.ExecutableDataV1, txs []*types.Transaction, modify func(*types.Header)) *beacon.ExecutableDataV1 {
+	header := &types.Header{
+		ParentHash:  p.ParentHash,
+		UncleHash:   types.EmptyUncleHash,
+		Coinbase:    p.FeeRecipient,
+		Root:        p.StateRoot,
+		TxHash:      types.DeriveSha(types.Transactions(txs), trie.NewStackTrie(nil)),
+		ReceiptHash: p.ReceiptsRoot,
+		Bloom:       types.BytesToBloom(p.LogsBloom),
+		Difficulty:  common.Big0,
+		Number:      new(big.Int).SetUint64(p.Number),
+		GasLimit:    p.GasLimit,
+		GasUsed:     p.GasUsed,
+		Time:        p.Timestamp,
+		Extra:       p.ExtraData,
+		MixDigest:   p.Random,
+		BaseFee:     p.BaseFeePerGas,
+	}
+	if modify != nil {
+		modify(header)
+	}
+	return beacon.BlockToExecutableData(types.NewBlockWithHeader(header).WithBody(txs, nil))
+}
+
+// payloadTxs decodes the transactions of a payload.
+func payloadTxs(t *hivesim.T, p *beacon.ExecutableDataV1) []*types.Transaction {
+	txs := make([]*types.Transaction, len(p.Transactions))
+	for i, enc := range p.Transactions {
+		tx := new(types.Transaction)
+		require.NoError(t, tx.UnmarshalBinary(enc))
+		txs[i] = tx
+	}
+	return txs
+}
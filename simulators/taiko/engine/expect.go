@@ -0,0 +1,362 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"runtime"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/beacon"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/hive/taiko"
+	taikorpc "github.com/taikoxyz/taiko-client/pkg/rpc"
+)
+
+// printExpectStack prints the caller of the failed expectation.
+func printExpectStack(t *Env) {
+	_, currentFile, _, _ := runtime.Caller(0)
+	for i := 1; ; i++ {
+		_, file, line, ok := runtime.Caller(i)
+		if !ok {
+			return
+		}
+		if file == currentFile {
+			continue
+		}
+		t.T.Logf("DEBUG: Failed `Expect*` routine called from: file=%s, line=%d", file, line)
+		return
+	}
+}
+
+type PayloadStatus string
+
+const (
+	Unknown          PayloadStatus = ""
+	Valid                          = "VALID"
+	Invalid                        = "INVALID"
+	Syncing                        = "SYNCING"
+	Accepted                       = "ACCEPTED"
+	InvalidBlockHash               = "INVALID_BLOCK_HASH"
+)
+
+// InvalidPayloadAttributes is the error code of a forkchoice update whose payload
+// attributes can't be built into a block.
+const InvalidPayloadAttributes = -38003
+
+func statusesToString(statuses []PayloadStatus) []string {
+	result := make([]string, len(statuses))
+	for i, s := range statuses {
+		result[i] = string(s)
+	}
+	return result
+}
+
+// ExpectEnv is the environment of an expectation.
+type ExpectEnv struct {
+	*Env
+}
+
+func (e ExpectEnv) Fatalf(format string, values ...interface{}) {
+	printExpectStack(e.Env)
+	e.Env.T.Fatalf(format, values...)
+}
+
+// TestEngineClient calls the engine API of taiko-geth, and returns the responses
+// as objects with expectations.
+type TestEngineClient struct {
+	*ExpectEnv
+	Engine *taikorpc.EngineClient
+}
+
+func NewTestEngineClient(t *Env, ec *taikorpc.EngineClient) *TestEngineClient {
+	return &TestEngineClient{
+		ExpectEnv: &ExpectEnv{Env: t},
+		Engine:    ec,
+	}
+}
+
+// ForkchoiceUpdated
+
+type ForkchoiceResponseExpectObject struct {
+	*ExpectEnv
+	Response  beacon.ForkChoiceResponse
+	Error     error
+	ErrorCode int
+}
+
+func (tec *TestEngineClient) TestEngineForkchoiceUpdatedV1(fcState *beacon.ForkchoiceStateV1, pAttributes *beacon.PayloadAttributesV1) *ForkchoiceResponseExpectObject {
+	ctx, cancel := context.WithTimeout(tec.Context, taiko.RPCTimeout)
+	defer cancel()
+	resp, err := tec.Engine.ForkchoiceUpdate(ctx, fcState, pAttributes)
+	ret := &ForkchoiceResponseExpectObject{
+		ExpectEnv: &ExpectEnv{Env: tec.Env},
+		Error:     err,
+	}
+	if resp != nil {
+		ret.Response = *resp
+	}
+	if err, ok := err.(rpc.Error); ok {
+		ret.ErrorCode = err.ErrorCode()
+	}
+	return ret
+}
+
+func (exp *ForkchoiceResponseExpectObject) ExpectNoError() {
+	if exp.Error != nil {
+		exp.Fatalf("FAIL: Unexpected error on EngineForkchoiceUpdatedV1: %v, expected=<None>", exp.Error)
+	}
+}
+
+func (exp *ForkchoiceResponseExpectObject) ExpectError() {
+	if exp.Error == nil {
+		exp.Fatalf("FAIL: Expected error on EngineForkchoiceUpdatedV1: response=%v", exp.Response)
+	}
+}
+
+func (exp *ForkchoiceResponseExpectObject) ExpectErrorCode(code int) {
+	exp.ExpectError()
+	if exp.ErrorCode != code {
+		exp.Fatalf("FAIL: Unexpected error code on EngineForkchoiceUpdatedV1: %d, expected=%d, error=%v", exp.ErrorCode, code, exp.Error)
+	}
+}
+
+func (exp *ForkchoiceResponseExpectObject) ExpectPayloadStatus(ps PayloadStatus) {
+	exp.ExpectNoError()
+	if PayloadStatus(exp.Response.PayloadStatus.Status) != ps {
+		exp.Fatalf("FAIL: Unexpected status response on EngineForkchoiceUpdatedV1: %v, expected=%v", exp.Response.PayloadStatus.Status, ps)
+	}
+}
+
+func (exp *ForkchoiceResponseExpectObject) ExpectAnyPayloadStatus(statuses ...PayloadStatus) {
+	exp.ExpectNoError()
+	for _, status := range statuses {
+		if PayloadStatus(exp.Response.PayloadStatus.Status) == status {
+			return
+		}
+	}
+	exp.Fatalf("FAIL: Unexpected status response on EngineForkchoiceUpdatedV1: %v, expected=%v", exp.Response.PayloadStatus.Status, statusesToString(statuses))
+}
+
+func (exp *ForkchoiceResponseExpectObject) ExpectLatestValidHash(lvh *common.Hash) {
+	exp.ExpectNoError()
+	if ((lvh == nil || exp.Response.PayloadStatus.LatestValidHash == nil) && exp.Response.PayloadStatus.LatestValidHash != lvh) ||
+		(lvh != nil && exp.Response.PayloadStatus.LatestValidHash != nil && *exp.Response.PayloadStatus.LatestValidHash != *lvh) {
+		exp.Fatalf("FAIL: Unexpected LatestValidHash on EngineForkchoiceUpdatedV1: %v, expected=%v", exp.Response.PayloadStatus.LatestValidHash, lvh)
+	}
+}
+
+func (exp *ForkchoiceResponseExpectObject) ExpectPayloadID() *beacon.PayloadID {
+	exp.ExpectNoError()
+	if exp.Response.PayloadID == nil {
+		exp.Fatalf("FAIL: No PayloadID on EngineForkchoiceUpdatedV1: status=%v", exp.Response.PayloadStatus.Status)
+	}
+	return exp.Response.PayloadID
+}
+
+func (exp *ForkchoiceResponseExpectObject) ExpectNoPayloadID() {
+	exp.ExpectNoError()
+	if exp.Response.PayloadID != nil {
+		exp.Fatalf("FAIL: Unexpected PayloadID on EngineForkchoiceUpdatedV1: %v, expected=<None>", exp.Response.PayloadID)
+	}
+}
+
+// NewPayload
+
+type NewPayloadResponseExpectObject struct {
+	*ExpectEnv
+	Payload   *beacon.ExecutableDataV1
+	Status    beacon.PayloadStatusV1
+	Error     error
+	ErrorCode int
+}
+
+func (tec *TestEngineClient) TestEngineNewPayloadV1(payload *beacon.ExecutableDataV1) *NewPayloadResponseExpectObject {
+	ctx, cancel := context.WithTimeout(tec.Context, taiko.RPCTimeout)
+	defer cancel()
+	status, err := tec.Engine.NewPayload(ctx, payload)
+	ret := &NewPayloadResponseExpectObject{
+		ExpectEnv: &ExpectEnv{Env: tec.Env},
+		Payload:   payload,
+		Error:     err,
+	}
+	if status != nil {
+		ret.Status = *status
+	}
+	if err, ok := err.(rpc.Error); ok {
+		ret.ErrorCode = err.ErrorCode()
+	}
+	return ret
+}
+
+func (exp *NewPayloadResponseExpectObject) PayloadJson() string {
+	jsonPayload, _ := json.MarshalIndent(exp.Payload, "", " ")
+	return string(jsonPayload)
+}
+
+func (exp *NewPayloadResponseExpectObject) ExpectNoError() {
+	if exp.Error != nil {
+		exp.Fatalf("FAIL: Expected no error on EngineNewPayloadV1: error=%v, payload=%s", exp.Error, exp.PayloadJson())
+	}
+}
+
+func (exp *NewPayloadResponseExpectObject) ExpectStatus(ps PayloadStatus) {
+	exp.ExpectNoError()
+	if PayloadStatus(exp.Status.Status) != ps {
+		exp.Fatalf("FAIL: Unexpected status response on EngineNewPayloadV1: %v, expected=%v, payload=%s", exp.Status.Status, ps, exp.PayloadJson())
+	}
+}
+
+func (exp *NewPayloadResponseExpectObject) ExpectStatusEither(statuses ...PayloadStatus) {
+	exp.ExpectNoError()
+	for _, status := range statuses {
+		if PayloadStatus(exp.Status.Status) == status {
+			return
+		}
+	}
+	exp.Fatalf("FAIL: Unexpected status response on EngineNewPayloadV1: %v, expected=%v, payload=%s", exp.Status.Status, strings.Join(statusesToString(statuses), ","), exp.PayloadJson())
+}
+
+func (exp *NewPayloadResponseExpectObject) ExpectLatestValidHash(lvh *common.Hash) {
+	exp.ExpectNoError()
+	if ((lvh == nil || exp.Status.LatestValidHash == nil) && exp.Status.LatestValidHash != lvh) ||
+		(lvh != nil && exp.Status.LatestValidHash != nil && *exp.Status.LatestValidHash != *lvh) {
+		exp.Fatalf("FAIL: Unexpected LatestValidHash on EngineNewPayloadV1: %v, expected=%v", exp.Status.LatestValidHash, lvh)
+	}
+}
+
+// GetPayload
+
+type GetPayloadResponseExpectObject struct {
+	*ExpectEnv
+	Payload   *beacon.ExecutableDataV1
+	Error     error
+	ErrorCode int
+}
+
+func (tec *TestEngineClient) TestEngineGetPayloadV1(payloadID *beacon.PayloadID) *GetPayloadResponseExpectObject {
+	ctx, cancel := context.WithTimeout(tec.Context, taiko.RPCTimeout)
+	defer cancel()
+	payload, err := tec.Engine.GetPayload(ctx, payloadID)
+	ret := &GetPayloadResponseExpectObject{
+		ExpectEnv: &ExpectEnv{Env: tec.Env},
+		Payload:   payload,
+		Error:     err,
+	}
+	if err, ok := err.(rpc.Error); ok {
+		ret.ErrorCode = err.ErrorCode()
+	}
+	return ret
+}
+
+func (exp *GetPayloadResponseExpectObject) ExpectNoError() {
+	if exp.Error != nil {
+		exp.Fatalf("FAIL: Expected no error on EngineGetPayloadV1: error=%v", exp.Error)
+	}
+}
+
+func (exp *GetPayloadResponseExpectObject) ExpectError() {
+	if exp.Error == nil {
+		exp.Fatalf("FAIL: Expected error on EngineGetPayloadV1: payload=%v", exp.Payload)
+	}
+}
+
+func (exp *GetPayloadResponseExpectObject) ExpectNumber(number uint64) {
+	exp.ExpectNoError()
+	if exp.Payload.Number != number {
+		exp.Fatalf("FAIL: Unexpected block number on EngineGetPayloadV1: %d, expected=%d", exp.Payload.Number, number)
+	}
+}
+
+func (exp *GetPayloadResponseExpectObject) ExpectTransactionCount(n int) {
+	exp.ExpectNoError()
+	if len(exp.Payload.Transactions) != n {
+		exp.Fatalf("FAIL: Unexpected transaction count on EngineGetPayloadV1: %d, expected=%d", len(exp.Payload.Transactions), n)
+	}
+}
+
+// Eth namespace
+
+type HeaderResponseExpectObject struct {
+	*ExpectEnv
+	Call     string
+	Response *types.Header
+	Error    error
+}
+
+func (t *Env) TestHeaderByNumber(number *big.Int) *HeaderResponseExpectObject {
+	ctx, cancel := context.WithTimeout(t.Context, taiko.RPCTimeout)
+	defer cancel()
+	header, err := t.L2Eth.HeaderByNumber(ctx, number)
+	return &HeaderResponseExpectObject{
+		ExpectEnv: &ExpectEnv{Env: t},
+		Call:      fmt.Sprintf("HeaderByNumber(%v)", number),
+		Response:  header,
+		Error:     err,
+	}
+}
+
+func (exp *HeaderResponseExpectObject) ExpectNoError() {
+	if exp.Error != nil {
+		exp.Fatalf("FAIL: Unexpected error on %s: %v, expected=<None>", exp.Call, exp.Error)
+	}
+}
+
+func (exp *HeaderResponseExpectObject) ExpectError() {
+	if exp.Error == nil {
+		exp.Fatalf("FAIL: Expected error on %s: header=%v", exp.Call, exp.Response.Hash())
+	}
+}
+
+func (exp *HeaderResponseExpectObject) ExpectHash(expHash common.Hash) {
+	exp.ExpectNoError()
+	if exp.Response.Hash() != expHash {
+		exp.Fatalf("FAIL: Unexpected hash on %s: %v, expected=%v", exp.Call, exp.Response.Hash(), expHash)
+	}
+}
+
+type L1OriginResponseExpectObject struct {
+	*ExpectEnv
+	Call     string
+	Response *taiko.L1Origin
+	Error    error
+}
+
+func (t *Env) TestL1OriginByID(id *big.Int) *L1OriginResponseExpectObject {
+	ctx, cancel := context.WithTimeout(t.Context, taiko.RPCTimeout)
+	defer cancel()
+	origin, err := taiko.L1OriginByID(ctx, t.L2, id)
+	return &L1OriginResponseExpectObject{
+		ExpectEnv: &ExpectEnv{Env: t},
+		Call:      fmt.Sprintf("taiko_l1OriginByID(%v)", id),
+		Response:  origin,
+		Error:     err,
+	}
+}
+
+func (exp *L1OriginResponseExpectObject) ExpectNoError() {
+	if exp.Error != nil {
+		exp.Fatalf("FAIL: Unexpected error on %s: %v, expected=<None>", exp.Call, exp.Error)
+	}
+}
+
+func (exp *L1OriginResponseExpectObject) ExpectError() {
+	if exp.Error == nil {
+		exp.Fatalf("FAIL: Expected error on %s: origin=%+v", exp.Call, exp.Response)
+	}
+}
+
+// ExpectOrigin expects the L1 origin to refer to the L2 block and the L1 block.
+func (exp *L1OriginResponseExpectObject) ExpectOrigin(l2Hash common.Hash, l1 *types.Header) {
+	exp.ExpectNoError()
+	o := exp.Response
+	if o.L2BlockHash != l2Hash {
+		exp.Fatalf("FAIL: Unexpected L2 block hash on %s: %v, expected=%v", exp.Call, o.L2BlockHash, l2Hash)
+	}
+	if o.L1BlockHash != l1.Hash() || o.L1BlockHeight.ToInt().Cmp(l1.Number) != 0 {
+		exp.Fatalf("FAIL: Unexpected L1 block on %s: %d %v, expected=%d %v", exp.Call, o.L1BlockHeight.ToInt(), o.L1BlockHash, l1.Number, l1.Hash())
+	}
+}
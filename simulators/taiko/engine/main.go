@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/hive/hivesim"
+	"github.com/ethereum/hive/taiko"
+)
+
+func main() {
+	suite := hivesim.Suite{
+		Name:        "taiko-engine",
+		Description: "Drives the engine API of taiko-geth with taiko payload attributes, and checks the statuses of valid and invalid payloads and the forkchoice behaviour.",
+	}
+	suite.Add(&hivesim.TestSpec{
+		Name: "Client launch",
+		Description: `This test launches the client and runs the test tool.
+		Results from the test tool are reported as individual sub-tests.`,
+		Run: func(t *hivesim.T) { runAllTests(t) },
+	})
+	sim := hivesim.New()
+	hivesim.MustRun(sim, suite)
+}
+
+// runAllTests runs every engine test against its own L1 and taiko-geth node, once for
+// every L2 execution client. There is no driver, proposer or prover, the blocks of
+// taiko-geth are only built by the test.
+func runAllTests(t *hivesim.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	var specs []*hivesim.TestSpec
+	seen := make(map[string]bool)
+	for _, clients := range taiko.ClientCombinations(t) {
+		if clients.L2 == nil || seen[clients.L2.Name] {
+			continue
+		}
+		seen[clients.L2.Name] = true
+		clients := clients
+		for _, test := range tests {
+			test := test
+			specs = append(specs, &hivesim.TestSpec{
+				Name:        fmt.Sprintf("%s (%s)", test.Name, clients.L2.Name),
+				Description: test.Description,
+				Run: func(t *hivesim.T) {
+					env := newEnv(ctx, t, clients)
+					defer env.Close()
+					test.Run(env)
+				},
+			})
+		}
+	}
+	taiko.RunTests(t, ctx, &taiko.RunTestsParams{
+		Tests:       specs,
+		Concurrency: 4,
+	})
+}
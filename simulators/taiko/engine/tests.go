@@ -0,0 +1,207 @@
+package main
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/beacon"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// EngineTest is a test of the engine API of taiko-geth.
+type EngineTest struct {
+	Name        string
+	Description string
+	Run         func(*Env)
+}
+
+var tests = []EngineTest{
+	{
+		Name:        "Build blocks",
+		Description: "Blocks built from taiko payload attributes start with the anchor transaction, contain the transaction list and record their L1 origin.",
+		Run:         buildBlocksTest,
+	},
+	{
+		Name:        "Unknown payload ID",
+		Description: "engine_getPayloadV1 fails for a payload ID which was not returned by engine_forkchoiceUpdatedV1.",
+		Run:         unknownPayloadIDTest,
+	},
+	{
+		Name:        "Unknown parent",
+		Description: "engine_newPayloadV1 returns SYNCING or ACCEPTED for a payload whose parent is unknown.",
+		Run:         unknownParentTest,
+	},
+	{
+		Name:        "Unknown head",
+		Description: "engine_forkchoiceUpdatedV1 returns SYNCING for an unknown head, and keeps the current head.",
+		Run:         unknownHeadTest,
+	},
+	{
+		Name:        "Invalid block hash",
+		Description: "engine_newPayloadV1 returns INVALID_BLOCK_HASH for a payload whose block hash does not match its contents.",
+		Run:         invalidBlockHashTest,
+	},
+	{
+		Name:        "Invalid timestamp",
+		Description: "engine_newPayloadV1 returns INVALID for a payload with the timestamp of its parent, with the parent as latest valid hash.",
+		Run:         invalidTimestampTest,
+	},
+	{
+		Name:        "Missing anchor",
+		Description: "A block without anchor transaction is rejected, and the head is kept.",
+		Run:         missingAnchorTest,
+	},
+	{
+		Name:        "Anchor from other account",
+		Description: "A block whose anchor transaction is not sent by the golden touch account is rejected, and the head is kept.",
+		Run:         anchorWrongSenderTest,
+	},
+	{
+		Name:        "Anchor not first",
+		Description: "A block whose anchor transaction is not the first transaction is rejected, and the head is kept.",
+		Run:         anchorNotFirstTest,
+	},
+	{
+		Name:        "Forkchoice reorg",
+		Description: "engine_forkchoiceUpdatedV1 switches the head between two valid sibling blocks, and blocks can be built on top of the new head.",
+		Run:         forkchoiceReorgTest,
+	},
+}
+
+// buildBlocksTest builds several blocks with transfers on top of each other.
+func buildBlocksTest(t *Env) {
+	for i := 0; i < 3; i++ {
+		parent, l1 := t.head(), t.l1Head()
+		txs := append([]*types.Transaction{t.validAnchorTx(parent, l1)}, t.transferTxs(parent, 3)...)
+		p := t.buildPayload(parent, t.payloadAttributes(parent, l1, txs))
+		if len(p.Transactions) != len(txs) {
+			t.T.Fatalf("block %d has %d transactions, expected=%d", p.Number, len(p.Transactions), len(txs))
+		}
+		for j, tx := range payloadTxs(t.T, p) {
+			require.Equal(t.T, txs[j].Hash(), tx.Hash(), "transaction %d of block %d", j, p.Number)
+		}
+		t.insertPayload(p)
+
+		t.TestL1OriginByID(new(big.Int).SetUint64(p.Number)).ExpectOrigin(p.BlockHash, l1)
+		for _, tx := range txs {
+			receipt, err := t.L2Eth.TransactionReceipt(t.Context, tx.Hash())
+			require.NoError(t.T, err)
+			require.Equal(t.T, types.ReceiptStatusSuccessful, receipt.Status, "transaction %v of block %d failed", tx.Hash(), p.Number)
+			require.Equal(t.T, p.BlockHash, receipt.BlockHash)
+		}
+	}
+}
+
+// unknownPayloadIDTest requests a payload which was never built.
+func unknownPayloadIDTest(t *Env) {
+	t.Engine.TestEngineGetPayloadV1(&beacon.PayloadID{1, 2, 3, 4, 5, 6, 7, 8}).ExpectError()
+}
+
+// unknownParentTest sends a payload on top of a block taiko-geth does not know.
+func unknownParentTest(t *Env) {
+	parent, l1 := t.head(), t.l1Head()
+	txs := []*types.Transaction{t.validAnchorTx(parent, l1)}
+	p := t.buildPayload(parent, t.payloadAttributes(parent, l1, txs))
+	p = customizePayload(p, txs, func(h *types.Header) {
+		h.ParentHash = common.HexToHash("0xdeadbeef")
+	})
+	t.Engine.TestEngineNewPayloadV1(p).ExpectStatusEither(Syncing, Accepted)
+	t.TestHeaderByNumber(nil).ExpectHash(parent.Hash())
+}
+
+// unknownHeadTest points the head to a block taiko-geth does not know.
+func unknownHeadTest(t *Env) {
+	head := t.head()
+	fc := &beacon.ForkchoiceStateV1{HeadBlockHash: common.HexToHash("0xdeadbeef")}
+	r := t.Engine.TestEngineForkchoiceUpdatedV1(fc, nil)
+	r.ExpectPayloadStatus(Syncing)
+	r.ExpectNoPayloadID()
+	t.TestHeaderByNumber(nil).ExpectHash(head.Hash())
+}
+
+// invalidBlockHashTest sends a payload with a wrong block hash.
+func invalidBlockHashTest(t *Env) {
+	parent, l1 := t.head(), t.l1Head()
+	txs := []*types.Transaction{t.validAnchorTx(parent, l1)}
+	p := t.buildPayload(parent, t.payloadAttributes(parent, l1, txs))
+	invalid := *p
+	invalid.BlockHash = common.HexToHash("0xdeadbeef")
+	t.Engine.TestEngineNewPayloadV1(&invalid).ExpectStatus(InvalidBlockHash)
+	t.TestHeaderByNumber(nil).ExpectHash(parent.Hash())
+
+	// The valid payload is still accepted.
+	t.insertPayload(p)
+}
+
+// invalidTimestampTest sends a payload with the timestamp of its parent.
+func invalidTimestampTest(t *Env) {
+	parent, l1 := t.head(), t.l1Head()
+	txs := []*types.Transaction{t.validAnchorTx(parent, l1)}
+	p := t.buildPayload(parent, t.payloadAttributes(parent, l1, txs))
+	p = customizePayload(p, txs, func(h *types.Header) {
+		h.Time = parent.Time
+	})
+	r := t.Engine.TestEngineNewPayloadV1(p)
+	r.ExpectStatus(Invalid)
+	parentHash := parent.Hash()
+	r.ExpectLatestValidHash(&parentHash)
+	t.TestHeaderByNumber(nil).ExpectHash(parentHash)
+}
+
+// missingAnchorTest builds a block with transfers only.
+func missingAnchorTest(t *Env) {
+	parent, l1 := t.head(), t.l1Head()
+	txs := t.transferTxs(parent, 2)
+	t.expectRejected(parent, t.payloadAttributes(parent, l1, txs))
+	t.TestHeaderByNumber(nil).ExpectHash(parent.Hash())
+}
+
+// anchorWrongSenderTest builds a block whose anchor transaction is signed by the
+// proposer instead of the golden touch account.
+func anchorWrongSenderTest(t *Env) {
+	parent, l1 := t.head(), t.l1Head()
+	txs := []*types.Transaction{t.anchorTx(parent, l1, t.Conf.L2.Proposer)}
+	t.expectRejected(parent, t.payloadAttributes(parent, l1, txs))
+	t.TestHeaderByNumber(nil).ExpectHash(parent.Hash())
+}
+
+// anchorNotFirstTest builds a block whose anchor transaction follows a transfer.
+func anchorNotFirstTest(t *Env) {
+	parent, l1 := t.head(), t.l1Head()
+	txs := append(t.transferTxs(parent, 1), t.validAnchorTx(parent, l1))
+	t.expectRejected(parent, t.payloadAttributes(parent, l1, txs))
+	t.TestHeaderByNumber(nil).ExpectHash(parent.Hash())
+}
+
+// forkchoiceReorgTest executes two sibling blocks, and switches the head between them.
+func forkchoiceReorgTest(t *Env) {
+	parent, l1 := t.head(), t.l1Head()
+	anchor := t.validAnchorTx(parent, l1)
+	a := t.buildPayload(parent, t.payloadAttributes(parent, l1, append([]*types.Transaction{anchor}, t.transferTxs(parent, 1)...)))
+	// The payload ID may not cover the transaction list, so the sibling gets another
+	// timestamp as well.
+	attrs := t.payloadAttributes(parent, l1, []*types.Transaction{anchor})
+	attrs.Timestamp++
+	attrs.BlockMetadata.Timestamp++
+	b := t.buildPayload(parent, attrs)
+	require.NotEqual(t.T, a.BlockHash, b.BlockHash)
+
+	for _, p := range []*beacon.ExecutableDataV1{a, b} {
+		r := t.Engine.TestEngineNewPayloadV1(p)
+		r.ExpectStatus(Valid)
+		r.ExpectLatestValidHash(&p.BlockHash)
+	}
+	t.TestHeaderByNumber(nil).ExpectHash(parent.Hash())
+
+	t.setHead(a.BlockHash)
+	t.setHead(b.BlockHash)
+	t.setHead(a.BlockHash)
+
+	// The chain continues on top of the new head.
+	head := t.head()
+	l1 = t.l1Head()
+	child := t.buildPayload(head, t.payloadAttributes(head, l1, []*types.Transaction{t.validAnchorTx(head, l1)}))
+	require.Equal(t.T, a.BlockHash, child.ParentHash)
+	t.insertPayload(child)
+}
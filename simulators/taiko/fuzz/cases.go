@@ -0,0 +1,371 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"math/rand"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/taikoxyz/taiko-client/bindings"
+)
+
+// txListCase generates a malformed transaction list.
+type txListCase struct {
+	Name string
+	Gen  func(g *generator) (*txList, error)
+}
+
+var cases = []*txListCase{
+	{Name: "random bytes", Gen: randomBytes},
+	{Name: "truncated RLP", Gen: truncatedRLP},
+	{Name: "RLP of non-transactions", Gen: nonTransactions},
+	{Name: "empty list", Gen: emptyList},
+	{Name: "oversize list", Gen: oversizeList},
+	{Name: "too many transactions", Gen: tooManyTxs},
+	{Name: "transaction over block gas limit", Gen: single(overGasLimit)},
+	{Name: "wrong chain ID", Gen: single(wrongChainID)},
+	{Name: "unprotected transaction", Gen: single(unprotected)},
+	{Name: "underpriced", Gen: single(underpriced)},
+	{Name: "intrinsic gas too low", Gen: single(intrinsicGas)},
+	{Name: "nonce too high", Gen: single(nonceTooHigh)},
+	{Name: "insufficient funds", Gen: single(insufficientFunds)},
+	{Name: "duplicate nonces", Gen: duplicateNonces},
+	{Name: "spoofed anchor", Gen: spoofedAnchor},
+	{Name: "mixed", Gen: mixed},
+}
+
+// txList is a generated transaction list, the gas limit it is proposed with, and what the
+// drivers derive from it.
+type txList struct {
+	Bytes    []byte
+	GasLimit uint64
+	Expect   expectation
+}
+
+// expectation is what the drivers derive from a transaction list.
+type expectation struct {
+	// Invalid lists are replaced by the drivers: they derive a block with just the
+	// anchor transaction, or no block at all.
+	Invalid bool `json:"invalid,omitempty"`
+	// Included are the transactions following the anchor transaction in the block
+	// derived from a valid list.
+	Included []common.Hash `json:"included,omitempty"`
+	// Optional transactions may follow the anchor transaction too.
+	Optional []common.Hash `json:"optional,omitempty"`
+}
+
+// outcome is what happens to a transaction of a decodable list.
+type outcome int
+
+const (
+	// included transactions are executed, successfully or not.
+	included outcome = iota
+	// skipped transactions can't be executed, the L2 engine leaves them out.
+	skipped
+	// optional transactions may be included or skipped.
+	optional
+	// invalidating transactions make the driver replace the whole list.
+	invalidating
+)
+
+// genTx is a generated transaction and its outcome.
+type genTx struct {
+	*types.Transaction
+	outcome outcome
+}
+
+// generator creates transactions of a funded sender against the current state of the
+// L2 chain.
+type generator struct {
+	rand    *rand.Rand
+	conf    *bindings.TaikoDataConfig
+	chainID *big.Int
+	taikoL2 *bindings.TaikoL2Client
+
+	sender      *ecdsa.PrivateKey
+	goldenTouch *ecdsa.PrivateKey
+
+	// state of the L2 chain, read by refresh
+	nonce            uint64
+	goldenTouchNonce uint64
+	gasPrice         *big.Int
+}
+
+// refresh reads the nonces of the senders and the gas price from the L2 node. Valid
+// transactions of a proposal change them.
+func (g *generator) refresh(ctx context.Context, cli *ethclient.Client) error {
+	var err error
+	if g.nonce, err = cli.NonceAt(ctx, crypto.PubkeyToAddress(g.sender.PublicKey), nil); err != nil {
+		return err
+	}
+	if g.goldenTouchNonce, err = cli.NonceAt(ctx, crypto.PubkeyToAddress(g.goldenTouch.PublicKey), nil); err != nil {
+		return err
+	}
+	g.gasPrice, err = cli.SuggestGasPrice(ctx)
+	return err
+}
+
+// transfer returns a transfer of the sender with the given nonce. modify changes the
+// transaction before it is signed.
+func (g *generator) transfer(nonce uint64, modify func(*types.LegacyTx)) *types.Transaction {
+	to := common.BigToAddress(big.NewInt(g.rand.Int63()))
+	tx := &types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: g.gasPrice,
+		Gas:      params.TxGas,
+		To:       &to,
+		Value:    big.NewInt(1 + g.rand.Int63n(params.GWei)),
+	}
+	if modify != nil {
+		modify(tx)
+	}
+	return g.sign(g.sender, g.chainID, tx)
+}
+
+func (g *generator) sign(key *ecdsa.PrivateKey, chainID *big.Int, tx types.TxData) *types.Transaction {
+	signed, err := types.SignNewTx(key, types.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		panic(err)
+	}
+	return signed
+}
+
+// anchor returns a call of TaikoL2.anchor with random arguments.
+func (g *generator) anchor(key *ecdsa.PrivateKey, nonce uint64) (*types.Transaction, error) {
+	opts, err := bind.NewKeyedTransactorWithChainID(key, g.chainID)
+	if err != nil {
+		return nil, err
+	}
+	opts.NoSend = true
+	opts.Nonce = new(big.Int).SetUint64(nonce)
+	opts.GasLimit = 300000
+	opts.GasPrice = g.gasPrice
+	l1Height := big.NewInt(g.rand.Int63n(1 << 20))
+	return g.taikoL2.Anchor(opts, l1Height, common.BytesToHash(g.bytes(32)))
+}
+
+func (g *generator) bytes(n int) []byte {
+	b := make([]byte, n)
+	g.rand.Read(b)
+	return b
+}
+
+// gasLimit returns the gas limit of a block with the given transactions, capped by the
+// block gas limit of the protocol.
+func (g *generator) gasLimit(txs types.Transactions) uint64 {
+	var gas uint64
+	for _, tx := range txs {
+		gas += tx.Gas()
+	}
+	if max := g.conf.BlockMaxGasLimit.Uint64(); gas > max {
+		gas = max
+	}
+	return gas
+}
+
+// list encodes the transactions, and derives the expectation from their outcomes.
+func (g *generator) list(txs ...genTx) (*txList, error) {
+	var (
+		raw types.Transactions
+		e   expectation
+	)
+	for _, tx := range txs {
+		raw = append(raw, tx.Transaction)
+		switch tx.outcome {
+		case included:
+			e.Included = append(e.Included, tx.Hash())
+		case optional:
+			e.Optional = append(e.Optional, tx.Hash())
+		case invalidating:
+			e.Invalid = true
+		}
+	}
+	if e.Invalid {
+		e = expectation{Invalid: true}
+	}
+	b, err := rlp.EncodeToBytes(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &txList{Bytes: b, GasLimit: g.gasLimit(raw), Expect: e}, nil
+}
+
+// invalid returns a list which the drivers can't decode.
+func (g *generator) invalid(b []byte) *txList {
+	return &txList{Bytes: b, GasLimit: g.randomGasLimit(), Expect: expectation{Invalid: true}}
+}
+
+func (g *generator) randomGasLimit() uint64 {
+	return uint64(g.rand.Int63n(g.conf.BlockMaxGasLimit.Int64()))
+}
+
+func randomBytes(g *generator) (*txList, error) {
+	return g.invalid(g.bytes(1 + g.rand.Intn(1024))), nil
+}
+
+func truncatedRLP(g *generator) (*txList, error) {
+	var txs []genTx
+	for i := 0; i < 1+g.rand.Intn(3); i++ {
+		txs = append(txs, genTx{g.transfer(g.nonce+uint64(i), nil), included})
+	}
+	l, err := g.list(txs...)
+	if err != nil {
+		return nil, err
+	}
+	return g.invalid(l.Bytes[:1+g.rand.Intn(len(l.Bytes)-1)]), nil
+}
+
+func nonTransactions(g *generator) (*txList, error) {
+	items := make([]interface{}, 1+g.rand.Intn(4))
+	for i := range items {
+		switch g.rand.Intn(3) {
+		case 0:
+			items[i] = g.bytes(g.rand.Intn(64))
+		case 1:
+			items[i] = g.rand.Uint64()
+		default:
+			items[i] = []interface{}{g.rand.Uint64(), g.bytes(g.rand.Intn(32))}
+		}
+	}
+	b, err := rlp.EncodeToBytes(items)
+	if err != nil {
+		return nil, err
+	}
+	return g.invalid(b), nil
+}
+
+// emptyList is valid, the drivers derive a block with just the anchor transaction.
+func emptyList(g *generator) (*txList, error) {
+	return g.list()
+}
+
+// oversizeList creates a transaction with more calldata than a transaction list may
+// have.
+func oversizeList(g *generator) (*txList, error) {
+	data := g.bytes(int(g.conf.MaxBytesPerTxList.Uint64()) + 1)
+	tx := g.transfer(g.nonce, func(tx *types.LegacyTx) {
+		tx.Data = data
+		tx.Gas = params.TxGas + params.TxDataNonZeroGasEIP2028*uint64(len(data))
+	})
+	return g.list(genTx{tx, invalidating})
+}
+
+// tooManyTxs creates valid transfers, one more than a block may have.
+func tooManyTxs(g *generator) (*txList, error) {
+	var txs []genTx
+	for i := uint64(0); i <= g.conf.MaxTransactionsPerBlock.Uint64(); i++ {
+		txs = append(txs, genTx{g.transfer(g.nonce+i, nil), included})
+	}
+	l, err := g.list(txs...)
+	if err != nil {
+		return nil, err
+	}
+	l.Expect = expectation{Invalid: true}
+	return l, nil
+}
+
+// duplicateNonces creates two transfers with the same nonce, the second is skipped.
+func duplicateNonces(g *generator) (*txList, error) {
+	return g.list(genTx{g.transfer(g.nonce, nil), included}, genTx{g.transfer(g.nonce, nil), skipped})
+}
+
+// spoofedAnchor creates anchor calls which are not the anchor transaction of the block:
+// one of the sender, which is included and reverts, and one of the golden touch
+// account with the nonce following the anchor transaction.
+func spoofedAnchor(g *generator) (*txList, error) {
+	fromSender, err := g.anchor(g.sender, g.nonce)
+	if err != nil {
+		return nil, err
+	}
+	fromGoldenTouch, err := g.anchor(g.goldenTouch, g.goldenTouchNonce+1)
+	if err != nil {
+		return nil, err
+	}
+	txs := []genTx{{fromSender, included}, {fromGoldenTouch, optional}}
+	g.rand.Shuffle(len(txs), func(i, j int) { txs[i], txs[j] = txs[j], txs[i] })
+	return g.list(txs...)
+}
+
+// mixed creates a valid transfer together with some invalid transactions, in random
+// order. The invalid transactions don't share the nonce of the transfer, or can't be
+// executed with it.
+func mixed(g *generator) (*txList, error) {
+	txs := []genTx{{g.transfer(g.nonce, nil), included}}
+	invalid := []func(*generator) genTx{
+		overGasLimit, wrongChainID, underpriced, intrinsicGas, nonceTooHigh, insufficientFunds,
+	}
+	for i := 0; i < 1+g.rand.Intn(5); i++ {
+		txs = append(txs, invalid[g.rand.Intn(len(invalid))](g))
+	}
+	g.rand.Shuffle(len(txs), func(i, j int) { txs[i], txs[j] = txs[j], txs[i] })
+	return g.list(txs...)
+}
+
+// single returns a case of a list with one transaction.
+func single(f func(*generator) genTx) func(*generator) (*txList, error) {
+	return func(g *generator) (*txList, error) {
+		return g.list(f(g))
+	}
+}
+
+// overGasLimit exceeds the gas limit of a block, which invalidates the list.
+func overGasLimit(g *generator) genTx {
+	return genTx{g.transfer(g.nonce, func(tx *types.LegacyTx) {
+		tx.Gas = g.conf.BlockMaxGasLimit.Uint64() + 1
+	}), invalidating}
+}
+
+// wrongChainID has a signature the driver can't recover the sender from, which
+// invalidates the list.
+func wrongChainID(g *generator) genTx {
+	tx := &types.LegacyTx{Nonce: g.nonce, GasPrice: g.gasPrice, Gas: params.TxGas, To: &common.Address{}}
+	return genTx{g.sign(g.sender, new(big.Int).Add(g.chainID, common.Big1), tx), invalidating}
+}
+
+// unprotected is only rejected by the transaction pool, blocks include it.
+func unprotected(g *generator) genTx {
+	tx, err := types.SignNewTx(g.sender, types.HomesteadSigner{}, &types.LegacyTx{
+		Nonce: g.nonce, GasPrice: g.gasPrice, Gas: params.TxGas, To: &common.Address{},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return genTx{tx, included}
+}
+
+// underpriced creates a dynamic fee transaction with a tip above its fee cap.
+func underpriced(g *generator) genTx {
+	return genTx{g.sign(g.sender, g.chainID, &types.DynamicFeeTx{
+		ChainID:   g.chainID,
+		Nonce:     g.nonce,
+		GasTipCap: new(big.Int).Add(g.gasPrice, common.Big1),
+		GasFeeCap: g.gasPrice,
+		Gas:       params.TxGas,
+		To:        &common.Address{},
+	}), skipped}
+}
+
+// intrinsicGas has the gas limit of a plain transfer, but calldata.
+func intrinsicGas(g *generator) genTx {
+	return genTx{g.transfer(g.nonce, func(tx *types.LegacyTx) {
+		tx.Data = g.bytes(1 + g.rand.Intn(64))
+		tx.Gas = params.TxGas
+	}), skipped}
+}
+
+func nonceTooHigh(g *generator) genTx {
+	return genTx{g.transfer(g.nonce+1024, nil), skipped}
+}
+
+func insufficientFunds(g *generator) genTx {
+	return genTx{g.transfer(g.nonce, func(tx *types.LegacyTx) {
+		tx.Value = new(big.Int).Lsh(common.Big1, 200)
+	}), skipped}
+}
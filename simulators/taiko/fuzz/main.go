@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/hive/hivesim"
+	"github.com/ethereum/hive/taiko"
+	"github.com/stretchr/testify/require"
+	"github.com/taikoxyz/taiko-client/bindings/encoding"
+	"github.com/taikoxyz/taiko-client/pkg/rpc"
+	"github.com/taikoxyz/taiko-client/proposer"
+)
+
+const (
+	envFuzzCases  = "HIVE_TAIKO_FUZZ_CASES"
+	envFuzzSeed   = "HIVE_TAIKO_FUZZ_SEED"
+	envFuzzReplay = "HIVE_TAIKO_FUZZ_REPLAY"
+)
+
+const defaultCases = 32
+
+// deriveTimeout is how long the L2 nodes may take to derive a proposed block.
+var deriveTimeout = 2 * time.Minute
+
+var tests = []*taiko.MatrixTest{
+	{
+		Name:        "Invalid proposal fuzzing",
+		Description: "Proposes malformed transaction lists to TaikoL1. Every L2 node derives the same block for each of them, and the chain continues afterwards.",
+		Run:         fuzzProposals,
+	},
+}
+
+func main() {
+	suite := hivesim.Suite{
+		Name:        "taiko-fuzz",
+		Description: "Proposes generated malformed transaction lists, and checks that the drivers derive the same blocks from them and don't halt.",
+	}
+	suite.Add(&hivesim.TestSpec{
+		Name: "Client launch",
+		Description: `This test launches the client and runs the test tool.
+		Results from the test tool are reported as individual sub-tests.`,
+		Run: func(t *hivesim.T) { runAllTests(t) },
+	})
+	sim := hivesim.New()
+	hivesim.MustRun(sim, suite)
+}
+
+func runAllTests(t *hivesim.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
+	defer cancel()
+	taiko.RunTests(t, ctx, &taiko.RunTestsParams{
+		MatrixTests: tests,
+		Concurrency: 1,
+	})
+}
+
+// fuzzInput is a proposed transaction list and what the drivers derive from it. It is
+// logged as JSON when the derivation breaks, and proposed again when given in
+// HIVE_TAIKO_FUZZ_REPLAY.
+type fuzzInput struct {
+	Seed     int64         `json:"seed"`
+	Index    int           `json:"index"`
+	Case     string        `json:"case"`
+	GasLimit uint64        `json:"gasLimit"`
+	TxList   hexutil.Bytes `json:"txList"`
+	Expect   expectation   `json:"expect"`
+	Error    string        `json:"error,omitempty"`
+}
+
+func fuzzProposals(t *hivesim.T, clients *taiko.ClientsByRole) {
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Minute)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartDevnet(&taiko.DevnetSpec{
+		L2NodeTypes: []string{"full", "full"},
+		Proposers:   1,
+		Provers:     1,
+	})
+	defer env.StopDevnet()
+
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	l2Cli, err := l2.EthClient()
+	require.NoError(t, err)
+	defer l2Cli.Close()
+	taikoL2, err := l2.TaikoL2Client()
+	require.NoError(t, err)
+
+	// The sender of the generated transactions is funded while the devnet proposer
+	// runs. Afterwards, only the test proposes blocks.
+	sender := env.L2Vault.CreateAccount(ctx, l2Cli, new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether)))
+	env.StopNode(env.Net.GetProposerNode(0))
+	p, err := taiko.NewProposer(t, env, taiko.NewProposerConfig(env, l1, l2))
+	require.NoError(t, err)
+
+	if v := os.Getenv(envFuzzReplay); v != "" {
+		in := new(fuzzInput)
+		require.NoError(t, json.Unmarshal([]byte(v), in), "invalid %s", envFuzzReplay)
+		mustPropose(env, p, in)
+	} else {
+		seed := envInt(t, envFuzzSeed, time.Now().UnixNano())
+		t.Logf("fuzzing with seed %d", seed)
+		g := &generator{
+			rand:        rand.New(rand.NewSource(seed)),
+			conf:        env.TaikoConf,
+			chainID:     env.Conf.L2.ChainID,
+			taikoL2:     taikoL2,
+			sender:      env.L2Vault.FindKey(sender),
+			goldenTouch: env.Conf.L2.Throwawayer.PrivateKey,
+		}
+		for i := 0; i < int(envInt(t, envFuzzCases, defaultCases)); i++ {
+			c := cases[g.rand.Intn(len(cases))]
+			require.NoError(t, g.refresh(ctx, l2Cli))
+			l, err := c.Gen(g)
+			require.NoError(t, err, "case %q", c.Name)
+			mustPropose(env, p, &fuzzInput{
+				Seed: seed, Index: i, Case: c.Name, GasLimit: l.GasLimit, TxList: l.Bytes, Expect: l.Expect,
+			})
+		}
+	}
+
+	// The drivers still derive valid blocks.
+	env.Net.Apply(taiko.WithProposerNode(env.NewProposerNode(l1, l2)))
+	env.GenSomeL2Blocks(t, 3)
+	head, err := l2Cli.BlockNumber(ctx)
+	require.NoError(t, err)
+	require.NoError(t, taiko.CheckL2Consistency(ctx, l1, env.Net.L2Engines, head))
+}
+
+// mustPropose proposes the transaction list of in, and fails the test with in logged
+// if the L2 nodes don't derive the expected block from it.
+func mustPropose(env *taiko.TestEnv, p *proposer.Proposer, in *fuzzInput) {
+	if err := propose(env, p, in); err != nil {
+		logInput(env.T, in, err)
+		env.T.Fatalf("%s proposal %d: %v", in.Case, in.Index, err)
+	}
+}
+
+// propose proposes the transaction list of in, and checks that all L2 nodes derive the
+// expected block from it. Proposals rejected by TaikoL1 are not an error.
+func propose(env *taiko.TestEnv, p *proposer.Proposer, in *fuzzInput) error {
+	l2Cli, err := env.Net.GetL2ELNode(0).EthClient()
+	if err != nil {
+		return err
+	}
+	defer l2Cli.Close()
+	// Only the test proposes blocks, and it waits for their derivation, so the head
+	// doesn't change until the proposal.
+	head, err := l2Cli.BlockNumber(env.Context)
+	if err != nil {
+		return err
+	}
+	id, ok, err := proposeTxList(env, p, in)
+	if err != nil || !ok {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(env.Context, deriveTimeout)
+	defer cancel()
+	if !in.Expect.Invalid {
+		if err := checkDerived(ctx, env, id, head+1, in.Expect); err != nil {
+			return err
+		}
+		return taiko.CheckL2Consistency(ctx, env.Net.GetL1ELNode(0), env.Net.L2Engines, head+1)
+	}
+
+	// The drivers may derive no block from an invalid list, so they are only known to
+	// be done with it when they derived the next proposal.
+	next := &fuzzInput{Case: "empty list after " + in.Case, Index: in.Index, GasLimit: params.TxGas, TxList: emptyTxList}
+	nextID, ok, err := proposeTxList(env, p, next)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("empty list after block %d was rejected", id)
+	}
+	height, err := derivedHeight(ctx, env, nextID)
+	if err != nil {
+		return err
+	}
+	switch height {
+	case head + 1:
+		env.T.Logf("%s proposal %d: block %d derived no L2 block", in.Case, in.Index, id)
+	case head + 2:
+		if err := checkDerived(ctx, env, id, head+1, in.Expect); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("block %d following invalid block %d derived L2 block %d, head was %d", nextID, id, height, head)
+	}
+	return taiko.CheckL2Consistency(ctx, env.Net.GetL1ELNode(0), env.Net.L2Engines, height)
+}
+
+// emptyTxList is the RLP encoding of an empty transaction list.
+var emptyTxList = []byte{0xc0}
+
+// proposeTxList commits and proposes the transaction list of in, and returns the
+// TaikoL1 block ID of the proposal. It reports false if TaikoL1 rejected it.
+func proposeTxList(env *taiko.TestEnv, p *proposer.Proposer, in *fuzzInput) (uint64, bool, error) {
+	t, ctx := env.T, env.Context
+	taikoL1, err := env.Net.GetL1ELNode(0).TaikoL1Client()
+	if err != nil {
+		return 0, false, err
+	}
+	before, err := rpc.GetProtocolStateVariables(taikoL1, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	meta, commitTx, err := p.CommitTxList(ctx, in.TxList, in.GasLimit, 0)
+	if err != nil {
+		t.Logf("%s proposal %d: commit rejected: %v", in.Case, in.Index, encoding.TryParsingCustomError(err))
+		return 0, false, nil
+	}
+	env.GenCommitDelayBlocks(t)
+	if err := p.ProposeTxList(ctx, meta, commitTx, in.TxList, 1); err != nil {
+		t.Logf("%s proposal %d: rejected by TaikoL1: %v", in.Case, in.Index, encoding.TryParsingCustomError(err))
+		after, err := rpc.GetProtocolStateVariables(taikoL1, nil)
+		if err != nil {
+			return 0, false, err
+		}
+		if after.NextBlockId != before.NextBlockId {
+			return 0, false, fmt.Errorf("failed proposal created block %d", before.NextBlockId)
+		}
+		return 0, false, nil
+	}
+	return before.NextBlockId, true, nil
+}
+
+// derivedHeight waits until all L2 nodes derived the block with the given ID, and
+// returns the height of the L2 block they derived from it.
+func derivedHeight(ctx context.Context, env *taiko.TestEnv, id uint64) (uint64, error) {
+	var height uint64
+	for i, n := range env.Net.L2Engines {
+		origin, err := taiko.WaitL1Origin(ctx, n, id)
+		if err != nil {
+			return 0, err
+		}
+		cli, err := n.EthClient()
+		if err != nil {
+			return 0, err
+		}
+		header, err := cli.HeaderByHash(ctx, origin.L2BlockHash)
+		cli.Close()
+		if err != nil {
+			return 0, fmt.Errorf("%s: can't get L2 block %v derived from block %d: %w", n.Container, origin.L2BlockHash, id, err)
+		}
+		num := header.Number.Uint64()
+		if i == 0 {
+			height = num
+		} else if num != height {
+			return 0, fmt.Errorf("block %d derived L2 block %d on %s, and %d on %s",
+				id, height, env.Net.L2Engines[0].Container, num, n.Container)
+		}
+	}
+	return height, nil
+}
+
+// checkDerived waits until all L2 nodes derived the block with the given ID, and checks
+// that they derived the L2 block at height with the expected transactions from it.
+func checkDerived(ctx context.Context, env *taiko.TestEnv, id, height uint64, e expectation) error {
+	derived, err := derivedHeight(ctx, env, id)
+	if err != nil {
+		return err
+	}
+	if derived != height {
+		return fmt.Errorf("block %d derived L2 block %d, want %d", id, derived, height)
+	}
+	cli, err := env.Net.GetL2ELNode(0).EthClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+	block, err := cli.BlockByNumber(ctx, new(big.Int).SetUint64(height))
+	if err != nil {
+		return err
+	}
+	return checkTxs(block, e)
+}
+
+// checkTxs verifies that the transactions following the anchor transaction of the
+// block are the expected ones.
+func checkTxs(block *types.Block, e expectation) error {
+	txs := block.Transactions()
+	if len(txs) == 0 {
+		return fmt.Errorf("L2 block %d has no anchor transaction", block.Number())
+	}
+	got := make(map[common.Hash]bool)
+	for _, tx := range txs[1:] {
+		got[tx.Hash()] = true
+	}
+	for _, h := range e.Included {
+		if !got[h] {
+			return fmt.Errorf("L2 block %d doesn't include transaction %v", block.Number(), h)
+		}
+		delete(got, h)
+	}
+	for _, h := range e.Optional {
+		delete(got, h)
+	}
+	for h := range got {
+		return fmt.Errorf("L2 block %d includes unexpected transaction %v", block.Number(), h)
+	}
+	return nil
+}
+
+// logInput logs a failing input in the test details of the hive results.
+func logInput(t *hivesim.T, in *fuzzInput, err error) {
+	in.Error = err.Error()
+	enc, _ := json.Marshal(in)
+	t.Logf("failing input, replay with %s:\n%s", envFuzzReplay, enc)
+}
+
+func envInt(t *hivesim.T, key string, def int64) int64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	require.NoError(t, err, "invalid %s", key)
+	return n
+}
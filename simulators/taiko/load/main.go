@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/hive/hivesim"
+	"github.com/ethereum/hive/taiko"
+	"github.com/stretchr/testify/require"
+)
+
+var tests = []*taiko.MatrixTest{
+	{
+		Name:        "Mixed load",
+		Description: "Transfers, contract deployments, storage writes and large calldata transactions of all types are sent at a fixed rate. All of them are included, proposed and verified.",
+		Run:         mixedLoad,
+	},
+	{
+		Name:        "Large calldata load",
+		Description: "Transactions with large calldata fill the transaction lists of the proposer. All of them are included, proposed and verified.",
+		Run:         calldataLoad,
+	},
+	{
+		Name:        "Load across L2 nodes",
+		Description: "Transactions are sent to all L2 nodes of a devnet. They reach the proposer through p2p, and all L2 nodes end up with the same chain.",
+		Run:         multiNodeLoad,
+	},
+}
+
+func main() {
+	suite := hivesim.Suite{
+		Name:        "taiko-load",
+		Description: "Sends L2 transactions at a fixed rate and measures how long it takes to include, propose and verify them.",
+	}
+	suite.Add(&hivesim.TestSpec{
+		Name: "Client launch",
+		Description: `This test launches the client and runs the test tool.
+		Results from the test tool are reported as individual sub-tests.`,
+		Run: func(t *hivesim.T) { runAllTests(t) },
+	})
+	sim := hivesim.New()
+	hivesim.MustRun(sim, suite)
+}
+
+var defaultTimeout = 20 * time.Minute
+
+// loadSpec returns the default load, with the rate and duration taken from
+// HIVE_TAIKO_LOAD_TPS and HIVE_TAIKO_LOAD_DURATION if they are set.
+func loadSpec(t *hivesim.T) *taiko.LoadSpec {
+	spec := taiko.DefaultLoadSpec()
+	if v, ok := os.LookupEnv("HIVE_TAIKO_LOAD_TPS"); ok {
+		tps, err := strconv.Atoi(v)
+		require.NoError(t, err, "invalid HIVE_TAIKO_LOAD_TPS")
+		spec.TPS = tps
+	}
+	if v, ok := os.LookupEnv("HIVE_TAIKO_LOAD_DURATION"); ok {
+		d, err := time.ParseDuration(v)
+		require.NoError(t, err, "invalid HIVE_TAIKO_LOAD_DURATION")
+		spec.Duration = d
+	}
+	return spec
+}
+
+func mixedLoad(t *hivesim.T, clients *taiko.ClientsByRole) {
+	spec := loadSpec(t)
+	spec.Mix = map[taiko.LoadKind]int{
+		taiko.LoadTransfer: 4,
+		taiko.LoadDeploy:   1,
+		taiko.LoadStorage:  2,
+		taiko.LoadCalldata: 1,
+	}
+	spec.TxTypes = []uint8{types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartSingleNodeNet()
+	defer env.StopSingleNodeNet()
+
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	runLoad(t, env, l1, []*taiko.ELNode{l2}, spec)
+}
+
+func calldataLoad(t *hivesim.T, clients *taiko.ClientsByRole) {
+	spec := loadSpec(t)
+	spec.Mix = map[taiko.LoadKind]int{taiko.LoadCalldata: 1}
+	spec.CalldataSize = 32 * 1024
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartSingleNodeNet()
+	defer env.StopSingleNodeNet()
+
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	runLoad(t, env, l1, []*taiko.ELNode{l2}, spec)
+}
+
+func multiNodeLoad(t *hivesim.T, clients *taiko.ClientsByRole) {
+	spec := loadSpec(t)
+	spec.Mix = map[taiko.LoadKind]int{taiko.LoadTransfer: 3, taiko.LoadStorage: 1}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartDevnet(&taiko.DevnetSpec{
+		L2NodeTypes: []string{"full", "full"},
+		Proposers:   1,
+		Provers:     1,
+		EnableL2P2P: true,
+	})
+	defer env.StopDevnet()
+
+	l1 := env.Net.GetL1ELNode(0)
+	height := runLoad(t, env, l1, env.Net.L2Engines, spec)
+	require.NoError(t, taiko.CheckL2Consistency(ctx, l1, env.Net.L2Engines, height))
+}
+
+// runLoad runs the load, waits until all of it is verified, and reports the summary
+// and the lifecycle of the L2 blocks. It returns the L2 height after the load.
+func runLoad(t *hivesim.T, env *taiko.TestEnv, l1 *taiko.ELNode, l2s []*taiko.ELNode, spec *taiko.LoadSpec) uint64 {
+	ctx := env.Context
+	g := env.NewLoadGenerator(l1, l2s, spec)
+	defer func() {
+		require.NoError(t, g.Close())
+	}()
+
+	err := g.Run(ctx)
+	if err == nil {
+		err = g.WaitVerified(ctx)
+	}
+	s := g.Summary()
+	t.Logf("load summary:\n%v", s)
+	t.Logf("block lifecycle:\n%v", g.Tracker().Report())
+	require.NoError(t, err)
+	require.Zero(t, s.Failed, "transactions could not be sent")
+	require.Equal(t, s.Sent, s.Included)
+	require.NoError(t, g.Tracker().CheckHashes())
+
+	cli, err := l2s[0].EthClient()
+	require.NoError(t, err)
+	height, err := cli.BlockNumber(ctx)
+	require.NoError(t, err)
+	return height
+}
+
+func runAllTests(t *hivesim.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	taiko.RunTests(t, ctx, &taiko.RunTestsParams{
+		MatrixTests: tests,
+		Concurrency: 1,
+	})
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/hive/hivesim"
+	"github.com/ethereum/hive/taiko"
+	"github.com/stretchr/testify/require"
+	"github.com/taikoxyz/taiko-client/proposer"
+	"github.com/taikoxyz/taiko-client/testutils"
+)
+
+var tests = []*taiko.MatrixTest{
+	{
+		Name:        "Suggested fee recipient",
+		Description: "Proposed blocks and the resulting L2 blocks pay fees to the configured recipient.",
+		Run:         feeRecipient,
+	},
+	{
+		Name:        "Transaction list limits under load",
+		Description: "The L2 mempool holds more transactions than fit into a block. With the default limits, a lower max gas per block and a lower max transaction list size configured in the proposer, all proposed transaction lists respect the proposer limits and those of TaikoDataConfig.",
+		Run:         txListLimits,
+	},
+	{
+		Name:        "Local addresses first",
+		Description: "Transactions of local addresses are proposed before better paying transactions of other accounts.",
+		Run:         localAddressesFirst,
+	},
+	{
+		Name:        "Typed transactions",
+		Description: "Legacy, EIP-2930 and EIP-1559 transactions are proposed and included in L2 blocks. Their fees cover the L2 base fee.",
+		Run:         typedTransactions,
+	},
+}
+
+func main() {
+	suite := hivesim.Suite{
+		Name:        "taiko-proposer",
+		Description: "Tests how the proposer selects transactions from the L2 mempool.",
+	}
+	suite.Add(&hivesim.TestSpec{
+		Name: "Client launch",
+		Description: `This test launches the client and runs the test tool.
+		Results from the test tool are reported as individual sub-tests.`,
+		Run: func(t *hivesim.T) { runAllTests(t) },
+	})
+	sim := hivesim.New()
+	hivesim.MustRun(sim, suite)
+}
+
+var (
+	defaultTimeout = 10 * time.Minute
+	accountFunding = new(big.Int).Mul(big.NewInt(10), big.NewInt(params.Ether))
+)
+
+func feeRecipient(t *hivesim.T, clients *taiko.ClientsByRole) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartL1L2Driver(taiko.WithELNodeType("full"))
+	defer env.StopDevnet()
+
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	l2Cli := l2.MustEthClient(t)
+	recipient := common.BytesToAddress(testutils.RandomBytes(20))
+	c := taiko.NewProposerConfig(env, l1, l2)
+	c.L2SuggestedFeeRecipient = recipient
+	p, err := taiko.NewProposer(t, env, c)
+	require.NoError(t, err)
+
+	start := l1Height(t, ctx, l1)
+	require.NoError(t, env.L2Vault.SendTestTx(ctx, l2Cli, nil))
+	require.NoError(t, p.ProposeOp(ctx))
+	blocks := waitProposed(t, env, l1, start)
+	for _, b := range blocks {
+		require.Equal(t, recipient, b.Meta.Beneficiary, "beneficiary of block %d", b.Meta.Id)
+	}
+
+	last := blocks[len(blocks)-1].Meta.Id
+	require.NoError(t, taiko.WaitHeight(ctx, l2, taiko.GreaterEqual(last.Uint64())))
+	header, err := l2Cli.HeaderByNumber(ctx, last)
+	require.NoError(t, err)
+	require.Equal(t, recipient, header.Coinbase, "coinbase of L2 block %d", last)
+}
+
+// txListLimitCase configures the transaction list limits of the proposer. Zero limits
+// are left to the protocol. The limits are lowered so that the mempool load exceeds them
+// by far.
+type txListLimitCase struct {
+	name              string
+	blockMaxGasLimit  uint64
+	maxBytesPerTxList uint64
+}
+
+var txListLimitCases = []txListLimitCase{
+	{name: "default limits"},
+	{name: "max gas 3000000", blockMaxGasLimit: 3000000},
+	{name: "max bytes 20000", maxBytesPerTxList: 20000},
+}
+
+func txListLimits(t *hivesim.T, clients *taiko.ClientsByRole) {
+	for _, limits := range txListLimitCases {
+		limits := limits
+		t.Run(hivesim.TestSpec{
+			Name: "Transaction list limits, " + limits.name,
+			Run:  func(t *hivesim.T) { txListLimitsWith(t, clients, limits) },
+		})
+	}
+}
+
+func txListLimitsWith(t *hivesim.T, clients *taiko.ClientsByRole, limits txListLimitCase) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartL1L2Driver(taiko.WithELNodeType("full"))
+	defer env.StopDevnet()
+
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	l2Cli := l2.MustEthClient(t)
+	c := taiko.NewProposerConfig(env, l1, l2)
+	// The proposed transaction lists must respect both the proposer limits and the
+	// limits of the TaikoL1 contract.
+	conf := *env.TaikoConf
+	if limits.blockMaxGasLimit != 0 {
+		require.Less(t, limits.blockMaxGasLimit, env.TaikoConf.BlockMaxGasLimit.Uint64())
+		c.BlockMaxGasLimit = &limits.blockMaxGasLimit
+		conf.BlockMaxGasLimit = new(big.Int).SetUint64(limits.blockMaxGasLimit)
+	}
+	if limits.maxBytesPerTxList != 0 {
+		require.Less(t, limits.maxBytesPerTxList, env.TaikoConf.MaxBytesPerTxList.Uint64())
+		c.MaxBytesPerTxList = &limits.maxBytesPerTxList
+		conf.MaxBytesPerTxList = new(big.Int).SetUint64(limits.maxBytesPerTxList)
+	}
+	p, err := taiko.NewProposer(t, env, c)
+	require.NoError(t, err)
+
+	// The transactions together are larger than the transaction list limit. With the
+	// lowered max gas, they also use more gas than fits into a block.
+	txCnt := 2*conf.MaxTransactionsPerBlock.Uint64() + 1
+	dataSize := int(conf.MaxBytesPerTxList.Uint64()/txCnt) * 4
+	data := []byte(strings.Repeat("x", dataSize))
+	var gas uint64
+	for i := uint64(0); i < txCnt; i++ {
+		require.NoError(t, env.L2Vault.SendTestTx(ctx, l2Cli, data))
+		gas += params.TxGas + uint64(dataSize)*params.TxDataNonZeroGasEIP2028
+	}
+	if limits.blockMaxGasLimit != 0 {
+		require.Greater(t, gas, limits.blockMaxGasLimit, "load doesn't exceed the max gas")
+	}
+
+	start := l1Height(t, ctx, l1)
+	require.NoError(t, p.ProposeOp(ctx))
+	blocks := waitProposed(t, env, l1, start)
+	var proposed int
+	for _, b := range blocks {
+		require.NoError(t, taiko.CheckTxListLimits(&conf, b))
+		proposed += len(b.Txs)
+	}
+	require.NotZero(t, proposed, "no transactions proposed")
+	t.Logf("proposed %d of %d transactions in %d blocks", proposed, txCnt, len(blocks))
+}
+
+func localAddressesFirst(t *hivesim.T, clients *taiko.ClientsByRole) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartL1L2Driver(taiko.WithELNodeType("full"))
+	defer env.StopDevnet()
+
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	l2Cli := l2.MustEthClient(t)
+
+	// Fund the accounts while a default proposer is running.
+	funder, err := taiko.NewProposer(t, env, taiko.NewProposerConfig(env, l1, l2))
+	require.NoError(t, err)
+	stop := proposeInBackground(ctx, funder)
+	local, remote := env.L2Vault.GenerateKey(), env.L2Vault.GenerateKey()
+	err = env.L2Vault.Fund(ctx, l2Cli, []common.Address{local, remote}, []*big.Int{accountFunding, accountFunding})
+	stop()
+	require.NoError(t, err)
+
+	c := taiko.NewProposerConfig(env, l1, l2)
+	c.LocalAddresses = []common.Address{local}
+	c.ShufflePoolContent = false
+	p, err := taiko.NewProposer(t, env, c)
+	require.NoError(t, err)
+
+	gasPrice, err := l2Cli.SuggestGasPrice(ctx)
+	require.NoError(t, err)
+	sendTransfers(t, env, l2Cli, remote, 3, new(big.Int).Mul(gasPrice, big.NewInt(10)))
+	sendTransfers(t, env, l2Cli, local, 3, gasPrice)
+
+	start := l1Height(t, ctx, l1)
+	require.NoError(t, p.ProposeOp(ctx))
+	blocks := waitProposed(t, env, l1, start)
+	signer := types.LatestSignerForChainID(env.Conf.L2.ChainID)
+	require.NotEmpty(t, blocks[0].Txs, "no transactions proposed in block %d", blocks[0].Meta.Id)
+	first, err := types.Sender(signer, blocks[0].Txs[0])
+	require.NoError(t, err)
+	require.Equal(t, local, first, "first proposed transaction not from the local address")
+	seenRemote := false
+	for _, tx := range blocks[0].Txs {
+		sender, err := types.Sender(signer, tx)
+		require.NoError(t, err)
+		switch sender {
+		case remote:
+			seenRemote = true
+		case local:
+			require.False(t, seenRemote, "transaction %v of local address proposed after remote transaction", tx.Hash())
+		}
+	}
+}
+
+func typedTransactions(t *hivesim.T, clients *taiko.ClientsByRole) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartL1L2Driver(taiko.WithELNodeType("full"))
+	defer env.StopDevnet()
+
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	l2Cli := l2.MustEthClient(t)
+	p, err := taiko.NewProposer(t, env, taiko.NewProposerConfig(env, l1, l2))
+	require.NoError(t, err)
+	stop := proposeInBackground(ctx, p)
+	defer stop()
+
+	from, to := env.L2Vault.GenerateKey(), env.L2Vault.GenerateKey()
+	require.NoError(t, env.L2Vault.Fund(ctx, l2Cli, []common.Address{from}, []*big.Int{accountFunding}))
+	for _, typ := range []uint8{types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType} {
+		tx, err := env.L2Vault.SendTx(ctx, l2Cli, typ, from, &to, common.Big1, nil)
+		require.NoError(t, err, "transaction type %d", typ)
+		receipt, err := taiko.WaitReceiptOK(ctx, l2Cli, tx.Hash())
+		require.NoError(t, err, "transaction type %d", typ)
+		require.Equal(t, typ, receipt.Type)
+		header, err := l2Cli.HeaderByNumber(ctx, receipt.BlockNumber)
+		require.NoError(t, err)
+		if header.BaseFee != nil {
+			require.GreaterOrEqual(t, tx.GasFeeCap().Cmp(header.BaseFee), 0,
+				"fee cap %v of transaction type %d below base fee %v", tx.GasFeeCap(), typ, header.BaseFee)
+		}
+	}
+}
+
+// waitProposed waits until a block is proposed since the given L1 block, and returns
+// all blocks proposed since then.
+func waitProposed(t *hivesim.T, env *taiko.TestEnv, l1 *taiko.ELNode, start uint64) []*taiko.ProposedBlock {
+	ctx := env.Context
+	for {
+		blocks, err := taiko.ProposedBlocks(ctx, l1, start)
+		require.NoError(t, err)
+		if len(blocks) > 0 {
+			return blocks
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("no block proposed since L1 block %d", start)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// proposeInBackground proposes the pending L2 transactions until stop is called.
+func proposeInBackground(ctx context.Context, p *proposer.Proposer) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			p.ProposeOp(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// sendTransfers sends cnt value transfers from an account of the L2 vault to itself.
+func sendTransfers(t *hivesim.T, env *taiko.TestEnv, cli *ethclient.Client, from common.Address, cnt int, gasPrice *big.Int) {
+	ctx := env.Context
+	nonce, err := cli.PendingNonceAt(ctx, from)
+	require.NoError(t, err)
+	for i := 0; i < cnt; i++ {
+		tx := types.NewTx(&types.LegacyTx{
+			Nonce:    nonce + uint64(i),
+			Gas:      params.TxGas,
+			GasPrice: gasPrice,
+			To:       &from,
+			Value:    common.Big1,
+		})
+		tx, err := env.L2Vault.SignTransaction(from, tx)
+		require.NoError(t, err)
+		require.NoError(t, cli.SendTransaction(ctx, tx))
+	}
+}
+
+func l1Height(t *hivesim.T, ctx context.Context, l1 *taiko.ELNode) uint64 {
+	height, err := l1.MustEthClient(t).BlockNumber(ctx)
+	require.NoError(t, err)
+	return height
+}
+
+func runAllTests(t *hivesim.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+	taiko.RunTests(t, ctx, &taiko.RunTestsParams{
+		MatrixTests: tests,
+		Concurrency: 3,
+	})
+}
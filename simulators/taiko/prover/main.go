@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/hive/hivesim"
+	"github.com/ethereum/hive/taiko"
+	"github.com/stretchr/testify/require"
+	"github.com/taikoxyz/taiko-client/bindings"
+	"github.com/taikoxyz/taiko-client/bindings/encoding"
+	"github.com/taikoxyz/taiko-client/pkg/rpc"
+	"github.com/taikoxyz/taiko-client/testutils"
+)
+
+var tests = []*taiko.MatrixTest{
+	{
+		Name:        "Prover stopped mid-way",
+		Description: "Blocks stay pending while the prover is stopped, and are verified once a new prover starts.",
+		Run:         proverStopped,
+	},
+	{
+		Name:        "Competing provers",
+		Description: "Two provers prove the same blocks. Every block is proven once, by either prover, and verified once.",
+		Run:         competingProvers,
+	},
+	{
+		Name:        "Out of order proofs",
+		Description: "Proofs are submitted in random order, blocks are still verified in order.",
+		Run:         outOfOrderProofs,
+	},
+	{
+		Name:        "Invalid proofs",
+		Description: "TaikoL1 rejects malformed proofs and proofs of blocks which were not proposed.",
+		Run:         invalidProofs,
+	},
+	{
+		Name:        "Proofs after the proof time cap",
+		Description: "Blocks proven after the proof time cap has passed on L1 are still verified. Needs the taiko-l1-dev client.",
+		Run:         lateProofs,
+	},
+	{
+		Name:        "Proofs in full L1 blocks",
+		Description: "Blocks are verified while the L1 blocks are filled up to their gas limit.",
+		Run:         fullL1Blocks,
+	},
+}
+
+func main() {
+	suite := hivesim.Suite{
+		Name:        "taiko-prover",
+		Description: "Tests the proving and verification of L2 blocks by TaikoL1 when provers fail or compete.",
+	}
+	suite.Add(&hivesim.TestSpec{
+		Name: "Client launch",
+		Description: `This test launches the client and runs the test tool.
+		Results from the test tool are reported as individual sub-tests.`,
+		Run: func(t *hivesim.T) { runAllTests(t) },
+	})
+	sim := hivesim.New()
+	hivesim.MustRun(sim, suite)
+}
+
+var (
+	defaultTimeout = 10 * time.Minute
+	accountFunding = new(big.Int).Mul(big.NewInt(10), big.NewInt(params.Ether))
+)
+
+func proverStopped(t *hivesim.T, clients *taiko.ClientsByRole) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartSingleNodeNet()
+	defer env.StopSingleNodeNet()
+
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	env.GenSomeL2Blocks(t, 3)
+	waitVerified(t, env, l1, l2Height(t, ctx, l2))
+
+	env.StopNode(env.Net.GetProverNode(0))
+	before := protocolState(t, l1)
+	blockCnt := uint64(5)
+	env.GenSomeL2Blocks(t, blockCnt)
+	after := protocolState(t, l1)
+	require.Equal(t, before.LatestVerifiedId, after.LatestVerifiedId, "blocks verified without prover")
+	require.Equal(t, before.LatestVerifiedHeight, after.LatestVerifiedHeight, "blocks verified without prover")
+	// The L2 blocks may include blocks proposed before the prover stopped, so the
+	// proposals are counted on L1.
+	proposed := after.NextBlockId - before.NextBlockId
+	require.NotZero(t, proposed, "no blocks proposed")
+	require.Equal(t, pendingBlocks(before)+proposed, pendingBlocks(after), "proposed blocks not pending")
+
+	env.Net.Apply(taiko.WithProverNode(env.NewProverNode(l1, l2)))
+	waitVerified(t, env, l1, l2Height(t, ctx, l2))
+}
+
+func competingProvers(t *hivesim.T, clients *taiko.ClientsByRole) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartSingleNodeNet()
+	defer env.StopSingleNodeNet()
+
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	l1Cli, err := l1.EthClient()
+	require.NoError(t, err)
+	prover := env.L1Vault.CreateAccount(ctx, l1Cli, accountFunding)
+	key := common.Bytes2Hex(crypto.FromECDSA(env.L1Vault.FindKey(prover)))
+	env.Net.Apply(taiko.WithProverNode(env.NewProverNode(l1, l2, taiko.WithProverPrivateKey(key))))
+
+	env.GenSomeL2Blocks(t, 5)
+	height := l2Height(t, ctx, l2)
+	waitVerified(t, env, l1, height)
+	checkVerifiedInOrder(t, env, l1, height)
+
+	checkProvenOnce(t, env, l1, protocolState(t, l1).LatestVerifiedId, env.Conf.L2.Prover.Address, prover)
+}
+
+// checkProvenOnce checks that the blocks up to id were proven once each, by one of
+// the given provers.
+func checkProvenOnce(t *hivesim.T, env *taiko.TestEnv, l1 *taiko.ELNode, id uint64, provers ...common.Address) {
+	taikoL1, err := l1.TaikoL1Client()
+	require.NoError(t, err)
+	iter, err := taikoL1.FilterBlockProven(&bind.FilterOpts{Start: 0, Context: env.Context}, nil)
+	require.NoError(t, err)
+	defer iter.Close()
+
+	proofs := make(map[uint64]int)
+	byProver := make(map[common.Address]int)
+	for iter.Next() {
+		e := iter.Event
+		if e.Id.Uint64() > id {
+			continue
+		}
+		require.Contains(t, provers, e.Prover, "block %d proven by unknown prover", e.Id)
+		proofs[e.Id.Uint64()]++
+		byProver[e.Prover]++
+	}
+	require.NoError(t, iter.Error())
+	for i := uint64(1); i <= id; i++ {
+		require.Equal(t, 1, proofs[i], "number of proofs of block %d", i)
+	}
+	t.Logf("proofs by prover: %v", byProver)
+}
+
+func outOfOrderProofs(t *hivesim.T, clients *taiko.ClientsByRole) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartDevnet(&taiko.DevnetSpec{
+		L2NodeTypes: []string{"full"},
+		Proposers:   1,
+	})
+	defer env.StopDevnet()
+
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	env.Net.Apply(taiko.WithProverNode(env.NewProverNode(l1, l2, taiko.WithRandomDummyProofDelay("1s-10s"))))
+	tracker, err := taiko.StartBlockTracker(ctx, l1, l2)
+	require.NoError(t, err)
+	defer tracker.Stop()
+
+	env.GenSomeL2Blocks(t, 10)
+	height := l2Height(t, ctx, l2)
+	require.NoError(t, tracker.WaitVerified(ctx, height))
+	checkVerifiedInOrder(t, env, l1, height)
+	require.NoError(t, tracker.Stop())
+	t.Logf("block lifecycle:\n%v", tracker.Report())
+	require.NoError(t, tracker.CheckHashes())
+}
+
+func invalidProofs(t *hivesim.T, clients *taiko.ClientsByRole) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartDevnet(&taiko.DevnetSpec{
+		L2NodeTypes: []string{"full"},
+		Proposers:   1,
+	})
+	defer env.StopDevnet()
+
+	l1 := env.Net.GetL1ELNode(0)
+	l1Cli, err := l1.EthClient()
+	require.NoError(t, err)
+	taikoL1, err := l1.TaikoL1Client()
+	require.NoError(t, err)
+	prover := env.L1Vault.CreateAccount(ctx, l1Cli, accountFunding)
+	env.GenSomeL2Blocks(t, 1)
+	before := protocolState(t, l1)
+
+	malformed := [][]byte{testutils.RandomBytes(256), testutils.RandomBytes(256), testutils.RandomBytes(256)}
+	for _, id := range []uint64{1, before.NextBlockId + 10} {
+		opts := env.L1Vault.KeyedTransactor(prover)
+		opts.Context = ctx
+		_, err := taikoL1.ProveBlock(opts, new(big.Int).SetUint64(id), malformed)
+		require.Error(t, err, "proof of block %d accepted", id)
+		t.Logf("proof of block %d rejected: %v", id, encoding.TryParsingCustomError(err))
+	}
+
+	after := protocolState(t, l1)
+	require.Equal(t, before.LatestVerifiedId, after.LatestVerifiedId)
+	require.Equal(t, before.LatestVerifiedHeight, after.LatestVerifiedHeight)
+}
+
+func lateProofs(t *hivesim.T, clients *taiko.ClientsByRole) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartDevnet(&taiko.DevnetSpec{
+		L2NodeTypes: []string{"full"},
+		Proposers:   1,
+		L1DevMode:   true,
+	})
+	defer env.StopDevnet()
+
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	ctrl, err := env.NewL1Controller(l1)
+	require.NoError(t, err)
+	defer ctrl.Close()
+	require.True(t, ctrl.DevMode(), "%s has no dev mode", l1.Container)
+
+	env.GenSomeL2Blocks(t, 3)
+	height := l2Height(t, ctx, l2)
+	last := protocolState(t, l1).NextBlockId - 1
+	proposed, err := taiko.WaitBlockProposed(ctx, l1, last)
+	require.NoError(t, err)
+	proposedAt := l1BlockTime(t, ctx, l1, proposed.Raw.BlockHash)
+
+	// Without a prover, the L1 time passes the proof time cap of the proposed blocks.
+	proofTimeCap := time.Duration(env.TaikoConf.ProofTimeCap) * time.Second
+	head, err := ctrl.AdvanceTime(ctx, proofTimeCap+time.Second)
+	require.NoError(t, err)
+	require.Greater(t, head.Time, proposedAt+env.TaikoConf.ProofTimeCap)
+
+	env.Net.Apply(taiko.WithProverNode(env.NewProverNode(l1, l2)))
+	waitVerified(t, env, l1, height)
+	proven, err := taiko.WaitBlockProven(ctx, l1, last)
+	require.NoError(t, err)
+	provenAt := l1BlockTime(t, ctx, l1, proven.Raw.BlockHash)
+	require.Greater(t, provenAt-proposedAt, env.TaikoConf.ProofTimeCap, "block %d proven within the proof time cap", last)
+}
+
+func fullL1Blocks(t *hivesim.T, clients *taiko.ClientsByRole) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartSingleNodeNet()
+	defer env.StopSingleNodeNet()
+
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	ctrl, err := env.NewL1Controller(l1)
+	require.NoError(t, err)
+	defer ctrl.Close()
+
+	env.GenSomeL2Blocks(t, 3)
+	height := l2Height(t, ctx, l2)
+	// The proofs compete with the transactions filling the blocks.
+	require.NoError(t, ctrl.MineFull(ctx, 5))
+	waitVerified(t, env, l1, height)
+	checkVerifiedInOrder(t, env, l1, height)
+}
+
+// waitVerified waits until all L2 blocks up to height are verified on L1.
+func waitVerified(t *hivesim.T, env *taiko.TestEnv, l1 *taiko.ELNode, height uint64) {
+	err := taiko.WaitStateChange(env.Context, l1, func(s *bindings.LibUtilsStateVariables) bool {
+		return s.LatestVerifiedHeight >= height
+	})
+	require.NoError(t, err, "L2 block %d not verified", height)
+}
+
+// checkVerifiedInOrder checks that the blocks up to id were verified one after
+// another, each of them once.
+func checkVerifiedInOrder(t *hivesim.T, env *taiko.TestEnv, l1 *taiko.ELNode, id uint64) {
+	taikoL1, err := l1.TaikoL1Client()
+	require.NoError(t, err)
+	iter, err := taikoL1.FilterBlockVerified(&bind.FilterOpts{Start: 0, Context: env.Context}, nil)
+	require.NoError(t, err)
+	defer iter.Close()
+
+	next := uint64(1)
+	for iter.Next() && next <= id {
+		got := iter.Event.Id.Uint64()
+		require.Equal(t, next, got, "blocks verified out of order")
+		next++
+	}
+	require.NoError(t, iter.Error())
+	require.Greater(t, next, id, "block %d not verified", next)
+}
+
+func protocolState(t *hivesim.T, l1 *taiko.ELNode) *bindings.LibUtilsStateVariables {
+	taikoL1, err := l1.TaikoL1Client()
+	require.NoError(t, err)
+	s, err := rpc.GetProtocolStateVariables(taikoL1, nil)
+	require.NoError(t, err)
+	return s
+}
+
+// pendingBlocks returns the number of proposed blocks which are not verified yet.
+func pendingBlocks(s *bindings.LibUtilsStateVariables) uint64 {
+	return s.NextBlockId - s.LatestVerifiedId - 1
+}
+
+// l1BlockTime returns the timestamp of an L1 block.
+func l1BlockTime(t *hivesim.T, ctx context.Context, l1 *taiko.ELNode, hash common.Hash) uint64 {
+	cli, err := l1.EthClient()
+	require.NoError(t, err)
+	defer cli.Close()
+	header, err := cli.HeaderByHash(ctx, hash)
+	require.NoError(t, err)
+	return header.Time
+}
+
+func l2Height(t *hivesim.T, ctx context.Context, l2 *taiko.ELNode) uint64 {
+	cli, err := l2.EthClient()
+	require.NoError(t, err)
+	height, err := cli.BlockNumber(ctx)
+	require.NoError(t, err)
+	return height
+}
+
+func runAllTests(t *hivesim.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+	taiko.RunTests(t, ctx, &taiko.RunTestsParams{
+		MatrixTests: tests,
+		Concurrency: 4,
+	})
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/hive/hivesim"
+	"github.com/ethereum/hive/taiko"
+	"github.com/stretchr/testify/require"
+)
+
+var tests = []*taiko.MatrixTest{
+	{
+		Name:        "L1 reorg drops proposed blocks",
+		Description: "The L1 blocks containing proposeBlock transactions are replaced by a longer chain. The driver rewinds the L2 chain and derives the blocks again.",
+		Run:         reorgDropsProposals,
+	},
+	{
+		Name:        "L1 reorg without proposals",
+		Description: "The L1 blocks replaced by a reorg contain no proposeBlock transactions. The L2 chain stays the same.",
+		Run:         reorgWithoutProposals,
+	},
+}
+
+func main() {
+	suite := hivesim.Suite{
+		Name:        "taiko-reorg",
+		Description: "Tests how the driver follows reorgs of the L1 chain.",
+	}
+	suite.Add(&hivesim.TestSpec{
+		Name: "Client launch",
+		Description: `This test launches the client and runs the test tool.
+		Results from the test tool are reported as individual sub-tests.`,
+		Run: func(t *hivesim.T) { runAllTests(t) },
+	})
+	sim := hivesim.New()
+	hivesim.MustRun(sim, suite)
+}
+
+var (
+	defaultTimeout = 10 * time.Minute
+	// number of blocks the fork chain is longer than the replaced chain
+	reorgDepth = uint64(3)
+)
+
+func reorgDropsProposals(t *hivesim.T, clients *taiko.ClientsByRole) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartSingleNodeNet()
+	defer env.StopSingleNodeNet()
+	reorg := env.NewL1Reorg()
+	defer reorg.Stop()
+
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	l1Cli, l2Cli := l1.MustEthClient(t), l2.MustEthClient(t)
+
+	// This block is proposed before the partition and survives the reorg.
+	env.GenSomeL2Blocks(t, 1)
+	kept := headHeader(t, ctx, l2Cli)
+
+	require.NoError(t, reorg.Partition(ctx))
+	env.GenSomeL2Blocks(t, 2)
+	dropped := headHeader(t, ctx, l2Cli)
+	origin, err := taiko.L1OriginByID(ctx, l2, dropped.Number)
+	require.NoError(t, err)
+	require.NoError(t, reorg.Reconnect(ctx, reorgDepth))
+
+	l1Header, err := l1Cli.HeaderByNumber(ctx, origin.L1BlockHeight.ToInt())
+	require.NoError(t, err)
+	require.NotEqual(t, origin.L1BlockHash, l1Header.Hash(), "L1 origin block of L2 block %d not reorged", dropped.Number)
+
+	// The proposals go back into the L1 transaction pool. Once they are included
+	// again, the driver derives different L2 blocks at the same heights.
+	for {
+		header, err := l2Cli.HeaderByNumber(ctx, dropped.Number)
+		if err == nil && header.Hash() != dropped.Hash() {
+			err := taiko.CheckL1Origins(ctx, l1, l2)
+			if err == nil {
+				break
+			}
+			t.Logf("L1 origins not consistent yet: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("L2 block %d (%v) was not replaced after the L1 reorg", dropped.Number, dropped.Hash())
+		default:
+		}
+		env.GenSomeL2Blocks(t, 1)
+	}
+
+	header, err := l2Cli.HeaderByNumber(ctx, kept.Number)
+	require.NoError(t, err)
+	require.Equal(t, kept.Hash(), header.Hash(), "L2 block %d proposed before the partition changed", kept.Number)
+}
+
+func reorgWithoutProposals(t *hivesim.T, clients *taiko.ClientsByRole) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	env := taiko.NewTestEnvWithClients(ctx, t, clients)
+	env.StartSingleNodeNet()
+	defer env.StopSingleNodeNet()
+	reorg := env.NewL1Reorg()
+	defer reorg.Stop()
+
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	l2Cli := l2.MustEthClient(t)
+
+	env.GenSomeL2Blocks(t, 1)
+	head := headHeader(t, ctx, l2Cli)
+
+	// Only L1 transactions are sent, so nothing is proposed while partitioned.
+	require.NoError(t, reorg.Partition(ctx))
+	env.GenSomeL1Blocks(t, 2)
+	require.NoError(t, reorg.Reconnect(ctx, reorgDepth))
+
+	require.NoError(t, taiko.CheckL1Origins(ctx, l1, l2))
+	after := headHeader(t, ctx, l2Cli)
+	require.Equal(t, head.Hash(), after.Hash(), "L2 head changed by L1 reorg")
+}
+
+func headHeader(t *hivesim.T, ctx context.Context, cli *ethclient.Client) *types.Header {
+	header, err := cli.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	require.NotZero(t, header.Number.Uint64(), "no blocks")
+	return header
+}
+
+func runAllTests(t *hivesim.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+	taiko.RunTests(t, ctx, &taiko.RunTestsParams{
+		MatrixTests: tests,
+		Concurrency: 2,
+	})
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/hive/simulators/ethereum/rpc/rpctest"
+	"github.com/ethereum/hive/taiko"
+)
+
+// l2TestSpec is a test which only runs against Taiko L2.
+type l2TestSpec struct {
+	Name  string
+	About string
+	Run   func(*l2TestEnv)
+}
+
+// l2TestEnv is the environment of an L2 test.
+type l2TestEnv struct {
+	*rpctest.TestEnv
+	Taiko *taiko.TestEnv
+	L2    *taiko.ELNode
+}
+
+var l2Tests = []l2TestSpec{
+	{
+		Name:  "http/AnchorTransaction",
+		About: "Every L2 block starts with an anchor transaction sent by the golden touch account, which refers to an L1 block.",
+		Run:   anchorTxTest,
+	},
+	{
+		Name:  "ws/AnchorTransaction",
+		About: "Every L2 block starts with an anchor transaction sent by the golden touch account, which refers to an L1 block.",
+		Run:   anchorTxTest,
+	},
+	{
+		Name:  "http/BaseFee",
+		About: "Every L2 block has a base fee, and transactions are included only with a fee cap covering it.",
+		Run:   baseFeeTest,
+	},
+	{
+		Name:  "http/DebugTrace",
+		About: "The debug namespace traces L2 transactions and blocks, including the anchor transaction.",
+		Run:   debugTraceTest,
+	},
+}
+
+// sendTransfer funds a new account and sends a transfer from it to a fresh address,
+// so that the test has an L2 block of its own.
+func sendTransfer(t *l2TestEnv, typ uint8) (*types.Transaction, *types.Receipt) {
+	from := t.Vault.CreateAccount(t.TestEnv, big.NewInt(params.Ether))
+	to := t.Taiko.L2Vault.GenerateKey()
+	tx, err := t.Taiko.L2Vault.SendTx(t.Ctx(), t.Eth, typ, from, &to, big.NewInt(1), nil)
+	if err != nil {
+		t.Fatalf("Unable to send transfer: %v", err)
+	}
+	receipt, err := t.WaitForTxConfirmations(tx.Hash(), 0)
+	if err != nil {
+		t.Fatalf("Unable to retrieve receipt of %v: %v", tx.Hash(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("Transfer %v failed", tx.Hash())
+	}
+	return tx, receipt
+}
+
+// anchorTxTest checks the anchor transaction of every L2 block.
+func anchorTxTest(t *l2TestEnv) {
+	_, receipt := sendTransfer(t, types.DynamicFeeTxType)
+
+	l1, err := t.Taiko.Net.GetL1ELNode(0).EthClient()
+	if err != nil {
+		t.Fatalf("Unable to connect to L1: %v", err)
+	}
+	defer l1.Close()
+
+	var (
+		signer      = types.LatestSignerForChainID(t.Taiko.Conf.L2.ChainID)
+		goldenTouch = t.Taiko.Conf.L2.Throwawayer.Address
+	)
+	for n := uint64(1); n <= receipt.BlockNumber.Uint64(); n++ {
+		block, err := t.Eth.BlockByNumber(t.Ctx(), new(big.Int).SetUint64(n))
+		if err != nil {
+			t.Fatalf("Unable to fetch block %d: %v", n, err)
+		}
+		l1Height, l1Hash, err := taiko.AnchorOf(t.L2, block)
+		if err != nil {
+			t.Fatalf("Invalid anchor: %v", err)
+		}
+		for i, tx := range block.Transactions() {
+			sender, err := types.Sender(signer, tx)
+			if err != nil {
+				t.Fatalf("Unable to recover sender of tx %v: %v", tx.Hash(), err)
+			}
+			if i == 0 && sender != goldenTouch {
+				t.Errorf("Anchor transaction of block %d sent by %v, want %v", n, sender, goldenTouch)
+			}
+			if i > 0 && sender == goldenTouch {
+				t.Errorf("Transaction %d of block %d sent by the golden touch account", i, n)
+			}
+		}
+		anchorReceipt, err := t.Eth.TransactionReceipt(t.Ctx(), block.Transactions()[0].Hash())
+		if err != nil {
+			t.Fatalf("Unable to fetch receipt of the anchor transaction of block %d: %v", n, err)
+		}
+		if anchorReceipt.Status != types.ReceiptStatusSuccessful {
+			t.Errorf("Anchor transaction of block %d failed", n)
+		}
+		l1Header, err := l1.HeaderByNumber(t.Ctx(), new(big.Int).SetUint64(l1Height))
+		if err != nil {
+			t.Fatalf("Unable to fetch L1 block %d: %v", l1Height, err)
+		}
+		if l1Header.Hash() != l1Hash {
+			t.Errorf("Anchor transaction of block %d refers to L1 block %d with hash %v, want %v", n, l1Height, l1Hash, l1Header.Hash())
+		}
+	}
+}
+
+// baseFeeTest checks that L2 blocks have a base fee, and that the transactions
+// included in them pay it.
+func baseFeeTest(t *l2TestEnv) {
+	tx, receipt := sendTransfer(t, types.DynamicFeeTxType)
+
+	head, err := t.Eth.HeaderByNumber(t.Ctx(), nil)
+	if err != nil {
+		t.Fatalf("Unable to fetch head: %v", err)
+	}
+	for n := uint64(0); n <= head.Number.Uint64(); n++ {
+		header, err := t.Eth.HeaderByNumber(t.Ctx(), new(big.Int).SetUint64(n))
+		if err != nil {
+			t.Fatalf("Unable to fetch block %d: %v", n, err)
+		}
+		if header.BaseFee == nil {
+			t.Errorf("Block %d has no base fee", n)
+		}
+	}
+
+	suggested, err := t.Eth.SuggestGasPrice(t.Ctx())
+	if err != nil {
+		t.Fatalf("Unable to fetch gas price: %v", err)
+	}
+	if head.BaseFee != nil && suggested.Cmp(head.BaseFee) < 0 {
+		t.Errorf("Suggested gas price %d is below the base fee %d of the head block", suggested, head.BaseFee)
+	}
+
+	block, err := t.Eth.BlockByHash(t.Ctx(), receipt.BlockHash)
+	if err != nil {
+		t.Fatalf("Unable to fetch block %v: %v", receipt.BlockHash, err)
+	}
+	if block.BaseFee() == nil {
+		t.Fatalf("Block %d has no base fee", block.Number())
+	}
+	if tx.GasFeeCap().Cmp(block.BaseFee()) < 0 {
+		t.Errorf("Transaction %v with fee cap %d included in block %d with base fee %d", tx.Hash(), tx.GasFeeCap(), block.Number(), block.BaseFee())
+	}
+	for i, tx := range block.Transactions() {
+		if i == 0 {
+			// The anchor transaction is checked by the protocol.
+			continue
+		}
+		if tx.GasFeeCap().Cmp(block.BaseFee()) < 0 {
+			t.Errorf("Transaction %v with fee cap %d included in block %d with base fee %d", tx.Hash(), tx.GasFeeCap(), block.Number(), block.BaseFee())
+		}
+	}
+}
+
+// traceResult is the result of the default struct logger of debug_trace* methods.
+type traceResult struct {
+	Gas         uint64        `json:"gas"`
+	Failed      bool          `json:"failed"`
+	ReturnValue string        `json:"returnValue"`
+	StructLogs  []interface{} `json:"structLogs"`
+}
+
+// blockTraceResult is an element of the result of debug_traceBlock* methods.
+type blockTraceResult struct {
+	Result *traceResult `json:"result"`
+	Error  string       `json:"error"`
+}
+
+// debugTraceTest traces an L2 transaction and the block containing it.
+func debugTraceTest(t *l2TestEnv) {
+	tx, receipt := sendTransfer(t, types.DynamicFeeTxType)
+
+	// Tracing can take longer than a plain RPC call.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var trace traceResult
+	if err := t.CallContext(ctx, &trace, "debug_traceTransaction", tx.Hash(), map[string]interface{}{}); err != nil {
+		t.Fatalf("Unable to trace transaction %v: %v", tx.Hash(), err)
+	}
+	if trace.Failed {
+		t.Errorf("Trace of transaction %v failed", tx.Hash())
+	}
+	if trace.Gas != receipt.GasUsed {
+		t.Errorf("Trace of transaction %v used %d gas, receipt %d", tx.Hash(), trace.Gas, receipt.GasUsed)
+	}
+
+	block, err := t.Eth.BlockByHash(t.Ctx(), receipt.BlockHash)
+	if err != nil {
+		t.Fatalf("Unable to fetch block %v: %v", receipt.BlockHash, err)
+	}
+	var traces []blockTraceResult
+	if err := t.CallContext(ctx, &traces, "debug_traceBlockByNumber", hexutil.EncodeBig(block.Number()), map[string]interface{}{}); err != nil {
+		t.Fatalf("Unable to trace block %d: %v", block.Number(), err)
+	}
+	if len(traces) != len(block.Transactions()) {
+		t.Fatalf("Trace of block %d has %d results, block has %d transactions", block.Number(), len(traces), len(block.Transactions()))
+	}
+	for i, r := range traces {
+		hash := block.Transactions()[i].Hash()
+		if r.Error != "" || r.Result == nil {
+			t.Errorf("Unable to trace transaction %d (%v) of block %d: %s", i, hash, block.Number(), r.Error)
+			continue
+		}
+		if r.Result.Failed {
+			t.Errorf("Trace of transaction %d (%v) of block %d failed", i, hash, block.Number())
+		}
+		if hash == tx.Hash() && r.Result.Gas != trace.Gas {
+			t.Errorf("Block trace of transaction %v used %d gas, transaction trace %d", hash, r.Result.Gas, trace.Gas)
+		}
+	}
+}
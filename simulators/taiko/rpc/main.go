@@ -2,68 +2,23 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"math/big"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/hive/hivesim"
+	"github.com/ethereum/hive/simulators/ethereum/rpc/rpctest"
 	"github.com/ethereum/hive/taiko"
 )
 
-type testSpec struct {
-	Name  string
-	About string
-	Run   func(*LegacyTestEnv)
-}
-
-var tests = []testSpec{
-	{Name: "http/BalanceAndNonceAt", Run: balanceAndNonceAtTest},
-	{Name: "http/CanonicalChain", Run: canonicalChainTest},
-	{Name: "http/CodeAt", Run: CodeAtTest},
-	{Name: "http/ContractDeployment", Run: deployContractTest},
-	{Name: "http/ContractDeploymentOutOfGas", Run: deployContractOutOfGasTest},
-	{Name: "http/EstimateGas", Run: estimateGasTest},
-	{Name: "http/GenesisBlockByHash", Run: genesisBlockByHashTest},
-	{Name: "http/GenesisBlockByNumber", Run: genesisBlockByNumberTest},
-	{Name: "http/GenesisHeaderByHash", Run: genesisHeaderByHashTest},
-	{Name: "http/GenesisHeaderByNumber", Run: genesisHeaderByNumberTest},
-	{Name: "http/Receipt", Run: receiptTest},
-	{Name: "http/SyncProgress", Run: syncProgressTest},
-	{Name: "http/TransactionCount", Run: transactionCountTest},
-	{Name: "http/TransactionInBlock", Run: transactionInBlockTest},
-	{Name: "http/TransactionReceipt", Run: TransactionReceiptTest},
-
-	// HTTP ABI tests.
-	{Name: "http/ABICall", Run: callContractTest},
-	{Name: "http/ABITransact", Run: transactContractTest},
-
-	// WebSocket ABI tests.
-	{Name: "ws/ABICall", Run: callContractTest},
-	{Name: "ws/ABITransact", Run: transactContractTest},
-
-	// WebSocket subscription tests.
-	{Name: "ws/NewHeadSubscription", Run: newHeadSubscriptionTest},
-	{Name: "ws/LogSubscription", Run: logSubscriptionTest},
-	{Name: "ws/TransactionInBlockSubscription", Run: transactionInBlockSubscriptionTest},
-
-	// WebSocket RPC tests.
-	{Name: "ws/BalanceAndNonceAt", Run: balanceAndNonceAtTest},
-	{Name: "ws/CanonicalChain", Run: canonicalChainTest},
-	{Name: "ws/CodeAt", Run: CodeAtTest},
-	{Name: "ws/ContractDeployment", Run: deployContractTest},
-	{Name: "ws/ContractDeploymentOutOfGas", Run: deployContractOutOfGasTest},
-	{Name: "ws/EstimateGas", Run: estimateGasTest},
-	{Name: "ws/GenesisBlockByHash", Run: genesisBlockByHashTest},
-	{Name: "ws/GenesisBlockByNumber", Run: genesisBlockByNumberTest},
-	{Name: "ws/GenesisHeaderByHash", Run: genesisHeaderByHashTest},
-	{Name: "ws/GenesisHeaderByNumber", Run: genesisHeaderByNumberTest},
-	{Name: "ws/Receipt", Run: receiptTest},
-	{Name: "ws/SyncProgress", Run: syncProgressTest},
-	{Name: "ws/TransactionCount", Run: transactionCountTest},
-	{Name: "ws/TransactionInBlock", Run: transactionInBlockTest},
-	{Name: "ws/TransactionReceipt", Run: TransactionReceiptTest},
-}
+// gasPrice is the gas price of the transactions sent by the tests.
+var gasPrice = big.NewInt(30 * params.GWei)
 
 func main() {
 	suite := hivesim.Suite{
@@ -85,38 +40,91 @@ interacting with one.`[1:],
 	hivesim.MustRun(sim, suite)
 }
 
-// runAllTests runs the tests against a client instance.
-// Most tests simply wait for tx inclusion in a block so we can run many tests concurrently.
+// runAllTests runs the tests of the rpc simulator and the L2 tests, each against
+// its own single node network, for every combination of clients.
 func runAllTests(t *hivesim.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
 	defer cancel()
 
-	// Need to adapt the tests a bit to work with the common
-	// libraries in the taiko package.
-	adaptedTests := make([]*hivesim.TestSpec, len(tests))
-	for i, test := range tests {
-		adaptedTests[i] = &hivesim.TestSpec{
-			Name:        fmt.Sprintf("%s (%s)", test.Name, "taiko-l2"),
-			Description: test.About,
-			Run: func(t *hivesim.T) {
-				env := taiko.NewTestEnv(ctx, t)
-				env.StartSingleNodeNet()
-				defer env.StopSingleNodeNet()
-				l2 := env.Net.GetL2ELNode(0)
-				l2Genesis := core.TaikoGenesisBlock(env.Conf.L2.NetworkID)
-				switch test.Name[:strings.IndexByte(test.Name, '/')] {
-				case "http":
-					RunHTTP(t, l2.Client, env.L2Vault, l2Genesis, test.Run)
-				case "ws":
-					RunWS(t, l2.Client, env.L2Vault, l2Genesis, test.Run)
-				default:
-					panic("bad test prefix in name " + test.Name)
-				}
-			},
+	var specs []*hivesim.TestSpec
+	for _, clients := range taiko.ClientCombinations(t) {
+		clients := clients
+		for _, test := range rpctest.Tests {
+			test := test
+			specs = append(specs, &hivesim.TestSpec{
+				Name:        fmt.Sprintf("%s (%s)", test.Name, clients),
+				Description: test.About,
+				Run: func(t *hivesim.T) {
+					env := taiko.NewTestEnvWithClients(ctx, t, clients)
+					env.StartSingleNodeNet()
+					defer env.StopSingleNodeNet()
+					l2 := env.Net.GetL2ELNode(0)
+					rpctest.Run(t, l2.Client, l2Chain(env), test)
+				},
+			})
+		}
+		for _, test := range l2Tests {
+			test := test
+			specs = append(specs, &hivesim.TestSpec{
+				Name:        fmt.Sprintf("%s (%s)", test.Name, clients),
+				Description: test.About,
+				Run: func(t *hivesim.T) {
+					env := taiko.NewTestEnvWithClients(ctx, t, clients)
+					env.StartSingleNodeNet()
+					defer env.StopSingleNodeNet()
+					l2 := env.Net.GetL2ELNode(0)
+					rpctest.Run(t, l2.Client, l2Chain(env), rpctest.TestSpec{
+						Name: test.Name,
+						Run: func(t *rpctest.TestEnv) {
+							test.Run(&l2TestEnv{TestEnv: t, Taiko: env, L2: l2})
+						},
+					})
+				},
+			})
 		}
 	}
 	taiko.RunTests(t, ctx, &taiko.RunTestsParams{
-		Tests:       adaptedTests,
+		Tests:       specs,
 		Concurrency: 10,
 	})
 }
+
+// l2Chain describes the L2 of the test network to the rpc tests.
+func l2Chain(env *taiko.TestEnv) *rpctest.Chain {
+	return &rpctest.Chain{
+		Genesis:     core.TaikoGenesisBlock(env.Conf.L2.NetworkID),
+		Vault:       &l2Vault{env.L2Vault},
+		VaultAddr:   taiko.VaultAddr,
+		GasPrice:    gasPrice,
+		WaitReceipt: waitReceipt,
+	}
+}
+
+// l2Vault funds the accounts of the rpc tests from the L2 vault.
+type l2Vault struct {
+	*taiko.Vault
+}
+
+func (v *l2Vault) CreateAccount(t *rpctest.TestEnv, amount *big.Int) common.Address {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	return v.Vault.CreateAccount(ctx, t.Eth, amount)
+}
+
+// waitReceipt waits for the receipt of a transaction. It does not wait for
+// confirmations, L2 blocks are only built when there are transactions to propose.
+func waitReceipt(t *rpctest.TestEnv, txHash common.Hash, _ uint64) (*types.Receipt, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	for {
+		receipt, err := t.Eth.TransactionReceipt(ctx, txHash)
+		if !errors.Is(err, ethereum.NotFound) {
+			return receipt, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
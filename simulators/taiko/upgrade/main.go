@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/hive/hivesim"
+	"github.com/ethereum/hive/taiko"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	envUpgradeFrom = "HIVE_TAIKO_UPGRADE_FROM"
+	envUpgradeTo   = "HIVE_TAIKO_UPGRADE_TO"
+)
+
+var tests = []*hivesim.TestSpec{
+	{
+		Name:        "Upgrade taiko-geth",
+		Description: "An L2 chain built on taiko-geth version A is restarted from the data directory on version B, with the driver on the matching version. The RPC state of the chain is unchanged, and the chain continues.",
+		Run:         upgradeTest,
+	},
+}
+
+func main() {
+	suite := hivesim.Suite{
+		Name:        "taiko-upgrade",
+		Description: "Restarts taiko-geth and the driver on another version from the data directory of the old version, to catch database migration breakage.",
+	}
+	suite.Add(&hivesim.TestSpec{
+		Name: "Client launch",
+		Description: `This test launches the client and runs the test tool.
+		Results from the test tool are reported as individual sub-tests.`,
+		Run: func(t *hivesim.T) { runAllTests(t) },
+	})
+	sim := hivesim.New()
+	hivesim.MustRun(sim, suite)
+}
+
+func runAllTests(t *hivesim.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+	taiko.RunTests(t, ctx, &taiko.RunTestsParams{
+		Tests:       tests,
+		Concurrency: 1,
+	})
+}
+
+// upgradeTest builds some L2 blocks on the clients of HIVE_TAIKO_UPGRADE_FROM, and
+// restarts taiko-geth from its data directory on the clients of HIVE_TAIKO_UPGRADE_TO.
+func upgradeTest(t *hivesim.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Minute)
+	defer cancel()
+	env := taiko.NewTestEnv(ctx, t)
+	from := clientsFromEnv(t, env.Clients, envUpgradeFrom)
+	to := clientsFromEnv(t, env.Clients, envUpgradeTo)
+	env.Clients = from
+	t.Logf("starting L2 on %s and %s", env.Clients.L2.Name, env.Clients.Driver.Name)
+	env.StartSingleNodeNet()
+	defer env.StopDevnet()
+
+	env.GenSomeL2Blocks(t, 5)
+
+	// Stop the taiko clients first, so that the chain does not change while the data
+	// directory is copied.
+	l1, l2 := env.Net.GetL1ELNode(0), env.Net.GetL2ELNode(0)
+	env.StopNode(env.Net.GetProposerNode(0))
+	env.StopNode(env.Net.GetProverNode(0))
+	env.StopNode(env.Net.GetDriverNode(0))
+	before, height := readState(env, l2)
+	t.Logf("L2 height before upgrade: %d", height)
+
+	datadir := filepath.Join(os.TempDir(), fmt.Sprintf("taiko-upgrade-%d.tar.gz", time.Now().UnixNano()))
+	defer os.Remove(datadir)
+	require.NoError(t, l2.SnapshotDatadir(datadir))
+	env.StopNode(l2.Node)
+
+	env.Clients = to
+	t.Logf("restarting L2 on %s and %s", env.Clients.L2.Name, env.Clients.Driver.Name)
+	l2 = env.NewL2ELNode(taiko.WithELNodeType("full"), taiko.WithDatadir(datadir))
+	env.Net.Apply(taiko.WithL2Node(l2))
+	after := readStateAt(env, l2, height)
+	require.NoError(t, compareState(before, after))
+
+	env.Net.Apply(
+		taiko.WithDriverNode(env.NewDriverNode(l1, l2)),
+		taiko.WithProposerNode(env.NewProposerNode(l1, l2)),
+		taiko.WithProverNode(env.NewProverNode(l1, l2)),
+	)
+	env.GenSomeL2Blocks(t, 5)
+
+	cli, err := l2.EthClient()
+	require.NoError(t, err)
+	defer cli.Close()
+	head, err := cli.BlockNumber(ctx)
+	require.NoError(t, err)
+	require.Greater(t, head, height, "L2 chain did not continue after the upgrade")
+	require.NoError(t, taiko.CheckL2Consistency(ctx, l1, []*taiko.ELNode{l2}, head))
+}
+
+// clientsFromEnv returns the default clients, with the clients named in the
+// comma-separated environment variable key replacing the clients of their roles.
+// Clients of other versions are selected with hive's client_branch syntax, e.g.
+// taiko-geth_v0.2.0.
+func clientsFromEnv(t *hivesim.T, defaults *taiko.ClientsByRole, key string) *taiko.ClientsByRole {
+	clients := *defaults
+	v := os.Getenv(key)
+	if v == "" {
+		t.Fatalf("%s is not set: the upgrade test needs the clients of both versions, e.g. --sim.env %s=taiko-geth_v0.1.0,taiko-client_v0.1.0", key, key)
+	}
+	defs, err := t.Sim.ClientTypes()
+	require.NoError(t, err)
+	byName := make(map[string]*hivesim.ClientDefinition, len(defs))
+	for _, def := range defs {
+		byName[def.Name] = def
+	}
+	var selected []*hivesim.ClientDefinition
+	for _, name := range strings.Split(v, ",") {
+		def, ok := byName[strings.TrimSpace(name)]
+		require.True(t, ok, "%s: unknown client %q", key, name)
+		selected = append(selected, def)
+	}
+	roles := taiko.Roles(t, selected).Combinations()[0]
+	for _, r := range []struct{ dst, src **hivesim.ClientDefinition }{
+		{&clients.L1, &roles.L1},
+		{&clients.L2, &roles.L2},
+		{&clients.Driver, &roles.Driver},
+		{&clients.Proposer, &roles.Proposer},
+		{&clients.Prover, &roles.Prover},
+		{&clients.Contract, &roles.Contract},
+		{&clients.Relayer, &roles.Relayer},
+	} {
+		if *r.src != nil {
+			*r.dst = *r.src
+		}
+	}
+	return &clients
+}
+
+// readState reads the RPC state of the L2 chain up to its head, and returns it
+// together with the head.
+func readState(env *taiko.TestEnv, l2 *taiko.ELNode) (map[string]string, uint64) {
+	t, ctx := env.T, env.Context
+	cli, err := l2.EthClient()
+	require.NoError(t, err)
+	defer cli.Close()
+	head, err := cli.BlockNumber(ctx)
+	require.NoError(t, err)
+	return readStateAt(env, l2, head), head
+}
+
+// readStateAt reads the RPC state of the L2 chain up to the given height: headers,
+// receipts and L1 origins of all blocks, and the accounts touched by them at the
+// given height. The values are JSON encoded, keyed by what they describe.
+func readStateAt(env *taiko.TestEnv, l2 *taiko.ELNode, height uint64) map[string]string {
+	t, ctx := env.T, env.Context
+	cli, err := l2.EthClient()
+	require.NoError(t, err)
+	defer cli.Close()
+
+	state := make(map[string]string)
+	put := func(key string, v interface{}) {
+		enc, err := json.Marshal(v)
+		require.NoError(t, err)
+		state[key] = string(enc)
+	}
+	signer := types.LatestSignerForChainID(env.Conf.L2.ChainID)
+	accounts := make(map[common.Address]struct{})
+	for n := uint64(0); n <= height; n++ {
+		num := new(big.Int).SetUint64(n)
+		block, err := cli.BlockByNumber(ctx, num)
+		require.NoError(t, err, "block %d", n)
+		put(fmt.Sprintf("block %d", n), block.Header())
+		if n > 0 {
+			origin, err := taiko.L1OriginByID(ctx, l2, num)
+			if err != nil && err.Error() != ethereum.NotFound.Error() {
+				require.NoError(t, err, "L1 origin of block %d", n)
+			}
+			put(fmt.Sprintf("L1 origin %d", n), origin)
+		}
+		for _, tx := range block.Transactions() {
+			receipt, err := cli.TransactionReceipt(ctx, tx.Hash())
+			require.NoError(t, err, "receipt of %v", tx.Hash())
+			put(fmt.Sprintf("receipt %v", tx.Hash()), receipt)
+			from, err := types.Sender(signer, tx)
+			require.NoError(t, err)
+			accounts[from] = struct{}{}
+			if tx.To() != nil {
+				accounts[*tx.To()] = struct{}{}
+			}
+			if receipt.ContractAddress != (common.Address{}) {
+				accounts[receipt.ContractAddress] = struct{}{}
+			}
+		}
+	}
+	num := new(big.Int).SetUint64(height)
+	for addr := range accounts {
+		balance, err := cli.BalanceAt(ctx, addr, num)
+		require.NoError(t, err)
+		nonce, err := cli.NonceAt(ctx, addr, num)
+		require.NoError(t, err)
+		code, err := cli.CodeAt(ctx, addr, num)
+		require.NoError(t, err)
+		put(fmt.Sprintf("account %v", addr), map[string]interface{}{
+			"balance":  balance.String(),
+			"nonce":    nonce,
+			"codeHash": crypto.Keccak256Hash(code),
+		})
+	}
+	return state
+}
+
+// compareState returns the differences between two states read by readStateAt.
+func compareState(before, after map[string]string) error {
+	var diffs []string
+	for key, a := range before {
+		if b, ok := after[key]; !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: missing after upgrade", key))
+		} else if a != b {
+			diffs = append(diffs, fmt.Sprintf("%s: %s before upgrade, %s after", key, a, b))
+		}
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: missing before upgrade", key))
+		}
+	}
+	if len(diffs) == 0 {
+		return nil
+	}
+	sort.Strings(diffs)
+	return fmt.Errorf("RPC state changed by the upgrade:\n%s", strings.Join(diffs, "\n"))
+}
@@ -3,6 +3,12 @@
 set -e
 
 debug=false
+# With the argument "dev", the taiko-l1-dev image is built: an anvil node with the
+# evm_ RPC methods instead of geth with clique.
+dev=false
+if [[ "$1" == "dev" ]]; then
+    dev=true
+fi
 project_dir=$(realpath "$(dirname "$0")/..")
 tmp_dir=${project_dir}/tmp
 work_dir=${project_dir}/taiko-image
@@ -20,6 +26,13 @@ function print() {
 }
 
 l1_container_name=taiko-l1
+l1_image=taiko-l1
+l1_dockerfile=Dockerfile
+if [[ "${dev}" == "true" ]]; then
+    l1_container_name=taiko-l1-dev
+    l1_image=taiko-l1-dev
+    l1_dockerfile=Dockerfile.dev
+fi
 l2_container_name=taiko-l2
 
 function get_hive_config() {
@@ -88,9 +101,9 @@ function get_l2_taiko_Addr() {
 
 function start_l1_container() {
     print "Start container to build l1 image ..."
-    local image_name="taiko-l1:tmp"
+    local image_name="${l1_image}:tmp"
     delete_image ${image_name}
-    docker build -t ${image_name} "${work_dir}/l1" >/dev/null
+    docker build -t ${image_name} -f "${work_dir}/l1/${l1_dockerfile}" "${work_dir}/l1" >/dev/null
     delete_container ${l1_container_name}
     build_container=$(docker run -d \
         --name ${l1_container_name} \
@@ -100,6 +113,11 @@ function start_l1_container() {
     print "\tContainer ID:" "${build_container}"
 }
 
+# taiko-mono is pinned to the last commit of main before the taiko-client version of
+# taiko/go.mod (v0.4.1-0.20230305144634-8c772f4e1085), like in build-protocol-image.sh
+# and build-relayer-image.sh, so the L1 contracts match the relayer and the bindings.
+mono_branch="main"
+mono_date="2023-03-05T14:46:34Z"
 mono_dir="${tmp_dir}/taiko-mono"
 protocol_dir="${mono_dir}/packages/protocol"
 
@@ -125,7 +143,13 @@ download_protocol_repo() {
     fi
 
     rm -fr "${mono_dir}"
-    git clone --depth=1 https://github.com/taikoxyz/taiko-mono.git ${mono_dir}
+    git clone https://github.com/taikoxyz/taiko-mono.git "${mono_dir}"
+    cd "${mono_dir}"
+    local commit
+    commit=$(git rev-list -1 --before="${mono_date}" "origin/${mono_branch}")
+    print "Using taiko-mono commit ${commit}"
+    git checkout "${commit}"
+    cd -
 
     change_protocol
 
@@ -161,14 +185,33 @@ deploy_protocol() {
     print "Success to deploy contact on ${build_container}"
 }
 
+# dump_l1_state stores the state of the anvil node in the container, anvil doesn't
+# persist it by itself. It is loaded again when the image starts.
+dump_l1_state() {
+    if [[ "${dev}" != "true" ]]; then
+        return
+    fi
+    print "Dump state of ${build_container}"
+    curl \
+        --fail \
+        --silent \
+        -X POST \
+        -H "Content-Type: application/json" \
+        -d '{"jsonrpc":"2.0","id":0,"method":"anvil_dumpState","params":[]}' \
+        localhost:18545 | jq -re .result >state.hex
+    docker cp state.hex "${l1_container_name}:/state.hex"
+    rm state.hex
+}
+
 build_l1_image() {
-    docker commit -m "$(whoami)" -m "taiko-l1-image" "${build_container}" taiko-l1:local >/dev/null
+    docker commit -m "$(whoami)" -m "${l1_image}-image" "${build_container}" ${l1_image}:local >/dev/null
     delete_container ${l1_container_name}
-    print "Success to build taiko-l1 image"
+    print "Success to build ${l1_image} image"
 }
 
 get_hive_config
 download_protocol_repo
 start_l1_container
 deploy_protocol
+dump_l1_state
 build_l1_image
@@ -0,0 +1,49 @@
+#!/usr/bin/env bash
+
+set -e
+
+debug=false
+project_dir=$(realpath "$(dirname "$0")/..")
+tmp_dir=${project_dir}/tmp
+work_dir=${project_dir}/taiko-image
+
+# taiko-mono is pinned to the last commit of main before the taiko-client version of
+# taiko/go.mod (v0.4.1-0.20230305144634-8c772f4e1085), so the deployed contracts match
+# the bindings used by the tests.
+mono_branch="main"
+mono_date="2023-03-05T14:46:34Z"
+mono_dir="${tmp_dir}/taiko-mono"
+protocol_dir="${mono_dir}/packages/protocol"
+
+function download_mono_repo() {
+    if [[ "${debug}" == "true" ]]; then
+        echo "In debug mode, do not download taiko-mono repo"
+        return
+    fi
+    rm -fr "${mono_dir}"
+    git clone https://github.com/taikoxyz/taiko-mono.git "${mono_dir}"
+    cd "${mono_dir}"
+    local commit
+    commit=$(git rev-list -1 --before="${mono_date}" "origin/${mono_branch}")
+    echo "Using taiko-mono commit ${commit}"
+    git checkout "${commit}"
+    cd -
+}
+
+# The protocol is changed the same way as for the taiko-l1 image. Protocol parameters
+# given by the tests are applied on top of this when the contracts are deployed.
+function change_protocol() {
+    echo "Change some protocol config for test"
+    local config="${protocol_dir}/contracts/libs/LibSharedConfig.sol"
+    sed -i -f "${work_dir}/LibSharedConfig.sed" "${config}"
+    cp "${work_dir}/LibZKP.sol" "${protocol_dir}/contracts/libs/LibZKP.sol"
+}
+
+function build_protocol_image() {
+    docker build -t taiko-protocol:local -f "${work_dir}/protocol/Dockerfile" "${protocol_dir}"
+    echo "Success to build taiko-protocol image"
+}
+
+download_mono_repo
+change_protocol
+build_protocol_image
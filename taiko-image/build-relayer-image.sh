@@ -0,0 +1,38 @@
+#!/usr/bin/env bash
+
+set -e
+
+debug=false
+project_dir=$(realpath "$(dirname "$0")/..")
+tmp_dir=${project_dir}/tmp
+work_dir=${project_dir}/taiko-image
+
+# taiko-mono is pinned to the last commit of main before the taiko-client version of
+# taiko/go.mod (v0.4.1-0.20230305144634-8c772f4e1085), like in build-protocol-image.sh,
+# so the relayer matches the contracts of the L1 and L2 images.
+mono_branch="main"
+mono_date="2023-03-05T14:46:34Z"
+mono_dir="${tmp_dir}/taiko-mono"
+
+function download_mono_repo() {
+    if [[ "${debug}" == "true" ]]; then
+        echo "In debug mode, do not download taiko-mono repo"
+        return
+    fi
+    rm -fr "${mono_dir}"
+    git clone https://github.com/taikoxyz/taiko-mono.git "${mono_dir}"
+}
+
+function build_relayer_image() {
+    cd "${mono_dir}"
+    local commit
+    commit=$(git rev-list -1 --before="${mono_date}" "origin/${mono_branch}")
+    echo "Using taiko-mono commit ${commit}"
+    git checkout "${commit}"
+    docker build -t taiko-relayer:local -f packages/relayer/Dockerfile .
+    cd "${work_dir}"
+    echo "Success to build taiko-relayer image"
+}
+
+download_mono_repo
+build_relayer_image
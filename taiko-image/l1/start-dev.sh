@@ -0,0 +1,44 @@
+#!/bin/sh
+
+# Startup script of the dev-mode L1 node. It runs anvil, which seals a block for every
+# transaction like clique with period 0, and has the evm_ RPC methods to mine empty
+# blocks and to set block timestamps.
+#
+# This script assumes the following files:
+#  - `genesis.json` file is located in /host (mandatory)
+#  - `state.hex` file is located in the filesystem root (optional), the state with the
+#    deployed L1 contracts as returned by anvil_dumpState
+#
+# Taiko environment variables
+#
+#  - HIVE_TAIKO_L1_CHAIN_ID                          chain id of the l1 node
+
+set -e
+
+rpc() {
+  curl --fail --silent -X POST -H "Content-Type: application/json" --data "$1" localhost:8547
+}
+
+anvil \
+  --host 127.0.0.1 \
+  --port 8547 \
+  --chain-id "$HIVE_TAIKO_L1_CHAIN_ID" \
+  --init /host/genesis.json &
+anvil_pid=$!
+
+until rpc '{"jsonrpc":"2.0","id":0,"method":"eth_chainId","params":[]}' >/dev/null; do
+  sleep 0.1
+done
+
+if [ -f /state.hex ]; then
+  echo "Loading L1 state..."
+  printf '{"jsonrpc":"2.0","id":0,"method":"anvil_loadState","params":["%s"]}' "$(cat /state.hex)" >/tmp/load_state.json
+  rpc @/tmp/load_state.json | jq -e '.result == true' >/dev/null
+fi
+
+# The ports are only opened once the state is loaded, hive waits for them. anvil serves
+# websocket connections on its HTTP port.
+socat TCP-LISTEN:8545,fork,reuseaddr TCP:127.0.0.1:8547 &
+socat TCP-LISTEN:8546,fork,reuseaddr TCP:127.0.0.1:8547 &
+
+wait $anvil_pid
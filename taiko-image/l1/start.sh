@@ -60,6 +60,15 @@
 
 set -e
 
+# Load the test chain if present, e.g. one created by hivechain generate-taiko.
+if [ -f /chain.rlp ]; then
+  echo "Loading initial blockchain..."
+  if ! geth --datadir /data/l1-node --gcmode archive import /chain.rlp; then
+    echo "Failed to import /chain.rlp"
+    exit 1
+  fi
+fi
+
 geth \
   --datadir /data/l1-node \
   --nodiscover \
@@ -0,0 +1,474 @@
+package taiko
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// The bridge contracts are not part of the taiko-client bindings, so only the
+// methods and events used by tests are declared here.
+
+const messageTupleABI = `{"name":"message","type":"tuple","components":[
+	{"name":"id","type":"uint256"},{"name":"sender","type":"address"},
+	{"name":"srcChainId","type":"uint256"},{"name":"destChainId","type":"uint256"},
+	{"name":"owner","type":"address"},{"name":"to","type":"address"},
+	{"name":"refundAddress","type":"address"},{"name":"depositValue","type":"uint256"},
+	{"name":"callValue","type":"uint256"},{"name":"processingFee","type":"uint256"},
+	{"name":"gasLimit","type":"uint256"},{"name":"data","type":"bytes"},
+	{"name":"memo","type":"string"}]}`
+
+const bridgeABIJSON = `[
+	{"type":"function","name":"sendMessage","stateMutability":"payable","inputs":[` + messageTupleABI + `],"outputs":[{"name":"msgHash","type":"bytes32"}]},
+	{"type":"function","name":"processMessage","stateMutability":"nonpayable","inputs":[` + messageTupleABI + `,{"name":"proof","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"retryMessage","stateMutability":"nonpayable","inputs":[` + messageTupleABI + `,{"name":"isLastAttempt","type":"bool"}],"outputs":[]},
+	{"type":"function","name":"releaseEther","stateMutability":"nonpayable","inputs":[` + messageTupleABI + `,{"name":"proof","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"getMessageStatus","stateMutability":"view","inputs":[{"name":"msgHash","type":"bytes32"}],"outputs":[{"name":"","type":"uint8"}]},
+	{"type":"function","name":"isMessageSent","stateMutability":"view","inputs":[{"name":"msgHash","type":"bytes32"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"hashMessage","stateMutability":"pure","inputs":[` + messageTupleABI + `],"outputs":[{"name":"","type":"bytes32"}]},
+	{"type":"event","name":"MessageSent","anonymous":false,"inputs":[{"indexed":true,"name":"msgHash","type":"bytes32"},` + messageTupleABI + `]},
+	{"type":"event","name":"MessageStatusChanged","anonymous":false,"inputs":[{"indexed":true,"name":"msgHash","type":"bytes32"},{"indexed":false,"name":"status","type":"uint8"}]}
+]`
+
+const tokenVaultABIJSON = `[
+	{"type":"function","name":"sendEther","stateMutability":"payable","inputs":[{"name":"destChainId","type":"uint256"},{"name":"to","type":"address"},{"name":"gasLimit","type":"uint256"},{"name":"processingFee","type":"uint256"},{"name":"refundAddress","type":"address"},{"name":"memo","type":"string"}],"outputs":[]},
+	{"type":"function","name":"sendERC20","stateMutability":"payable","inputs":[{"name":"destChainId","type":"uint256"},{"name":"to","type":"address"},{"name":"token","type":"address"},{"name":"amount","type":"uint256"},{"name":"gasLimit","type":"uint256"},{"name":"processingFee","type":"uint256"},{"name":"refundAddress","type":"address"},{"name":"memo","type":"string"}],"outputs":[]},
+	{"type":"function","name":"releaseERC20","stateMutability":"nonpayable","inputs":[` + messageTupleABI + `,{"name":"proof","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"canonicalToBridged","stateMutability":"view","inputs":[{"name":"chainId","type":"uint256"},{"name":"canonicalAddress","type":"address"}],"outputs":[{"name":"","type":"address"}]}
+]`
+
+const erc20ABIJSON = `[
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"approve","stateMutability":"nonpayable","inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}
+]`
+
+// HeaderSynced is emitted by TaikoL1 and TaikoL2 when a header of the other chain is synced.
+const headerSyncedABIJSON = `[
+	{"type":"event","name":"HeaderSynced","anonymous":false,"inputs":[{"indexed":true,"name":"height","type":"uint256"},{"indexed":true,"name":"srcHeight","type":"uint256"},{"indexed":false,"name":"srcHash","type":"bytes32"}]}
+]`
+
+var (
+	bridgeABI       = mustParseABI(bridgeABIJSON)
+	tokenVaultABI   = mustParseABI(tokenVaultABIJSON)
+	erc20ABI        = mustParseABI(erc20ABIJSON)
+	headerSyncedABI = mustParseABI(headerSyncedABIJSON)
+)
+
+func mustParseABI(def string) abi.ABI {
+	a, err := abi.JSON(strings.NewReader(def))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// BridgeMessage mirrors IBridge.Message.
+type BridgeMessage struct {
+	Id            *big.Int
+	Sender        common.Address
+	SrcChainId    *big.Int
+	DestChainId   *big.Int
+	Owner         common.Address
+	To            common.Address
+	RefundAddress common.Address
+	DepositValue  *big.Int
+	CallValue     *big.Int
+	ProcessingFee *big.Int
+	GasLimit      *big.Int
+	Data          []byte
+	Memo          string
+}
+
+// MessageStatus is the processing status of a message on the destination chain.
+type MessageStatus uint8
+
+const (
+	MessageStatusNew MessageStatus = iota
+	MessageStatusRetriable
+	MessageStatusDone
+	MessageStatusFailed
+)
+
+func (s MessageStatus) String() string {
+	switch s {
+	case MessageStatusNew:
+		return "NEW"
+	case MessageStatusRetriable:
+		return "RETRIABLE"
+	case MessageStatusDone:
+		return "DONE"
+	case MessageStatusFailed:
+		return "FAILED"
+	default:
+		return fmt.Sprintf("MessageStatus(%d)", uint8(s))
+	}
+}
+
+// Bridge is a binding of the Bridge contract on a node.
+type Bridge struct {
+	Address  common.Address
+	contract *bind.BoundContract
+}
+
+// TokenVault is a binding of the TokenVault contract on a node.
+type TokenVault struct {
+	Address  common.Address
+	contract *bind.BoundContract
+}
+
+// ERC20 is a binding of an ERC20 token contract.
+type ERC20 struct {
+	Address  common.Address
+	contract *bind.BoundContract
+}
+
+func (e *ELNode) BridgeClient() (*Bridge, error) {
+	c, err := e.EthClient()
+	if err != nil {
+		return nil, err
+	}
+	addr := e.deploy.bridgeAddress
+	if addr == (common.Address{}) {
+		return nil, fmt.Errorf("no bridge deployed on %s", e.Container)
+	}
+	return &Bridge{addr, bind.NewBoundContract(addr, bridgeABI, c, c, c)}, nil
+}
+
+func (e *ELNode) TokenVaultClient() (*TokenVault, error) {
+	c, err := e.EthClient()
+	if err != nil {
+		return nil, err
+	}
+	addr := e.deploy.vaultAddress
+	if addr == (common.Address{}) {
+		return nil, fmt.Errorf("no token vault deployed on %s", e.Container)
+	}
+	return &TokenVault{addr, bind.NewBoundContract(addr, tokenVaultABI, c, c, c)}, nil
+}
+
+func (e *ELNode) ERC20Client(token common.Address) (*ERC20, error) {
+	c, err := e.EthClient()
+	if err != nil {
+		return nil, err
+	}
+	return &ERC20{token, bind.NewBoundContract(token, erc20ABI, c, c, c)}, nil
+}
+
+// TestERC20Address returns the address of the test token deployed in the L2 genesis.
+func (e *ELNode) TestERC20Address() common.Address {
+	return e.deploy.testERC20Address
+}
+
+func (b *Bridge) SendMessage(opts *bind.TransactOpts, msg BridgeMessage) (*types.Transaction, error) {
+	return b.contract.Transact(opts, "sendMessage", msg)
+}
+
+func (b *Bridge) ProcessMessage(opts *bind.TransactOpts, msg BridgeMessage, proof []byte) (*types.Transaction, error) {
+	return b.contract.Transact(opts, "processMessage", msg, proof)
+}
+
+func (b *Bridge) RetryMessage(opts *bind.TransactOpts, msg BridgeMessage, isLastAttempt bool) (*types.Transaction, error) {
+	return b.contract.Transact(opts, "retryMessage", msg, isLastAttempt)
+}
+
+func (b *Bridge) ReleaseEther(opts *bind.TransactOpts, msg BridgeMessage, proof []byte) (*types.Transaction, error) {
+	return b.contract.Transact(opts, "releaseEther", msg, proof)
+}
+
+func (b *Bridge) MessageStatus(ctx context.Context, msgHash common.Hash) (MessageStatus, error) {
+	var out []interface{}
+	if err := b.contract.Call(&bind.CallOpts{Context: ctx}, &out, "getMessageStatus", msgHash); err != nil {
+		return 0, err
+	}
+	return MessageStatus(*abi.ConvertType(out[0], new(uint8)).(*uint8)), nil
+}
+
+func (b *Bridge) IsMessageSent(ctx context.Context, msgHash common.Hash) (bool, error) {
+	var out []interface{}
+	if err := b.contract.Call(&bind.CallOpts{Context: ctx}, &out, "isMessageSent", msgHash); err != nil {
+		return false, err
+	}
+	return *abi.ConvertType(out[0], new(bool)).(*bool), nil
+}
+
+// SentMessage returns the message emitted by the bridge in a transaction receipt.
+func (b *Bridge) SentMessage(receipt *types.Receipt) (*BridgeMessage, common.Hash, error) {
+	event := bridgeABI.Events["MessageSent"]
+	for _, log := range receipt.Logs {
+		if log.Address != b.Address || len(log.Topics) < 2 || log.Topics[0] != event.ID {
+			continue
+		}
+		values, err := bridgeABI.Unpack("MessageSent", log.Data)
+		if err != nil {
+			return nil, common.Hash{}, err
+		}
+		msg := abi.ConvertType(values[0], new(BridgeMessage)).(*BridgeMessage)
+		return msg, log.Topics[1], nil
+	}
+	return nil, common.Hash{}, fmt.Errorf("no MessageSent event in transaction %v", receipt.TxHash)
+}
+
+func (v *TokenVault) SendEther(opts *bind.TransactOpts, destChainID *big.Int, to common.Address, gasLimit, processingFee *big.Int, refund common.Address, memo string) (*types.Transaction, error) {
+	return v.contract.Transact(opts, "sendEther", destChainID, to, gasLimit, processingFee, refund, memo)
+}
+
+func (v *TokenVault) SendERC20(opts *bind.TransactOpts, destChainID *big.Int, to, token common.Address, amount, gasLimit, processingFee *big.Int, refund common.Address, memo string) (*types.Transaction, error) {
+	return v.contract.Transact(opts, "sendERC20", destChainID, to, token, amount, gasLimit, processingFee, refund, memo)
+}
+
+func (v *TokenVault) ReleaseERC20(opts *bind.TransactOpts, msg BridgeMessage, proof []byte) (*types.Transaction, error) {
+	return v.contract.Transact(opts, "releaseERC20", msg, proof)
+}
+
+// CanonicalToBridged returns the address of the bridged token on this chain.
+func (v *TokenVault) CanonicalToBridged(ctx context.Context, srcChainID *big.Int, token common.Address) (common.Address, error) {
+	var out []interface{}
+	if err := v.contract.Call(&bind.CallOpts{Context: ctx}, &out, "canonicalToBridged", srcChainID, token); err != nil {
+		return common.Address{}, err
+	}
+	return *abi.ConvertType(out[0], new(common.Address)).(*common.Address), nil
+}
+
+func (t *ERC20) BalanceOf(ctx context.Context, account common.Address) (*big.Int, error) {
+	var out []interface{}
+	if err := t.contract.Call(&bind.CallOpts{Context: ctx}, &out, "balanceOf", account); err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
+}
+
+func (t *ERC20) Transfer(opts *bind.TransactOpts, to common.Address, amount *big.Int) (*types.Transaction, error) {
+	return t.contract.Transact(opts, "transfer", to, amount)
+}
+
+func (t *ERC20) Approve(opts *bind.TransactOpts, spender common.Address, amount *big.Int) (*types.Transaction, error) {
+	return t.contract.Transact(opts, "approve", spender, amount)
+}
+
+// LatestSyncedHeight returns the highest block of the other chain which is known to
+// the taiko contract on n, i.e. TaikoL2 for L1 blocks and TaikoL1 for L2 blocks.
+func LatestSyncedHeight(ctx context.Context, n *ELNode) (uint64, error) {
+	cli, err := n.EthClient()
+	if err != nil {
+		return 0, err
+	}
+	logs, err := cli.FilterLogs(ctx, ethereum.FilterQuery{
+		Addresses: []common.Address{n.deploy.rollupAddress},
+		Topics:    [][]common.Hash{{headerSyncedABI.Events["HeaderSynced"].ID}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	var height uint64
+	for _, log := range logs {
+		if len(log.Topics) < 3 {
+			continue
+		}
+		if h := log.Topics[2].Big().Uint64(); h > height {
+			height = h
+		}
+	}
+	return height, nil
+}
+
+// WaitHeaderSynced waits until the taiko contract on dst knows the block of the
+// source chain at the given height.
+func WaitHeaderSynced(ctx context.Context, dst *ELNode, srcHeight uint64) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		h, err := LatestSyncedHeight(ctx, dst)
+		if err != nil {
+			return err
+		}
+		if h >= srcHeight {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("header %d not synced, latest synced height %d: %w", srcHeight, h, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// WaitMessageStatus waits until the message has the given status on the bridge.
+func WaitMessageStatus(ctx context.Context, b *Bridge, msgHash common.Hash, want MessageStatus) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		s, err := b.MessageStatus(ctx, msgHash)
+		if err != nil {
+			return err
+		}
+		if s == want {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("message %v has status %v, want %v: %w", msgHash, s, want, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// SignalProof creates the proof that the message was sent by the bridge on src, for
+// processing the message on dst.
+func SignalProof(ctx context.Context, src, dst *ELNode, msgHash common.Hash) ([]byte, error) {
+	bridge := src.deploy.bridgeAddress
+	key := crypto.Keccak256Hash(bridge.Bytes(), msgHash.Bytes())
+	return storageProof(ctx, src, dst, bridge, key)
+}
+
+// FailureProof creates the proof that the message failed on dst, for releasing the
+// deposited assets on src.
+func FailureProof(ctx context.Context, src, dst *ELNode, msgHash common.Hash) ([]byte, error) {
+	bridge := dst.deploy.bridgeAddress
+	key := crypto.Keccak256Hash([]byte("MESSAGE_STATUS"), msgHash.Bytes())
+	return storageProof(ctx, dst, src, bridge, key)
+}
+
+// storageProof creates a SignalProof for a storage slot on chain n, at the latest
+// block of n which is synced on the verifying chain.
+func storageProof(ctx context.Context, n, verifier *ELNode, account common.Address, key common.Hash) ([]byte, error) {
+	height, err := LatestSyncedHeight(ctx, verifier)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := n.EthClient()
+	if err != nil {
+		return nil, err
+	}
+	header, err := cli.HeaderByNumber(ctx, new(big.Int).SetUint64(height))
+	if err != nil {
+		return nil, err
+	}
+	result, err := n.GethClient().GetProof(ctx, account, []string{key.Hex()}, header.Number)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.StorageProof) != 1 {
+		return nil, fmt.Errorf("missing storage proof of %v", key)
+	}
+	accountProof, err := encodeProofNodes(result.AccountProof)
+	if err != nil {
+		return nil, err
+	}
+	slotProof, err := encodeProofNodes(result.StorageProof[0].Proof)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := abi.Arguments{{Type: bytesType}, {Type: bytesType}}.Pack(accountProof, slotProof)
+	if err != nil {
+		return nil, err
+	}
+	return abi.Arguments{{Type: signalProofType}}.Pack(signalProof{
+		Header: toBlockHeader(header),
+		Proof:  proof,
+	})
+}
+
+// encodeProofNodes RLP-encodes the trie nodes returned by eth_getProof.
+func encodeProofNodes(nodes []string) ([]byte, error) {
+	list := make([][]byte, len(nodes))
+	for i, n := range nodes {
+		b, err := hexutil.Decode(n)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = b
+	}
+	return rlp.EncodeToBytes(list)
+}
+
+// blockHeader mirrors LibBlockHeader.BlockHeader.
+type blockHeader struct {
+	ParentHash       [32]byte
+	OmmersHash       [32]byte
+	Beneficiary      common.Address
+	StateRoot        [32]byte
+	TransactionsRoot [32]byte
+	ReceiptsRoot     [32]byte
+	LogsBloom        [8][32]byte
+	Difficulty       *big.Int
+	Height           *big.Int
+	GasLimit         uint64
+	GasUsed          uint64
+	Timestamp        uint64
+	ExtraData        []byte
+	MixHash          [32]byte
+	Nonce            uint64
+	BaseFeePerGas    *big.Int
+}
+
+// signalProof mirrors LibBridgeSignal.SignalProof.
+type signalProof struct {
+	Header blockHeader
+	Proof  []byte
+}
+
+var (
+	bytesType, _       = abi.NewType("bytes", "", nil)
+	signalProofType, _ = abi.NewType("tuple", "", []abi.ArgumentMarshaling{
+		{Name: "header", Type: "tuple", Components: []abi.ArgumentMarshaling{
+			{Name: "parentHash", Type: "bytes32"},
+			{Name: "ommersHash", Type: "bytes32"},
+			{Name: "beneficiary", Type: "address"},
+			{Name: "stateRoot", Type: "bytes32"},
+			{Name: "transactionsRoot", Type: "bytes32"},
+			{Name: "receiptsRoot", Type: "bytes32"},
+			{Name: "logsBloom", Type: "bytes32[8]"},
+			{Name: "difficulty", Type: "uint256"},
+			{Name: "height", Type: "uint128"},
+			{Name: "gasLimit", Type: "uint64"},
+			{Name: "gasUsed", Type: "uint64"},
+			{Name: "timestamp", Type: "uint64"},
+			{Name: "extraData", Type: "bytes"},
+			{Name: "mixHash", Type: "bytes32"},
+			{Name: "nonce", Type: "uint64"},
+			{Name: "baseFeePerGas", Type: "uint256"},
+		}},
+		{Name: "proof", Type: "bytes"},
+	})
+)
+
+func toBlockHeader(h *types.Header) blockHeader {
+	var bloom [8][32]byte
+	for i := range bloom {
+		copy(bloom[i][:], h.Bloom[i*32:(i+1)*32])
+	}
+	baseFee := new(big.Int)
+	if h.BaseFee != nil {
+		baseFee.Set(h.BaseFee)
+	}
+	return blockHeader{
+		ParentHash:       h.ParentHash,
+		OmmersHash:       h.UncleHash,
+		Beneficiary:      h.Coinbase,
+		StateRoot:        h.Root,
+		TransactionsRoot: h.TxHash,
+		ReceiptsRoot:     h.ReceiptHash,
+		LogsBloom:        bloom,
+		Difficulty:       h.Difficulty,
+		Height:           h.Number,
+		GasLimit:         h.GasLimit,
+		GasUsed:          h.GasUsed,
+		Timestamp:        h.Time,
+		ExtraData:        h.Extra,
+		MixHash:          h.MixDigest,
+		Nonce:            h.Nonce.Uint64(),
+		BaseFeePerGas:    baseFee,
+	}
+}
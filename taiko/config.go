@@ -1,10 +1,16 @@
 package taiko
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -13,35 +19,78 @@ import (
 	"github.com/taikoxyz/taiko-client/bindings"
 )
 
+// Environment variables of the simulator which configure the test environment.
+//
+// HIVE_TAIKO_CONFIG is the path of a config file, or the config itself as JSON. It
+// replaces the config.json file built into the simulator. A path is resolved inside the
+// simulator container, not on the host running hive. Single fields of the config
+// can be overridden by variables named HIVE_TAIKO_CONFIG_ followed by the upper case
+// JSON field name, e.g. HIVE_TAIKO_CONFIG_L1_NETWORK_ID.
+const (
+	envTaikoConfig         = "HIVE_TAIKO_CONFIG"
+	envTaikoConfigOverride = "HIVE_TAIKO_CONFIG_"
+)
+
 type taikoConfig struct {
 	L1NetworkID    uint64 `json:"l1_network_id"`
+	L2NetworkID    uint64 `json:"l2_network_id"`
 	L1CliquePeriod uint64 `json:"l1_clique_period"`
 	DeployPrivKey  string `json:"deploy_private_key"`
+	DeployAddress  string `json:"deploy_address"`
 	ProverPrivKey  string `json:"prover_private_key"`
 	JWTSecret      string `json:"jwt_secret"`
 }
 
+// DefaultConfig loads the config of the simulator. It is read from config.json, unless
+// another config is given by the environment.
 func DefaultConfig() (*Config, error) {
-	data, err := ioutil.ReadFile("config.json")
+	data, err := readConfig(os.Getenv(envTaikoConfig))
 	if err != nil {
 		return nil, err
 	}
+	return LoadConfig(data, os.LookupEnv)
+}
+
+func readConfig(value string) ([]byte, error) {
+	switch {
+	case value == "":
+		return ioutil.ReadFile("config.json")
+	case strings.HasPrefix(strings.TrimSpace(value), "{"):
+		return []byte(value), nil
+	default:
+		return ioutil.ReadFile(value)
+	}
+}
+
+// LoadConfig parses a JSON config and applies the overrides returned by lookupEnv.
+func LoadConfig(data []byte, lookupEnv func(string) (string, bool)) (*Config, error) {
 	tc := new(taikoConfig)
 	if err := json.Unmarshal(data, tc); err != nil {
+		return nil, fmt.Errorf("invalid taiko config: %w", err)
+	}
+	if err := tc.override(lookupEnv); err != nil {
 		return nil, err
 	}
 	deployAccount, err := NewAccount(tc.DeployPrivKey)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid deploy_private_key: %w", err)
+	}
+	if tc.DeployAddress != "" && common.HexToAddress(tc.DeployAddress) != deployAccount.Address {
+		return nil, fmt.Errorf("deploy_address %s does not match deploy_private_key, want %v",
+			tc.DeployAddress, deployAccount.Address)
 	}
 	proverAccount, err := NewAccount(tc.ProverPrivKey)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid prover_private_key: %w", err)
 	}
 	throwawayAccount, err := NewAccount(bindings.GoldenTouchPrivKey[2:])
 	if err != nil {
 		return nil, err
 	}
+	l2ChainID := params.TaikoAlpha1NetworkID
+	if tc.L2NetworkID != 0 {
+		l2ChainID = new(big.Int).SetUint64(tc.L2NetworkID)
+	}
 	return &Config{
 		L1: &L1Config{
 			ChainID:      big.NewInt(int64(tc.L1NetworkID)),
@@ -50,8 +99,8 @@ func DefaultConfig() (*Config, error) {
 			CliquePeriod: tc.L1CliquePeriod,
 		},
 		L2: &L2Config{
-			ChainID:   params.TaikoAlpha1NetworkID,
-			NetworkID: params.TaikoAlpha1NetworkID.Uint64(),
+			ChainID:   l2ChainID,
+			NetworkID: l2ChainID.Uint64(),
 			JWTSecret: tc.JWTSecret,
 
 			Proposer:              deployAccount,
@@ -65,6 +114,58 @@ func DefaultConfig() (*Config, error) {
 	}, nil
 }
 
+// override sets the fields which have an override variable.
+func (tc *taikoConfig) override(lookupEnv func(string) (string, bool)) error {
+	v := reflect.ValueOf(tc).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name := envTaikoConfigOverride + strings.ToUpper(field.Tag.Get("json"))
+		value, ok := lookupEnv(name)
+		if !ok {
+			continue
+		}
+		switch field.Type.Kind() {
+		case reflect.String:
+			v.Field(i).SetString(value)
+		case reflect.Uint64:
+			n, err := strconv.ParseUint(value, 0, 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %w", name, err)
+			}
+			v.Field(i).SetUint(n)
+		}
+	}
+	return nil
+}
+
+// Validate checks the config against the deployed protocol and the chains of the nodes.
+func (c *Config) Validate(ctx context.Context, l1, l2 *ELNode, protocol *bindings.TaikoDataConfig) error {
+	if protocol.ChainId.Cmp(c.L2.ChainID) != 0 {
+		return fmt.Errorf("L2 chain ID %v of config does not match chain ID %v of TaikoL1", c.L2.ChainID, protocol.ChainId)
+	}
+	for _, n := range []struct {
+		name string
+		node *ELNode
+		want *big.Int
+	}{
+		{"L1", l1, c.L1.ChainID},
+		{"L2", l2, c.L2.ChainID},
+	} {
+		cli, err := n.node.EthClient()
+		if err != nil {
+			return err
+		}
+		id, err := cli.ChainID(ctx)
+		if err != nil {
+			return err
+		}
+		if id.Cmp(n.want) != 0 {
+			return fmt.Errorf("%s chain ID %v of config does not match chain ID %v of node", n.name, n.want, id)
+		}
+	}
+	return nil
+}
+
 type Account struct {
 	PrivateKeyHex string
 	PrivateKey    *ecdsa.PrivateKey
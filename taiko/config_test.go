@@ -0,0 +1,132 @@
+package taiko
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func lookupMap(m map[string]string) func(string) (string, bool) {
+	return func(k string) (string, bool) {
+		v, ok := m[k]
+		return v, ok
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	data, err := ioutil.ReadFile("config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(data, lookupMap(nil))
+	if err != nil {
+		t.Fatalf("can't load config.json: %v", err)
+	}
+	if c.L1.NetworkID != 31336 || c.L1.ChainID.Uint64() != 31336 {
+		t.Errorf("wrong L1 network %d, chain %v", c.L1.NetworkID, c.L1.ChainID)
+	}
+	if c.L2.NetworkID != 167001 || c.L2.ChainID.Uint64() != 167001 {
+		t.Errorf("wrong L2 network %d, chain %v", c.L2.NetworkID, c.L2.ChainID)
+	}
+	if c.L1.Deployer.Address.Hex() != "0xDf08F82De32B8d460adbE8D72043E3a7e25A3B39" {
+		t.Errorf("wrong deployer %v", c.L1.Deployer.Address)
+	}
+}
+
+func TestLoadConfigOverride(t *testing.T) {
+	data, err := ioutil.ReadFile("config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(data, lookupMap(map[string]string{
+		"HIVE_TAIKO_CONFIG_L1_NETWORK_ID":    "0x10",
+		"HIVE_TAIKO_CONFIG_L2_NETWORK_ID":    "200",
+		"HIVE_TAIKO_CONFIG_L1_CLIQUE_PERIOD": "5",
+		"HIVE_TAIKO_CONFIG_JWT_SECRET":       "00",
+		// names are upper case, so this one is ignored
+		"HIVE_TAIKO_CONFIG_prover_private_key": "invalid",
+	}))
+	if err != nil {
+		t.Fatalf("can't load config: %v", err)
+	}
+	if c.L1.NetworkID != 16 {
+		t.Errorf("wrong L1 network %d, want 16", c.L1.NetworkID)
+	}
+	if c.L2.NetworkID != 200 || c.L2.ChainID.Uint64() != 200 {
+		t.Errorf("wrong L2 network %d, chain %v, want 200", c.L2.NetworkID, c.L2.ChainID)
+	}
+	if c.L1.CliquePeriod != 5 {
+		t.Errorf("wrong clique period %d, want 5", c.L1.CliquePeriod)
+	}
+	if c.L2.JWTSecret != "00" {
+		t.Errorf("wrong JWT secret %q, want 00", c.L2.JWTSecret)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	data, err := ioutil.ReadFile("config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		data    []byte
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name:    "invalid JSON",
+			data:    []byte("{"),
+			wantErr: "invalid taiko config",
+		},
+		{
+			name:    "override not a number",
+			data:    data,
+			env:     map[string]string{"HIVE_TAIKO_CONFIG_L1_NETWORK_ID": "abc"},
+			wantErr: "invalid HIVE_TAIKO_CONFIG_L1_NETWORK_ID",
+		},
+		{
+			name:    "override negative",
+			data:    data,
+			env:     map[string]string{"HIVE_TAIKO_CONFIG_L2_NETWORK_ID": "-1"},
+			wantErr: "invalid HIVE_TAIKO_CONFIG_L2_NETWORK_ID",
+		},
+		{
+			name:    "invalid deploy key",
+			data:    data,
+			env:     map[string]string{"HIVE_TAIKO_CONFIG_DEPLOY_PRIVATE_KEY": "xyz"},
+			wantErr: "invalid deploy_private_key",
+		},
+		{
+			name:    "deploy address of other key",
+			data:    data,
+			env:     map[string]string{"HIVE_TAIKO_CONFIG_DEPLOY_ADDRESS": "0x0000000000000000000000000000000000000001"},
+			wantErr: "does not match deploy_private_key",
+		},
+		{
+			name:    "invalid prover key",
+			data:    data,
+			env:     map[string]string{"HIVE_TAIKO_CONFIG_PROVER_PRIVATE_KEY": ""},
+			wantErr: "invalid prover_private_key",
+		},
+	}
+	for _, test := range tests {
+		_, err := LoadConfig(test.data, lookupMap(test.env))
+		if err == nil {
+			t.Errorf("%s: no error, want %q", test.name, test.wantErr)
+		} else if !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("%s: wrong error %q, want %q", test.name, err, test.wantErr)
+		}
+	}
+}
+
+func TestReadConfig(t *testing.T) {
+	inline := `{"l1_network_id": 1}`
+	data, err := readConfig(" " + inline)
+	if err != nil || string(data) != " "+inline {
+		t.Errorf("inline config read as %q, %v", data, err)
+	}
+	if _, err := readConfig("does-not-exist.json"); err == nil {
+		t.Error("no error for missing config file")
+	}
+}
@@ -0,0 +1,254 @@
+package taiko
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/taikoxyz/taiko-client/bindings"
+)
+
+// Divergence is the first difference found between the chains of two L2 nodes.
+type Divergence struct {
+	Height uint64
+	// What differs, e.g. "state root".
+	What    string
+	A, B    *ELNode
+	HeaderA *types.Header
+	HeaderB *types.Header
+	// Detail describes the difference if it is not in the headers.
+	Detail string
+}
+
+func (d *Divergence) Error() string {
+	msg := fmt.Sprintf("L2 nodes %s and %s diverge at height %d: different %s", d.A.Container, d.B.Container, d.Height, d.What)
+	if d.Detail != "" {
+		msg += " (" + d.Detail + ")"
+	}
+	return msg + fmt.Sprintf("\n  %s: %s\n  %s: %s", d.A.Container, formatHeader(d.HeaderA), d.B.Container, formatHeader(d.HeaderB))
+}
+
+func formatHeader(h *types.Header) string {
+	return fmt.Sprintf("number=%d hash=%v parent=%v root=%v coinbase=%v time=%d gasUsed=%d",
+		h.Number, h.Hash(), h.ParentHash, h.Root, h.Coinbase, h.Time, h.GasUsed)
+}
+
+// CheckL2Consistency waits until all L2 nodes reach the target height, and verifies
+// that they agree on every block up to it: block hashes, state roots and L1 origins.
+// Each block is correlated with the TaikoL1 proposal it was derived from through its
+// anchor transaction and L1 origin, so proposals which didn't produce a block, like
+// invalid transaction lists on the throwaway block path, don't shift the L2 heights.
+// The L1 origin of each block has to refer to the L1 block containing the proposal.
+// The first difference is returned as a *Divergence.
+//
+// Nodes which synced a block through p2p have no L1 origin for it, these are left out
+// of the L1 origin comparison.
+func CheckL2Consistency(ctx context.Context, l1 *ELNode, l2s []*ELNode, target uint64) error {
+	if len(l2s) == 0 {
+		return fmt.Errorf("no L2 nodes")
+	}
+	clients := make([]*ethclient.Client, len(l2s))
+	for i, n := range l2s {
+		if err := WaitHeight(ctx, n, GreaterEqual(target)); err != nil {
+			return err
+		}
+		cli, err := n.EthClient()
+		if err != nil {
+			return err
+		}
+		defer cli.Close()
+		clients[i] = cli
+	}
+	proposed, err := ProposedBlocks(ctx, l1, 0)
+	if err != nil {
+		return err
+	}
+
+	// Proposals are derived in the order of their IDs, so the proposal of a block
+	// follows the proposal of its parent.
+	next := 0
+	for height := uint64(1); height <= target; height++ {
+		num := new(big.Int).SetUint64(height)
+		var first *types.Block
+		for i, n := range l2s {
+			block, err := clients[i].BlockByNumber(ctx, num)
+			if err != nil {
+				return fmt.Errorf("%s: can't get L2 block %d: %w", n.Container, height, err)
+			}
+			if i == 0 {
+				first = block
+			} else if d := compareBlocks(height, l2s[0], n, first.Header(), block.Header()); d != nil {
+				return d
+			}
+		}
+		i, origins, err := proposalOf(ctx, l2s, first, proposed[next:])
+		if err != nil {
+			return err
+		}
+		b := proposed[next+i]
+		next += i + 1
+		if err := checkOrigins(l2s, first, b, origins); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// proposalOf returns the index of the proposal an L2 block was derived from, and the
+// L1 origins the nodes recorded for it. Candidates are the proposals which recorded the
+// L1 block referenced by the anchor transaction. The first candidate is taken unless
+// the L1 origins of its ID belong to another L2 block.
+func proposalOf(ctx context.Context, l2s []*ELNode, block *types.Block, proposed []*ProposedBlock) (int, []*L1Origin, error) {
+	l1Height, l1Hash, err := AnchorOf(l2s[0], block)
+	if err != nil {
+		return 0, nil, err
+	}
+	for i, b := range proposed {
+		if b.Meta.L1Height != l1Height || common.Hash(b.Meta.L1Hash) != l1Hash {
+			continue
+		}
+		origins, err := l1Origins(ctx, l2s, b.Meta.Id)
+		if err != nil {
+			return 0, nil, err
+		}
+		if origin := firstOrigin(origins); origin == nil || origin.L2BlockHash == block.Hash() {
+			return i, origins, nil
+		}
+	}
+	return 0, nil, fmt.Errorf("L2 block %d (%v) with anchor of L1 block %d (%v) was not proposed to TaikoL1",
+		block.Number(), block.Hash(), l1Height, l1Hash)
+}
+
+// l1Origins returns the L1 origins the nodes recorded for a block ID, nil for nodes
+// without one.
+func l1Origins(ctx context.Context, l2s []*ELNode, id *big.Int) ([]*L1Origin, error) {
+	origins := make([]*L1Origin, len(l2s))
+	for i, n := range l2s {
+		origin, err := L1OriginByID(ctx, n, id)
+		if err != nil && err.Error() != ethereum.NotFound.Error() {
+			return nil, fmt.Errorf("%s: can't get L1 origin of block %d: %w", n.Container, id, err)
+		}
+		origins[i] = origin
+	}
+	return origins, nil
+}
+
+func firstOrigin(origins []*L1Origin) *L1Origin {
+	for _, origin := range origins {
+		if origin != nil {
+			return origin
+		}
+	}
+	return nil
+}
+
+// checkOrigins verifies that the L1 origins of the nodes agree, and that they refer to
+// the L2 block and to the L1 block containing its proposal.
+func checkOrigins(l2s []*ELNode, block *types.Block, b *ProposedBlock, origins []*L1Origin) error {
+	height := block.NumberU64()
+	var (
+		ref     *L1Origin
+		refNode *ELNode
+	)
+	for i, origin := range origins {
+		n := l2s[i]
+		if origin == nil {
+			continue
+		}
+		if origin.L2BlockHash != block.Hash() {
+			return fmt.Errorf("%s: L1 origin of block %d has L2 hash %v, L2 block %d is %v",
+				n.Container, b.Meta.Id, origin.L2BlockHash, height, block.Hash())
+		}
+		if ref == nil {
+			ref, refNode = origin, n
+			continue
+		}
+		if origin.L1BlockHash != ref.L1BlockHash {
+			return &Divergence{
+				Height: height, What: "L1 origin", A: refNode, B: n,
+				HeaderA: block.Header(), HeaderB: block.Header(),
+				Detail: fmt.Sprintf("L1 block %d %v vs %d %v",
+					ref.L1BlockHeight.ToInt(), ref.L1BlockHash,
+					origin.L1BlockHeight.ToInt(), origin.L1BlockHash),
+			}
+		}
+	}
+	if ref != nil && ref.L1BlockHash != b.Log.BlockHash {
+		return fmt.Errorf("%s: L1 origin of L2 block %d is L1 block %d (%v), block was proposed in L1 block %d (%v)",
+			refNode.Container, height, ref.L1BlockHeight.ToInt(), ref.L1BlockHash,
+			b.Log.BlockNumber, b.Log.BlockHash)
+	}
+	return nil
+}
+
+func compareBlocks(height uint64, a, b *ELNode, ha, hb *types.Header) *Divergence {
+	var what string
+	switch {
+	case ha.Root != hb.Root:
+		what = "state root"
+	case ha.Hash() != hb.Hash():
+		what = "block hash"
+	default:
+		return nil
+	}
+	return &Divergence{Height: height, What: what, A: a, B: b, HeaderA: ha, HeaderB: hb}
+}
+
+// AnchorOf returns the L1 block referenced by the anchor transaction of an L2 block.
+// It fails if the first transaction of the block is not an anchor transaction.
+func AnchorOf(l2 *ELNode, block *types.Block) (uint64, common.Hash, error) {
+	num := block.Number()
+	txs := block.Transactions()
+	if len(txs) == 0 {
+		return 0, common.Hash{}, fmt.Errorf("L2 block %d has no anchor transaction", num)
+	}
+	anchor := txs[0]
+	if to := anchor.To(); to == nil || *to != l2.deploy.rollupAddress {
+		return 0, common.Hash{}, fmt.Errorf("first transaction %v of L2 block %d is not sent to TaikoL2", anchor.Hash(), num)
+	}
+	l1Height, l1Hash, err := unpackAnchor(anchor.Data())
+	if err != nil {
+		return 0, common.Hash{}, fmt.Errorf("anchor transaction of L2 block %d: %w", num, err)
+	}
+	return l1Height, l1Hash, nil
+}
+
+// unpackAnchor returns the L1 block referenced by the calldata of an anchor transaction.
+func unpackAnchor(data []byte) (uint64, common.Hash, error) {
+	l2ABI, err := bindings.TaikoL2ClientMetaData.GetAbi()
+	if err != nil {
+		return 0, common.Hash{}, err
+	}
+	method, err := l2ABI.MethodById(data)
+	if err != nil {
+		return 0, common.Hash{}, err
+	}
+	if method.Name != "anchor" {
+		return 0, common.Hash{}, fmt.Errorf("calls %s, not anchor", method.Name)
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return 0, common.Hash{}, err
+	}
+	if len(args) < 2 {
+		return 0, common.Hash{}, fmt.Errorf("anchor has %d arguments", len(args))
+	}
+	var height uint64
+	switch h := args[0].(type) {
+	case uint64:
+		height = h
+	case *big.Int:
+		height = h.Uint64()
+	default:
+		return 0, common.Hash{}, fmt.Errorf("unexpected L1 height type %T", h)
+	}
+	hash, ok := args[1].([32]byte)
+	if !ok {
+		return 0, common.Hash{}, fmt.Errorf("unexpected L1 hash type %T", args[1])
+	}
+	return height, hash, nil
+}
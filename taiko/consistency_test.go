@@ -0,0 +1,177 @@
+package taiko
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/hive/hivesim"
+	"github.com/taikoxyz/taiko-client/bindings"
+)
+
+func testNode(name string) *ELNode {
+	return &ELNode{Node: &Node{Client: &hivesim.Client{Container: name}}}
+}
+
+// anchorData returns the calldata of an anchor transaction. The arguments are encoded as
+// words, whether the L1 height is an uint64 or an uint256 in the ABI.
+func anchorData(t *testing.T, height uint64, hash common.Hash) []byte {
+	l2ABI, err := bindings.TaikoL2ClientMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := append([]byte{}, l2ABI.Methods["anchor"].ID...)
+	data = append(data, common.LeftPadBytes(new(big.Int).SetUint64(height).Bytes(), 32)...)
+	return append(data, hash[:]...)
+}
+
+func TestUnpackAnchor(t *testing.T) {
+	hash := common.HexToHash("0x01020304")
+	valid := anchorData(t, 42, hash)
+	tests := []struct {
+		name   string
+		data   []byte
+		height uint64
+		hash   common.Hash
+		err    bool
+	}{
+		{name: "valid", data: valid, height: 42, hash: hash},
+		{name: "no selector", data: valid[:3], err: true},
+		{name: "unknown method", data: append([]byte{0xde, 0xad, 0xbe, 0xef}, valid[4:]...), err: true},
+		{name: "missing L1 hash", data: valid[:4+32], err: true},
+		{name: "no arguments", data: valid[:4], err: true},
+	}
+	for _, test := range tests {
+		height, hash, err := unpackAnchor(test.data)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: no error, got L1 block %d %v", test.name, height, hash)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if height != test.height || hash != test.hash {
+			t.Errorf("%s: got L1 block %d %v, want %d %v", test.name, height, hash, test.height, test.hash)
+		}
+	}
+}
+
+func TestAnchorOf(t *testing.T) {
+	var (
+		taikoL2 = common.HexToAddress("0x1000000000000000000000000000000000000002")
+		node    = &ELNode{deploy: &deployResult{rollupAddress: taikoL2}}
+		hash    = common.HexToHash("0x01020304")
+		anchor  = anchorData(t, 42, hash)
+	)
+	block := func(txs ...*types.Transaction) *types.Block {
+		return types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)}).WithBody(txs, nil)
+	}
+	tx := func(to common.Address, data []byte) *types.Transaction {
+		return types.NewTx(&types.LegacyTx{To: &to, Data: data})
+	}
+	tests := []struct {
+		name  string
+		block *types.Block
+		err   string
+	}{
+		{name: "anchor", block: block(tx(taikoL2, anchor), tx(common.Address{1}, nil))},
+		{name: "no transactions", block: block(), err: "no anchor transaction"},
+		{name: "not sent to TaikoL2", block: block(tx(common.Address{1}, anchor)), err: "is not sent to TaikoL2"},
+		{name: "malformed anchor", block: block(tx(taikoL2, anchor[:4+32])), err: "anchor transaction of L2 block 1"},
+	}
+	for _, test := range tests {
+		height, l1Hash, err := AnchorOf(node, test.block)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: wrong error %v, want %q", test.name, err, test.err)
+			}
+			continue
+		}
+		if err != nil || height != 42 || l1Hash != hash {
+			t.Errorf("%s: got L1 block %d %v, error %v", test.name, height, l1Hash, err)
+		}
+	}
+}
+
+func TestCompareBlocks(t *testing.T) {
+	a, b := testNode("a"), testNode("b")
+	header := &types.Header{Number: big.NewInt(5), Root: common.HexToHash("0x01"), Time: 100}
+	withRoot := func(h types.Header, root common.Hash) *types.Header { h.Root = root; return &h }
+	withTime := func(h types.Header, time uint64) *types.Header { h.Time = time; return &h }
+	tests := []struct {
+		name   string
+		ha, hb *types.Header
+		what   string
+	}{
+		{name: "equal", ha: header, hb: withTime(*header, header.Time)},
+		{name: "state root", ha: header, hb: withRoot(*header, common.HexToHash("0x02")), what: "state root"},
+		{name: "block hash", ha: header, hb: withTime(*header, 101), what: "block hash"},
+		{name: "root and hash", ha: header, hb: withTime(*withRoot(*header, common.HexToHash("0x02")), 101), what: "state root"},
+	}
+	for _, test := range tests {
+		d := compareBlocks(5, a, b, test.ha, test.hb)
+		if test.what == "" {
+			if d != nil {
+				t.Errorf("%s: unexpected divergence: %v", test.name, d)
+			}
+			continue
+		}
+		if d == nil {
+			t.Errorf("%s: no divergence, want different %s", test.name, test.what)
+			continue
+		}
+		if d.What != test.what || d.Height != 5 || d.A != a || d.B != b || d.HeaderA != test.ha || d.HeaderB != test.hb {
+			t.Errorf("%s: wrong divergence %+v", test.name, d)
+		}
+	}
+}
+
+func TestCheckOrigins(t *testing.T) {
+	var (
+		nodes     = []*ELNode{testNode("a"), testNode("b"), testNode("c")}
+		block     = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(3)})
+		l1Hash    = common.HexToHash("0x11")
+		proposal  = &ProposedBlock{Meta: bindings.TaikoDataBlockMetadata{Id: big.NewInt(3)}, Log: types.Log{BlockNumber: 7, BlockHash: l1Hash}}
+		origin    = &L1Origin{BlockID: (*hexutil.Big)(big.NewInt(3)), L2BlockHash: block.Hash(), L1BlockHeight: (*hexutil.Big)(big.NewInt(7)), L1BlockHash: l1Hash}
+		otherL1   = &L1Origin{BlockID: origin.BlockID, L2BlockHash: block.Hash(), L1BlockHeight: (*hexutil.Big)(big.NewInt(8)), L1BlockHash: common.HexToHash("0x12")}
+		otherL2   = &L1Origin{BlockID: origin.BlockID, L2BlockHash: common.HexToHash("0x21"), L1BlockHeight: origin.L1BlockHeight, L1BlockHash: l1Hash}
+		divergent = &Divergence{}
+	)
+	tests := []struct {
+		name    string
+		origins []*L1Origin
+		// err is a substring of the error, divergence is set if the error is a *Divergence.
+		err        string
+		divergence bool
+	}{
+		{name: "no origins", origins: []*L1Origin{nil, nil, nil}},
+		{name: "single origin", origins: []*L1Origin{nil, origin, nil}},
+		{name: "all origins", origins: []*L1Origin{origin, origin, origin}},
+		{name: "wrong L2 hash", origins: []*L1Origin{origin, otherL2, nil}, err: "b: L1 origin of block 3 has L2 hash"},
+		{name: "different L1 blocks", origins: []*L1Origin{nil, origin, otherL1}, err: "L2 nodes b and c diverge at height 3: different L1 origin", divergence: true},
+		{name: "not the proposal block", origins: []*L1Origin{otherL1, nil, otherL1}, err: "a: L1 origin of L2 block 3 is L1 block 8"},
+	}
+	for _, test := range tests {
+		err := checkOrigins(nodes, block, proposal, test.origins)
+		if test.err == "" {
+			if err != nil {
+				t.Errorf("%s: %v", test.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: wrong error %v, want %q", test.name, err, test.err)
+			continue
+		}
+		if errors.As(err, &divergent) != test.divergence {
+			t.Errorf("%s: error %T, divergence %t", test.name, err, test.divergence)
+		}
+	}
+}
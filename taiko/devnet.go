@@ -16,6 +16,7 @@ type Devnet struct {
 	drivers   []*Node
 	proposers []*Node
 	provers   []*Node
+	relayers  []*Node
 }
 
 type DevOption func(*Devnet)
@@ -61,3 +62,81 @@ func (d *Devnet) GetL2ELNode(idx int) *ELNode {
 	}
 	return d.L2Engines[idx]
 }
+
+func (d *Devnet) GetDriverNode(idx int) *Node {
+	if idx < 0 || idx >= len(d.drivers) {
+		return nil
+	}
+	return d.drivers[idx]
+}
+
+func (d *Devnet) GetProposerNode(idx int) *Node {
+	if idx < 0 || idx >= len(d.proposers) {
+		return nil
+	}
+	return d.proposers[idx]
+}
+
+func (d *Devnet) GetProverNode(idx int) *Node {
+	if idx < 0 || idx >= len(d.provers) {
+		return nil
+	}
+	return d.provers[idx]
+}
+
+func (d *Devnet) GetRelayerNode(idx int) *Node {
+	if idx < 0 || idx >= len(d.relayers) {
+		return nil
+	}
+	return d.relayers[idx]
+}
+
+// nodes returns all nodes of the network, clients depending on others come first.
+func (d *Devnet) nodes() []*Node {
+	d.Lock()
+	defer d.Unlock()
+	var all []*Node
+	all = append(all, d.relayers...)
+	all = append(all, d.provers...)
+	all = append(all, d.proposers...)
+	all = append(all, d.drivers...)
+	for _, n := range d.L1Engines {
+		all = append(all, n.Node)
+	}
+	for _, n := range d.L2Engines {
+		all = append(all, n.Node)
+	}
+	return all
+}
+
+// remove removes a node from the network.
+func (d *Devnet) remove(n *Node) {
+	d.Lock()
+	defer d.Unlock()
+	d.L1Engines = removeELNode(d.L1Engines, n)
+	d.L2Engines = removeELNode(d.L2Engines, n)
+	d.drivers = removeNode(d.drivers, n)
+	d.proposers = removeNode(d.proposers, n)
+	d.provers = removeNode(d.provers, n)
+	d.relayers = removeNode(d.relayers, n)
+}
+
+func removeNode(nodes []*Node, n *Node) []*Node {
+	out := nodes[:0]
+	for _, m := range nodes {
+		if m != n {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func removeELNode(nodes []*ELNode, n *Node) []*ELNode {
+	out := nodes[:0]
+	for _, m := range nodes {
+		if m.Node != n {
+			out = append(out, m)
+		}
+	}
+	return out
+}
@@ -39,3 +39,11 @@ func WithProverNode(n *Node) DevOption {
 		d.provers = append(d.provers, n)
 	}
 }
+
+func WithRelayerNode(n *Node) DevOption {
+	return func(d *Devnet) {
+		d.Lock()
+		defer d.Unlock()
+		d.relayers = append(d.relayers, n)
+	}
+}
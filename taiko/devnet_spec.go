@@ -0,0 +1,148 @@
+package taiko
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+// DevnetSpec describes the topology of a devnet started by TestEnv.StartDevnet.
+type DevnetSpec struct {
+	// L2NodeTypes holds the sync mode ("full" or "snap") of every L2 execution node.
+	// Each L2 node is driven by its own driver.
+	L2NodeTypes []string
+	// Number of proposers and provers. They are attached to the L2 nodes round-robin,
+	// and every one of them uses a separate L1 account.
+	Proposers int
+	Provers   int
+	// EnableL2P2P connects the L2 nodes and lets the drivers sync verified blocks
+	// over the L2 p2p network.
+	EnableL2P2P bool
+	// L1DevMode runs the L1 node with the taiko-l1-dev client, whose blocks and
+	// timestamps are set by the L1Controller without waiting.
+	L1DevMode bool
+	// Relayer starts a bridge relayer between L1 and the first L2 node.
+	Relayer bool
+	// Protocol runs the L1 node with a plain eth1 client instead of the taiko-l1 image,
+	// and deploys the L1 contracts with these parameters. It requires the go-ethereum
+	// and taiko-protocol clients, and can't be combined with Relayer or L1DevMode, see
+	// DeployProtocol.
+	Protocol ProtocolParams
+}
+
+// SingleNodeSpec is the devnet used by StartSingleNodeNet.
+func SingleNodeSpec() *DevnetSpec {
+	return &DevnetSpec{
+		L2NodeTypes: []string{"full"},
+		Proposers:   1,
+		Provers:     1,
+	}
+}
+
+// proposerAndProverFunding is the L1 balance of additional proposer and prover accounts.
+var proposerAndProverFunding = new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))
+
+// StartDevnet starts all nodes of the spec and wires them together.
+func (e *TestEnv) StartDevnet(spec *DevnetSpec) {
+	t := e.T
+	require.NotEmpty(t, spec.L2NodeTypes, "devnet needs at least one L2 node")
+	require.False(t, spec.Relayer && spec.Protocol != nil, "devnet with new protocol contracts has no L1 bridge")
+	require.False(t, spec.L1DevMode && spec.Protocol != nil, "devnet with new protocol contracts has no L1 dev mode")
+
+	l1Def := e.Clients.L1
+	if spec.L1DevMode {
+		require.NotNil(t, e.Clients.L1Dev, "devnet in L1 dev mode needs the %s client", taikoL1Dev)
+		l1Def = e.Clients.L1Dev
+	}
+	if spec.Protocol != nil {
+		e.startProtocolL1L2(spec.Protocol, WithELNodeType(spec.L2NodeTypes[0]))
+	} else {
+		e.startL1L2(l1Def, WithELNodeType(spec.L2NodeTypes[0]))
+	}
+	for _, typ := range spec.L2NodeTypes[1:] {
+		opts := []NodeOption{WithELNodeType(typ)}
+		if spec.EnableL2P2P {
+			opts = append(opts, WithBootNode(e.Net.GetL2ENodes(t)))
+		}
+		e.Net.Apply(WithL2Node(e.NewL2ELNode(opts...)))
+	}
+
+	l1 := e.Net.GetL1ELNode(0)
+	for i := range spec.L2NodeTypes {
+		var opts []NodeOption
+		if spec.EnableL2P2P {
+			opts = append(opts, WithEnableL2P2P())
+		}
+		e.Net.Apply(WithDriverNode(e.NewDriverNode(l1, e.Net.GetL2ELNode(i), opts...)))
+	}
+	for i := 0; i < spec.Provers; i++ {
+		l2 := e.Net.GetL2ELNode(i % len(spec.L2NodeTypes))
+		var opts []NodeOption
+		if i > 0 {
+			opts = append(opts, WithProverPrivateKey(e.newL1Account(l1)))
+		}
+		e.Net.Apply(WithProverNode(e.NewProverNode(l1, l2, opts...)))
+	}
+	for i := 0; i < spec.Proposers; i++ {
+		l2 := e.Net.GetL2ELNode(i % len(spec.L2NodeTypes))
+		var opts []NodeOption
+		if i > 0 {
+			opts = append(opts, WithProposerPrivateKey(e.newL1Account(l1)))
+		}
+		e.Net.Apply(WithProposerNode(e.NewProposerNode(l1, l2, opts...)))
+	}
+	if spec.Relayer {
+		e.Net.Apply(WithRelayerNode(e.NewRelayerNode(l1, e.Net.GetL2ELNode(0))))
+	}
+}
+
+// startProtocolL1L2 starts the first L2 node and a plain L1 node, and deploys the L1
+// contracts with the given parameters.
+func (e *TestEnv) startProtocolL1L2(params ProtocolParams, l2Opts ...NodeOption) {
+	l2 := e.NewL2ELNode(l2Opts...)
+	l1 := e.DeployProtocol(e.NewPlainL1ELNode(), l2, params)
+	e.loadProtocolConfig(l1, l2)
+	e.Net = NewDevnet(e.T, e.Conf, WithL2Node(l2), WithL1Node(l1))
+}
+
+// StopDevnet stops all nodes of the devnet.
+func (e *TestEnv) StopDevnet() {
+	t := e.T
+	for _, n := range e.Net.nodes() {
+		t.Sim.StopClient(t.SuiteID, t.TestID, n.Container)
+	}
+}
+
+// StopNode stops a single node and removes it from the devnet.
+func (e *TestEnv) StopNode(n *Node) {
+	t := e.T
+	e.Net.remove(n)
+	require.NoError(t, t.Sim.StopClient(t.SuiteID, t.TestID, n.Container))
+}
+
+// newL1Account creates a funded L1 account and returns its private key in hex.
+func (e *TestEnv) newL1Account(l1 *ELNode) string {
+	t := e.T
+	cli, err := l1.EthClient()
+	require.NoError(t, err)
+	addr := e.L1Vault.CreateAccount(e.Context, cli, proposerAndProverFunding)
+	key := e.L1Vault.FindKey(addr)
+	require.NotNil(t, key, "key of account %v not in vault", addr)
+	return common.Bytes2Hex(crypto.FromECDSA(key))
+}
+
+// fundL2Account funds the account of the given private key on L2, and adds the key to
+// the L2 vault.
+func (e *TestEnv) fundL2Account(l2 *ELNode, key string) {
+	t := e.T
+	k, err := crypto.HexToECDSA(key)
+	require.NoError(t, err)
+	cli, err := l2.EthClient()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(k.PublicKey)
+	require.NoError(t, e.L2Vault.FundAccount(e.Context, cli, addr, proposerAndProverFunding))
+	e.L2Vault.InsertKey(k)
+}
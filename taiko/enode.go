@@ -1,13 +1,16 @@
 package taiko
 
 import (
+	"encoding/base64"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/ethclient/gethclient"
 	"github.com/ethereum/hive/hivesim"
+	"github.com/stretchr/testify/require"
 	"github.com/taikoxyz/taiko-client/bindings"
 )
 
@@ -22,6 +25,10 @@ const (
 	enginePort  = 8551
 )
 
+// datadirArchive is the path of the data directory archive restored by taiko-geth
+// on startup.
+const datadirArchive = "/datadir.tar.gz"
+
 type ELNode struct {
 	*Node
 	deploy      *deployResult
@@ -56,6 +63,13 @@ func (e *ELNode) EthClient() (*ethclient.Client, error) {
 	return cli, nil
 }
 
+// MustEthClient is like EthClient, but fails the test if the node can't be dialed.
+func (e *ELNode) MustEthClient(t *hivesim.T) *ethclient.Client {
+	cli, err := e.EthClient()
+	require.NoError(t, err, "can't dial %s", e.Container)
+	return cli
+}
+
 func (e *ELNode) TaikoL1Client() (*bindings.TaikoL1Client, error) {
 	c, err := e.EthClient()
 	if err != nil {
@@ -100,3 +114,21 @@ func (e *ELNode) getL2Deployments(t *hivesim.T) *deployResult {
 		testERC20Address: query("TestERC20"),
 	}
 }
+
+// SnapshotDatadir stops taiko-geth gracefully and writes an archive of its data
+// directory to path. The node can't be used afterwards, the archive is restored by a
+// new node started WithDatadir.
+func (e *ELNode) SnapshotDatadir(path string) error {
+	result, err := e.Exec("snapshot.sh")
+	if err != nil {
+		return err
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to snapshot data directory of %s, exit code %d: %s", e.Container, result.ExitCode, result.Stderr)
+	}
+	data, err := base64.StdEncoding.DecodeString(result.Stdout)
+	if err != nil {
+		return fmt.Errorf("invalid data directory archive of %s: %w", e.Container, err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
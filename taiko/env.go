@@ -3,6 +3,7 @@ package taiko
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"time"
@@ -63,15 +64,19 @@ type TestEnv struct {
 	TaikoConf *bindings.TaikoDataConfig
 }
 
+// NewTestEnv creates the environment of a test, which uses the first combination
+// of clients.
 func NewTestEnv(ctx context.Context, t *hivesim.T) *TestEnv {
+	return NewTestEnvWithClients(ctx, t, ClientCombinations(t)[0])
+}
+
+// NewTestEnvWithClients creates the environment of a test using the given clients.
+func NewTestEnvWithClients(ctx context.Context, t *hivesim.T, clients *ClientsByRole) *TestEnv {
 	e := &TestEnv{
 		T:       t,
 		Context: ctx,
+		Clients: clients,
 	}
-	clientTypes, err := t.Sim.ClientTypes()
-	require.NoError(t, err, "failed to retrieve list of client types: %v", err)
-	e.Clients = Roles(t, clientTypes)
-
 	c, err := DefaultConfig()
 	require.NoError(t, err)
 
@@ -83,31 +88,11 @@ func NewTestEnv(ctx context.Context, t *hivesim.T) *TestEnv {
 }
 
 func (e *TestEnv) StartSingleNodeNet() {
-	e.StartL1L2Driver(WithELNodeType("full"))
-	l1, l2 := e.Net.GetL1ELNode(0), e.Net.GetL2ELNode(0)
-	e.Net.Apply(
-		WithProverNode(e.NewProverNode(l1, l2)),
-		WithProposerNode(e.NewProposerNode(l1, l2)),
-	)
+	e.StartDevnet(SingleNodeSpec())
 }
 
 func (e *TestEnv) StopSingleNodeNet() {
-	t := e.T
-	for _, n := range e.Net.provers {
-		t.Sim.StopClient(t.SuiteID, t.TestID, n.Container)
-	}
-	for _, n := range e.Net.proposers {
-		t.Sim.StopClient(t.SuiteID, t.TestID, n.Container)
-	}
-	for _, n := range e.Net.drivers {
-		t.Sim.StopClient(t.SuiteID, t.TestID, n.Container)
-	}
-	for _, n := range e.Net.L1Engines {
-		t.Sim.StopClient(t.SuiteID, t.TestID, n.Container)
-	}
-	for _, n := range e.Net.L2Engines {
-		t.Sim.StopClient(t.SuiteID, t.TestID, n.Container)
-	}
+	e.StopDevnet()
 }
 
 func (e *TestEnv) StartL1L2Driver(l2Opts ...NodeOption) {
@@ -117,14 +102,13 @@ func (e *TestEnv) StartL1L2Driver(l2Opts ...NodeOption) {
 }
 
 func (e *TestEnv) StartL1L2(l2Opts ...NodeOption) {
-	t := e.T
+	e.startL1L2(e.Clients.L1, l2Opts...)
+}
+
+func (e *TestEnv) startL1L2(l1Def *hivesim.ClientDefinition, l2Opts ...NodeOption) {
 	l2 := e.NewL2ELNode(l2Opts...)
-	l1 := e.NewL1ELNode(l2)
-	taikoL1, err := l1.TaikoL1Client()
-	require.NoError(t, err)
-	c, err := taikoL1.GetConfig(nil)
-	require.NoError(t, err)
-	e.TaikoConf = &c
+	l1 := e.newL1ELNode(l1Def, l2)
+	e.loadProtocolConfig(l1, l2)
 	opts := []DevOption{
 		WithL2Node(l2),
 		WithL1Node(l1),
@@ -132,22 +116,42 @@ func (e *TestEnv) StartL1L2(l2Opts ...NodeOption) {
 	e.Net = NewDevnet(e.T, e.Conf, opts...)
 }
 
+// loadProtocolConfig reads the config of the TaikoL1 contract used by l1.
+func (e *TestEnv) loadProtocolConfig(l1, l2 *ELNode) {
+	t := e.T
+	taikoL1, err := l1.TaikoL1Client()
+	require.NoError(t, err)
+	c, err := taikoL1.GetConfig(nil)
+	require.NoError(t, err)
+	require.NoError(t, e.Conf.Validate(e.Context, l1, l2, &c))
+	e.TaikoConf = &c
+}
+
 func (e *TestEnv) GenSomeL1Blocks(t *hivesim.T, cnt uint64) {
-	t, ctx := e.T, e.Context
-	require.NoError(t, GenSomeBlocks(ctx, e.Net.GetL1ELNode(0), e.L1Vault, cnt))
-	t.Logf("generate %d L2 blocks", cnt)
+	t = e.T
+	e.mineL1Blocks(cnt)
+	t.Logf("generate %d L1 blocks", cnt)
 }
 
 func (e *TestEnv) GenCommitDelayBlocks(t *hivesim.T) {
-	t, ctx := e.T, e.Context
+	t = e.T
 	cnt := e.TaikoConf.CommitConfirmations.Uint64()
 	if cnt == 0 {
 		return
 	}
+	e.mineL1Blocks(cnt)
+	t.Logf("generate %d L1 blocks", cnt)
+}
+
+// mineL1Blocks creates cnt new blocks on the first L1 node, see L1Controller.Mine.
+func (e *TestEnv) mineL1Blocks(cnt uint64) {
+	t := e.T
 	n := e.Net.GetL1ELNode(0)
 	require.NotNil(t, n)
-	require.NoError(t, GenSomeBlocks(ctx, n, e.L1Vault, cnt))
-	t.Logf("generate %d L2 blocks", cnt)
+	c, err := e.NewL1Controller(n)
+	require.NoError(t, err)
+	defer c.Close()
+	require.NoError(t, c.Mine(e.Context, cnt))
 }
 
 func (e *TestEnv) GenSomeL2Blocks(t *hivesim.T, cnt uint64) {
@@ -158,9 +162,26 @@ func (e *TestEnv) GenSomeL2Blocks(t *hivesim.T, cnt uint64) {
 	t.Logf("generate %d L2 blocks", cnt)
 }
 
+// ClientCombinations returns the combinations of the clients selected with --client.
+func ClientCombinations(t *hivesim.T) []*ClientsByRole {
+	clientTypes, err := t.Sim.ClientTypes()
+	require.NoError(t, err, "failed to retrieve list of client types: %v", err)
+	return Roles(t, clientTypes).Combinations()
+}
+
+// MatrixTest is a test which runs once for every combination of clients.
+type MatrixTest struct {
+	Name        string
+	Description string
+	Run         func(t *hivesim.T, clients *ClientsByRole)
+}
+
 type RunTestsParams struct {
-	Devnet      *Devnet
-	Tests       []*hivesim.TestSpec
+	Devnet *Devnet
+	Tests  []*hivesim.TestSpec
+	// MatrixTests run once for every combination of clients, with the combination
+	// appended to their names.
+	MatrixTests []*MatrixTest
 	Concurrency int64
 }
 
@@ -169,7 +190,11 @@ func RunTests(t *hivesim.T, ctx context.Context, params *RunTestsParams) {
 	var done int
 	doneCh := make(chan struct{})
 
-	for _, test := range params.Tests {
+	tests := append([]*hivesim.TestSpec{}, params.Tests...)
+	if len(params.MatrixTests) > 0 {
+		tests = append(tests, matrixSpecs(params.MatrixTests, ClientCombinations(t))...)
+	}
+	for _, test := range tests {
 		go func(test *hivesim.TestSpec) {
 			require.NoError(t, s.Acquire(ctx, 1))
 			defer s.Release(1)
@@ -178,7 +203,7 @@ func RunTests(t *hivesim.T, ctx context.Context, params *RunTestsParams) {
 		}(test)
 	}
 
-	for done < len(params.Tests) {
+	for done < len(tests) {
 		select {
 		case <-doneCh:
 			done++
@@ -187,3 +212,19 @@ func RunTests(t *hivesim.T, ctx context.Context, params *RunTestsParams) {
 		}
 	}
 }
+
+// matrixSpecs returns the specs of the tests for every combination of clients.
+func matrixSpecs(tests []*MatrixTest, combinations []*ClientsByRole) []*hivesim.TestSpec {
+	var specs []*hivesim.TestSpec
+	for _, clients := range combinations {
+		for _, test := range tests {
+			clients, test := clients, test
+			specs = append(specs, &hivesim.TestSpec{
+				Name:        fmt.Sprintf("%s (%s)", test.Name, clients),
+				Description: test.Description,
+				Run:         func(t *hivesim.T) { test.Run(t, clients) },
+			})
+		}
+	}
+	return specs
+}
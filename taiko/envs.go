@@ -17,11 +17,24 @@ package taiko
 // taiko environment variables constants
 const (
 	// hive common
-	envNetworkID    = "HIVE_NETWORK_ID"
-	envBootNode     = "HIVE_BOOTNODE"
-	envCliquePeriod = "HIVE_CLIQUE_PERIOD"
-	envNodeType     = "HIVE_NODETYPE"
-	envLogLevel     = "HIVE_LOGLEVEL"
+	envNetworkID          = "HIVE_NETWORK_ID"
+	envChainID            = "HIVE_CHAIN_ID"
+	envBootNode           = "HIVE_BOOTNODE"
+	envCliquePeriod       = "HIVE_CLIQUE_PERIOD"
+	envCliquePrivateKey   = "HIVE_CLIQUE_PRIVATEKEY"
+	envMiner              = "HIVE_MINER"
+	envNodeType           = "HIVE_NODETYPE"
+	envLogLevel           = "HIVE_LOGLEVEL"
+	envForkHomestead      = "HIVE_FORK_HOMESTEAD"
+	envForkTangerine      = "HIVE_FORK_TANGERINE"
+	envForkSpurious       = "HIVE_FORK_SPURIOUS"
+	envForkByzantium      = "HIVE_FORK_BYZANTIUM"
+	envForkConstantinople = "HIVE_FORK_CONSTANTINOPLE"
+	envForkPetersburg     = "HIVE_FORK_PETERSBURG"
+	envForkIstanbul       = "HIVE_FORK_ISTANBUL"
+	envForkMuirGlacier    = "HIVE_FORK_MUIR_GLACIER"
+	envForkBerlin         = "HIVE_FORK_BERLIN"
+	envForkLondon         = "HIVE_FORK_LONDON"
 
 	// taiko common
 	envTaikoRole            = "HIVE_TAIKO_ROLE"
@@ -46,7 +59,16 @@ const (
 	envTaikoProduceInvalidBlocksInterval = "HIVE_TAIKO_PRODUCE_INVALID_BLOCKS_INTERVAL"
 
 	// prover
-	envTaikoProverPrivateKey = "HIVE_TAIKO_PROVER_PRIVATE_KEY"
+	envTaikoProverPrivateKey            = "HIVE_TAIKO_PROVER_PRIVATE_KEY"
+	envTaikoProverRandomDummyProofDelay = "HIVE_TAIKO_PROVER_RANDOM_DUMMY_PROOF_DELAY"
+
+	// relayer
+	envTaikoL1BridgeAddress      = "HIVE_TAIKO_L1_BRIDGE_ADDRESS"
+	envTaikoL2BridgeAddress      = "HIVE_TAIKO_L2_BRIDGE_ADDRESS"
+	envTaikoL1VaultAddress       = "HIVE_TAIKO_L1_VAULT_ADDRESS"
+	envTaikoL2VaultAddress       = "HIVE_TAIKO_L2_VAULT_ADDRESS"
+	envTaikoRelayerPrivateKey    = "HIVE_TAIKO_RELAYER_PRIVATE_KEY"
+	envTaikoRelayerConfirmations = "HIVE_TAIKO_RELAYER_CONFIRMATIONS"
 
 	// deployer
 	envTaikoL1DeployerAddress  = "HIVE_TAIKO_L1_DEPLOYER_ADDRESS"
@@ -54,4 +76,5 @@ const (
 	envTaikoMainnetUrl         = "HIVE_TAIKO_MAINNET_URL"
 	envTaikoPrivateKey         = "HIVE_TAIKO_PRIVATE_KEY"
 	envTaikoL2ChainID          = "HIVE_TAIKO_L2_CHAIN_ID"
+	envTaikoProtocolParams     = "HIVE_TAIKO_PROTOCOL_PARAMS"
 )
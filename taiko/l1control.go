@@ -0,0 +1,234 @@
+package taiko
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ErrNoDevMode is returned by the L1Controller for operations which need the evm_ RPC
+// methods of a dev-mode L1 node.
+var ErrNoDevMode = errors.New("L1 node has no dev mode")
+
+// fillTxDataSize is the calldata size of the transactions which fill L1 blocks. The
+// data is nonzero, so a transaction uses about 1M gas and stays below the 128KB size
+// limit of the geth transaction pool.
+const fillTxDataSize = 64 * 1024
+
+// L1Controller produces L1 blocks on demand.
+//
+// The taiko-l1 image runs clique with period 0: a block is sealed as soon as there is a
+// transaction to include, with the timestamp max(parent+1, now). The controller pushes
+// blocks on this chain by sending transactions from the vault, and fills them by
+// pausing the miner while the transactions queue up. Empty blocks and arbitrary
+// timestamps can't be produced by clique, and AdvanceTime waits for the wall clock.
+//
+// The taiko-l1-dev client runs anvil, which has the evm_ RPC methods of hardhat and
+// anvil, and is used by devnets with DevnetSpec.L1DevMode. In dev mode, blocks are
+// mined with evm_mine, so they can be empty, and timestamps are set with
+// evm_setNextBlockTimestamp. Time-dependent protocol windows, like the proof time cap
+// and the commit confirmations, are then tested without waiting.
+type L1Controller struct {
+	L1    *ELNode
+	cli   *ethclient.Client
+	vault *Vault
+	dev   bool
+}
+
+// NewL1Controller returns a controller of the given L1 node. It detects whether the
+// node runs in dev mode.
+func (e *TestEnv) NewL1Controller(l1 *ELNode) (*L1Controller, error) {
+	cli, err := l1.EthClient()
+	if err != nil {
+		return nil, err
+	}
+	c := &L1Controller{L1: l1, cli: cli, vault: e.L1Vault}
+	// evm_increaseTime by zero has no effect, and fails on nodes without dev mode.
+	c.dev = l1.RPC().CallContext(e.Context, nil, "evm_increaseTime", hexutil.Uint64(0)) == nil
+	return c, nil
+}
+
+// DevMode reports whether the L1 node has the evm_ RPC methods.
+func (c *L1Controller) DevMode() bool {
+	return c.dev
+}
+
+func (c *L1Controller) Close() {
+	c.cli.Close()
+}
+
+// Head returns the latest L1 header.
+func (c *L1Controller) Head(ctx context.Context) (*types.Header, error) {
+	return c.cli.HeaderByNumber(ctx, nil)
+}
+
+// Mine creates at least n new L1 blocks. In dev mode the blocks are empty, otherwise
+// each of them contains a transaction of the vault.
+func (c *L1Controller) Mine(ctx context.Context, n uint64) error {
+	if c.dev {
+		return c.MineEmpty(ctx, n)
+	}
+	curr, err := c.cli.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	for end := curr + n; curr < end; {
+		tx, err := c.sendFunding(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := WaitReceiptOK(ctx, c.cli, tx.Hash()); err != nil {
+			return err
+		}
+		if curr, err = c.cli.BlockNumber(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MineEmpty creates n new L1 blocks without transactions of the vault. Pending
+// transactions of other accounts are included.
+func (c *L1Controller) MineEmpty(ctx context.Context, n uint64) error {
+	if !c.dev {
+		return fmt.Errorf("can't mine empty blocks: %w", ErrNoDevMode)
+	}
+	for i := uint64(0); i < n; i++ {
+		if err := c.call(ctx, "evm_mine"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MineFull creates n new L1 blocks which are filled up to their gas limit with
+// calldata-heavy transactions of the vault.
+func (c *L1Controller) MineFull(ctx context.Context, n uint64) error {
+	for i := uint64(0); i < n; i++ {
+		if err := c.mineFull(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *L1Controller) mineFull(ctx context.Context) error {
+	head, err := c.Head(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.pause(ctx, true); err != nil {
+		return err
+	}
+	paused := true
+	defer func() {
+		if paused {
+			c.pause(ctx, false)
+		}
+	}()
+
+	data := make([]byte, fillTxDataSize)
+	for i := range data {
+		data[i] = 0xff
+	}
+	var txs []*types.Transaction
+	for gas := uint64(0); gas <= head.GasLimit; {
+		tx, err := c.sendFunding(ctx, data)
+		if err != nil {
+			return err
+		}
+		txs = append(txs, tx)
+		gas += tx.Gas()
+	}
+	if c.dev {
+		if err := c.call(ctx, "evm_mine"); err != nil {
+			return err
+		}
+	}
+	paused = false
+	if err := c.pause(ctx, false); err != nil {
+		return err
+	}
+	// The transactions don't fit into one block. The block including the first of them
+	// is full, the others stay pending for the next blocks.
+	_, err = WaitReceiptOK(ctx, c.cli, txs[0].Hash())
+	return err
+}
+
+// SetNextTimestamp sets the timestamp of the next L1 block.
+func (c *L1Controller) SetNextTimestamp(ctx context.Context, ts uint64) error {
+	if !c.dev {
+		return fmt.Errorf("can't set block timestamp: %w", ErrNoDevMode)
+	}
+	return c.call(ctx, "evm_setNextBlockTimestamp", hexutil.Uint64(ts))
+}
+
+// AdvanceTime creates a new L1 block with a timestamp at least d after the current
+// head, and returns its header. Without dev mode it waits until the wall clock has
+// passed the timestamp.
+func (c *L1Controller) AdvanceTime(ctx context.Context, d time.Duration) (*types.Header, error) {
+	head, err := c.Head(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ts := head.Time + uint64((d+time.Second-1)/time.Second)
+	if c.dev {
+		if err := c.SetNextTimestamp(ctx, ts); err != nil {
+			return nil, err
+		}
+		if err := c.call(ctx, "evm_mine"); err != nil {
+			return nil, err
+		}
+		return c.Head(ctx)
+	}
+	if wait := time.Until(time.Unix(int64(ts), 0)); wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	tx, err := c.sendFunding(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := WaitReceiptOK(ctx, c.cli, tx.Hash())
+	if err != nil {
+		return nil, err
+	}
+	return c.cli.HeaderByHash(ctx, receipt.BlockHash)
+}
+
+// sendFunding sends a transaction of the vault to a new account.
+func (c *L1Controller) sendFunding(ctx context.Context, data []byte) (*types.Transaction, error) {
+	tx, err := c.vault.makeFundingTx(ctx, c.cli, c.vault.GenerateKey(), big.NewInt(params.GWei), data)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.vault.sendFundingTx(ctx, c.cli, tx); err != nil {
+		return nil, fmt.Errorf("unable to send funding transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// pause stops or resumes the automatic production of L1 blocks.
+func (c *L1Controller) pause(ctx context.Context, paused bool) error {
+	if c.dev {
+		return c.call(ctx, "evm_setAutomine", !paused)
+	}
+	return setMining(ctx, c.L1, !paused)
+}
+
+func (c *L1Controller) call(ctx context.Context, method string, args ...interface{}) error {
+	if err := c.L1.RPC().CallContext(ctx, nil, method, args...); err != nil {
+		return fmt.Errorf("%s failed on %s: %w", method, c.L1.Container, err)
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+package taiko
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/hive/hivesim"
+	"github.com/stretchr/testify/require"
+)
+
+// forkFunding is the balance of the account sending the transactions of the fork chain.
+var forkFunding = new(big.Int).Mul(big.NewInt(10), big.NewInt(params.Ether))
+
+// L1Reorg forces a reorg of the devnet L1 chain.
+//
+// It starts a second L1 node, the fork node, which is peered with the devnet L1 node
+// over an L2Network, and follows it until Partition is called. While the nodes are partitioned, both of them create blocks
+// independently. Reconnect makes the fork chain longer than the devnet L1 chain and
+// connects the nodes again, so the devnet L1 node switches to the fork chain and drops
+// all blocks it created after the partition. Transactions of the dropped blocks, e.g.
+// proposeBlock transactions, go back into the transaction pool and are included again
+// in a different order.
+type L1Reorg struct {
+	t       *hivesim.T
+	L1      *ELNode
+	Fork    *ELNode
+	network *L2Network
+
+	// key of the account which sends the transactions of the fork chain
+	key     *ecdsa.PrivateKey
+	chainID *big.Int
+}
+
+// NewL1Reorg starts the fork node and waits until it has synced with the first L1
+// node of the devnet.
+func (e *TestEnv) NewL1Reorg() *L1Reorg {
+	t, ctx := e.T, e.Context
+	l1 := e.Net.GetL1ELNode(0)
+	require.NotNil(t, l1)
+	cli, err := l1.EthClient()
+	require.NoError(t, err)
+
+	// The fork account is funded before the fork node syncs, so it exists on both chains.
+	addr := e.L1Vault.CreateAccount(ctx, cli, forkFunding)
+	r := &L1Reorg{
+		t:       t,
+		L1:      l1,
+		Fork:    e.NewL1ELNode(e.Net.GetL2ELNode(0)),
+		network: e.NewL2Network("taiko-l1-reorg"),
+		key:     e.L1Vault.FindKey(addr),
+		chainID: e.Conf.L1.ChainID,
+	}
+	// Both nodes seal with the same clique key. The fork node must not seal blocks
+	// while the nodes are connected, otherwise they would create competing blocks
+	// all the time.
+	require.NoError(t, setMining(ctx, r.Fork, false))
+	require.NoError(t, r.network.Join(ctx, r.L1))
+	require.NoError(t, r.network.Join(ctx, r.Fork))
+	require.NoError(t, r.waitSynced(ctx))
+	return r
+}
+
+// Partition disconnects the fork node from the network of the devnet L1 node.
+func (r *L1Reorg) Partition(ctx context.Context) error {
+	if err := r.network.Partition(ctx, r.Fork); err != nil {
+		return err
+	}
+	return setMining(ctx, r.Fork, true)
+}
+
+// Reconnect extends the fork chain by extraBlocks blocks beyond the head of the
+// devnet L1 node, connects the nodes and waits until the devnet L1 node has switched
+// to the fork chain. The devnet L1 node does not create blocks until the reorg is done.
+func (r *L1Reorg) Reconnect(ctx context.Context, extraBlocks uint64) error {
+	if err := setMining(ctx, r.L1, false); err != nil {
+		return err
+	}
+	defer setMining(ctx, r.L1, true)
+
+	l1Cli, err := r.L1.EthClient()
+	if err != nil {
+		return err
+	}
+	height, err := l1Cli.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.extendFork(ctx, height+extraBlocks); err != nil {
+		return err
+	}
+	if err := setMining(ctx, r.Fork, false); err != nil {
+		return err
+	}
+	if err := r.network.Rejoin(ctx, r.Fork); err != nil {
+		return err
+	}
+	return r.waitSynced(ctx)
+}
+
+// Stop removes the network and stops the fork node.
+func (r *L1Reorg) Stop() {
+	t := r.t
+	r.network.Remove()
+	t.Sim.StopClient(t.SuiteID, t.TestID, r.Fork.Container)
+}
+
+// extendFork adds blocks to the fork chain until it reaches the given height.
+func (r *L1Reorg) extendFork(ctx context.Context, height uint64) error {
+	cli, err := r.Fork.EthClient()
+	if err != nil {
+		return err
+	}
+	addr := crypto.PubkeyToAddress(r.key.PublicKey)
+	signer := types.LatestSignerForChainID(r.chainID)
+	for {
+		curr, err := cli.BlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+		if curr >= height {
+			return nil
+		}
+		nonce, err := cli.PendingNonceAt(ctx, addr)
+		if err != nil {
+			return err
+		}
+		gasPrice, err := cli.SuggestGasPrice(ctx)
+		if err != nil {
+			return err
+		}
+		tx, err := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			Gas:      params.TxGas,
+			GasPrice: gasPrice,
+			To:       &addr,
+			Value:    common.Big0,
+		}), signer, r.key)
+		if err != nil {
+			return err
+		}
+		if err := cli.SendTransaction(ctx, tx); err != nil {
+			return fmt.Errorf("can't send fork transaction: %w", err)
+		}
+		if _, err := WaitReceiptOK(ctx, cli, tx.Hash()); err != nil {
+			return err
+		}
+	}
+}
+
+// waitSynced waits until both nodes have the same head block.
+func (r *L1Reorg) waitSynced(ctx context.Context) error {
+	l1Cli, err := r.L1.EthClient()
+	if err != nil {
+		return err
+	}
+	forkCli, err := r.Fork.EthClient()
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		l1Head, err := l1Cli.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return err
+		}
+		forkHead, err := forkCli.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if l1Head.Hash() == forkHead.Hash() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("L1 head %d (%v) not synced with fork head %d (%v): %w",
+				l1Head.Number, l1Head.Hash(), forkHead.Number, forkHead.Hash(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func setMining(ctx context.Context, n *ELNode, on bool) error {
+	method := "miner_stop"
+	if on {
+		method = "miner_start"
+	}
+	if err := n.RPC().CallContext(ctx, nil, method); err != nil {
+		return fmt.Errorf("%s failed on %s: %w", method, n.Container, err)
+	}
+	return nil
+}
+
+func waitPeerCount(ctx context.Context, n *ELNode, want uint64) error {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		var count hexutil.Uint64
+		if err := n.RPC().CallContext(ctx, &count, "net_peerCount"); err != nil {
+			return err
+		}
+		if uint64(count) == want {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s has %d peers, want %d: %w", n.Container, count, want, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// L1Origin is the L1 block an L2 block was derived from, as recorded by taiko-geth.
+type L1Origin struct {
+	BlockID       *hexutil.Big `json:"blockID"`
+	L2BlockHash   common.Hash  `json:"l2BlockHash"`
+	L1BlockHeight *hexutil.Big `json:"l1BlockHeight"`
+	L1BlockHash   common.Hash  `json:"l1BlockHash"`
+}
+
+// L1OriginByID returns the L1 origin of the block with the given TaikoL1 block ID.
+func L1OriginByID(ctx context.Context, l2 *ELNode, id *big.Int) (*L1Origin, error) {
+	var origin *L1Origin
+	if err := l2.RPC().CallContext(ctx, &origin, "taiko_l1OriginByID", (*hexutil.Big)(id)); err != nil {
+		return nil, err
+	}
+	return origin, nil
+}
+
+// CheckL1Origins verifies that the L1 origins of all L2 blocks refer to the canonical
+// blocks of both chains.
+func CheckL1Origins(ctx context.Context, l1, l2 *ELNode) error {
+	l1Cli, err := l1.EthClient()
+	if err != nil {
+		return err
+	}
+	l2Cli, err := l2.EthClient()
+	if err != nil {
+		return err
+	}
+	head, err := l2Cli.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	for i := uint64(1); i <= head; i++ {
+		if err := checkL1Origin(ctx, l1Cli, l2Cli, l2, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkL1Origin(ctx context.Context, l1Cli, l2Cli *ethclient.Client, l2 *ELNode, num uint64) error {
+	id := new(big.Int).SetUint64(num)
+	origin, err := L1OriginByID(ctx, l2, id)
+	if err != nil {
+		return fmt.Errorf("can't get L1 origin of L2 block %d: %w", num, err)
+	}
+	l2Header, err := l2Cli.HeaderByNumber(ctx, id)
+	if err != nil {
+		return err
+	}
+	if origin.L2BlockHash != l2Header.Hash() {
+		return fmt.Errorf("L1 origin of L2 block %d has L2 hash %v, canonical block is %v",
+			num, origin.L2BlockHash, l2Header.Hash())
+	}
+	l1Header, err := l1Cli.HeaderByNumber(ctx, origin.L1BlockHeight.ToInt())
+	if err != nil {
+		return fmt.Errorf("L1 origin block %d of L2 block %d: %w", origin.L1BlockHeight.ToInt(), num, err)
+	}
+	if origin.L1BlockHash != l1Header.Hash() {
+		return fmt.Errorf("L1 origin of L2 block %d refers to L1 block %d (%v), canonical block is %v",
+			num, origin.L1BlockHeight.ToInt(), origin.L1BlockHash, l1Header.Hash())
+	}
+	return nil
+}
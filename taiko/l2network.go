@@ -0,0 +1,132 @@
+package taiko
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/hive/hivesim"
+	"github.com/stretchr/testify/require"
+)
+
+// L2Network is a docker network which carries the p2p traffic of some L2 nodes.
+//
+// The nodes of the devnet find each other through their enode URLs on the default
+// network, which also carries the RPC traffic of the simulator, so they can't be
+// separated. The nodes of an L2Network should be started without boot nodes: they
+// are peered over the L2Network only. Partition disconnects a node from the network,
+// while its RPC and engine APIs, and its driver, keep working. Rejoin connects it
+// again. L1Reorg partitions L1 nodes the same way.
+type L2Network struct {
+	t    *hivesim.T
+	Name string
+
+	mu    sync.Mutex
+	nodes []*ELNode
+}
+
+// NewL2Network creates an empty L2Network. The name is unique within the test.
+func (e *TestEnv) NewL2Network(name string) *L2Network {
+	t := e.T
+	name = fmt.Sprintf("%s-%d", name, t.TestID)
+	require.NoError(t, t.Sim.CreateNetwork(t.SuiteID, name), "can't create network %s", name)
+	return &L2Network{t: t, Name: name}
+}
+
+// Join connects n to the network and peers it with all nodes of the network.
+func (l *L2Network) Join(ctx context.Context, n *ELNode) error {
+	t := l.t
+	if err := t.Sim.ConnectContainer(t.SuiteID, l.Name, n.Container); err != nil {
+		return fmt.Errorf("can't connect %s to network %s: %w", n.Container, l.Name, err)
+	}
+	l.mu.Lock()
+	peers := append([]*ELNode(nil), l.nodes...)
+	l.nodes = append(l.nodes, n)
+	l.mu.Unlock()
+	return l.addPeers(ctx, n, peers)
+}
+
+// Partition disconnects n from the other nodes of the network. It stays a member of
+// the network, so Rejoin connects it again.
+func (l *L2Network) Partition(ctx context.Context, n *ELNode) error {
+	t := l.t
+	if !l.member(n) {
+		return fmt.Errorf("%s is not a node of network %s", n.Container, l.Name)
+	}
+	// Peers added with admin_addPeer are redialed, so they are removed before the node
+	// is disconnected from the network.
+	var peers []*p2p.PeerInfo
+	if err := n.RPC().CallContext(ctx, &peers, "admin_peers"); err != nil {
+		return err
+	}
+	for _, p := range peers {
+		if err := n.RPC().CallContext(ctx, nil, "admin_removePeer", p.Enode); err != nil {
+			return fmt.Errorf("can't remove peer %s of %s: %w", p.Enode, n.Container, err)
+		}
+	}
+	if err := t.Sim.DisconnectContainer(t.SuiteID, l.Name, n.Container); err != nil {
+		return fmt.Errorf("can't disconnect %s from network %s: %w", n.Container, l.Name, err)
+	}
+	// The peers of the other side drop n once the connection times out.
+	return waitPeerCount(ctx, n, 0)
+}
+
+// Rejoin connects a node which was partitioned by Partition to the network again.
+func (l *L2Network) Rejoin(ctx context.Context, n *ELNode) error {
+	t := l.t
+	if !l.member(n) {
+		return fmt.Errorf("%s is not a node of network %s", n.Container, l.Name)
+	}
+	if err := t.Sim.ConnectContainer(t.SuiteID, l.Name, n.Container); err != nil {
+		return fmt.Errorf("can't connect %s to network %s: %w", n.Container, l.Name, err)
+	}
+	var peers []*ELNode
+	l.mu.Lock()
+	for _, m := range l.nodes {
+		if m != n {
+			peers = append(peers, m)
+		}
+	}
+	l.mu.Unlock()
+	return l.addPeers(ctx, n, peers)
+}
+
+// Remove disconnects all nodes and removes the network.
+func (l *L2Network) Remove() {
+	t := l.t
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, n := range l.nodes {
+		t.Sim.DisconnectContainer(t.SuiteID, l.Name, n.Container)
+	}
+	l.nodes = nil
+	if err := t.Sim.RemoveNetwork(t.SuiteID, l.Name); err != nil {
+		t.Logf("can't remove network %s: %v", l.Name, err)
+	}
+}
+
+func (l *L2Network) member(n *ELNode) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, m := range l.nodes {
+		if m == n {
+			return true
+		}
+	}
+	return false
+}
+
+// addPeers makes n dial the given nodes over the network.
+func (l *L2Network) addPeers(ctx context.Context, n *ELNode, peers []*ELNode) error {
+	for _, p := range peers {
+		enode, err := p.EnodeURLNetwork(l.Name)
+		if err != nil {
+			return err
+		}
+		if err := n.RPC().CallContext(ctx, nil, "admin_addPeer", enode); err != nil {
+			return fmt.Errorf("can't add peer %s to %s: %w", p.Container, n.Container, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,530 @@
+package taiko
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/taikoxyz/taiko-client/bindings"
+)
+
+// BlockTracker records the lifecycle of L2 blocks: when their transactions were
+// submitted, when TaikoL1 emitted BlockProposed, when the driver included the block
+// in the L2 chain, and when TaikoL1 emitted BlockProven and BlockVerified. Times are
+// taken when the events are observed, so they have a higher resolution than block
+// timestamps.
+//
+// L2 blocks are tracked by hash, TaikoL1 events by block ID. They are correlated by the
+// L1 origin which the driver records for every block it inserts.
+type BlockTracker struct {
+	l2 *ELNode
+
+	mu sync.Mutex
+	// L2 blocks by hash, and the hash of the block of every transaction in them
+	blocks   map[common.Hash]*BlockTiming
+	txBlocks map[common.Hash]common.Hash
+	// TaikoL1 events by block ID
+	events map[uint64]*l1Events
+	// submission times of tracked transactions
+	submitted map[common.Hash]time.Time
+	err       error
+
+	// updated receives a value whenever something was recorded.
+	updated chan struct{}
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// l1Events are the TaikoL1 events of a block ID.
+type l1Events struct {
+	proposed, proven, verified time.Time
+	// L1 block containing the proposal
+	proposedIn common.Hash
+	// L2 block hashes of the first BlockProven event and of the BlockVerified event
+	provenHash, verifiedHash common.Hash
+	// L1 origin recorded by the driver, nil until the block was included
+	origin *L1Origin
+}
+
+// BlockTiming is the lifecycle of an L2 block. Times of events which were not observed
+// are zero.
+type BlockTiming struct {
+	Number uint64
+	// Hash of the block in the L2 chain.
+	Hash common.Hash
+	// ID is the TaikoL1 block ID of the block, zero until its L1 origin is known.
+	ID uint64
+	// Submitted is the earliest submission of a tracked transaction in the block.
+	Submitted time.Time
+	Proposed  time.Time
+	Included  time.Time
+	Proven    time.Time
+	Verified  time.Time
+	// L2 block hashes of the first BlockProven event and of the BlockVerified event.
+	ProvenHash   common.Hash
+	VerifiedHash common.Hash
+	// L1 blocks of the proposal, and recorded in the L1 origin of the block.
+	ProposedIn  common.Hash
+	L1BlockHash common.Hash
+}
+
+// StartBlockTracker starts tracking the L2 blocks of l2 which are created from now on,
+// and the TaikoL1 events on l1.
+func StartBlockTracker(ctx context.Context, l1, l2 *ELNode) (*BlockTracker, error) {
+	taikoL1, err := l1.TaikoL1Client()
+	if err != nil {
+		return nil, err
+	}
+	l2Cli, err := l2.EthClient()
+	if err != nil {
+		return nil, err
+	}
+	start, err := l2Cli.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	tr := &BlockTracker{
+		l2:        l2,
+		blocks:    make(map[common.Hash]*BlockTiming),
+		txBlocks:  make(map[common.Hash]common.Hash),
+		events:    make(map[uint64]*l1Events),
+		submitted: make(map[common.Hash]time.Time),
+		updated:   make(chan struct{}, 1),
+		cancel:    cancel,
+	}
+	heads := make(chan *types.Header, 16)
+	headSub, err := l2Cli.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("%s: can't subscribe to new heads: %w", l2.Container, err)
+	}
+	opts := &bind.WatchOpts{Context: ctx}
+	proposed := make(chan *bindings.TaikoL1ClientBlockProposed, 16)
+	proven := make(chan *bindings.TaikoL1ClientBlockProven, 16)
+	verified := make(chan *bindings.TaikoL1ClientBlockVerified, 16)
+	var subs []interface{ Unsubscribe() }
+	unsubscribe := func() {
+		for _, sub := range subs {
+			sub.Unsubscribe()
+		}
+	}
+	subs = append(subs, headSub)
+	proposedSub, err := taikoL1.WatchBlockProposed(opts, proposed, nil)
+	if err != nil {
+		unsubscribe()
+		cancel()
+		return nil, fmt.Errorf("%s: can't subscribe to proposed blocks: %w", l1.Container, err)
+	}
+	subs = append(subs, proposedSub)
+	provenSub, err := taikoL1.WatchBlockProven(opts, proven, nil)
+	if err != nil {
+		unsubscribe()
+		cancel()
+		return nil, fmt.Errorf("%s: can't subscribe to proven blocks: %w", l1.Container, err)
+	}
+	subs = append(subs, provenSub)
+	verifiedSub, err := taikoL1.WatchBlockVerified(opts, verified, nil)
+	if err != nil {
+		unsubscribe()
+		cancel()
+		return nil, fmt.Errorf("%s: can't subscribe to verified blocks: %w", l1.Container, err)
+	}
+	subs = append(subs, verifiedSub)
+
+	tr.wg.Add(2)
+	go func() {
+		defer tr.wg.Done()
+		for {
+			select {
+			case h := <-heads:
+				if err := tr.recordChain(ctx, l2Cli, h.Hash(), start); err != nil {
+					if ctx.Err() == nil {
+						tr.fail(err)
+					}
+					return
+				}
+				if err := tr.correlate(ctx); err != nil {
+					tr.fail(err)
+					return
+				}
+			case err := <-headSub.Err():
+				tr.fail(err)
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		defer tr.wg.Done()
+		defer unsubscribe()
+		for {
+			select {
+			case e := <-proposed:
+				tr.record(e.Id, func(ev *l1Events, now time.Time) {
+					if ev.proposed.IsZero() {
+						ev.proposed, ev.proposedIn = now, e.Raw.BlockHash
+					}
+				})
+				// The block may have been included before the event was observed.
+				if err := tr.correlate(ctx); err != nil {
+					tr.fail(err)
+					return
+				}
+			case e := <-proven:
+				tr.record(e.Id, func(ev *l1Events, now time.Time) {
+					if ev.proven.IsZero() {
+						ev.proven, ev.provenHash = now, e.BlockHash
+					}
+				})
+			case e := <-verified:
+				tr.record(e.Id, func(ev *l1Events, now time.Time) {
+					if ev.verified.IsZero() {
+						ev.verified, ev.verifiedHash = now, e.BlockHash
+					}
+				})
+			case err := <-proposedSub.Err():
+				tr.fail(err)
+				return
+			case err := <-provenSub.Err():
+				tr.fail(err)
+				return
+			case err := <-verifiedSub.Err():
+				tr.fail(err)
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return tr, nil
+}
+
+// Stop stops tracking and returns the first error which occurred while tracking.
+func (tr *BlockTracker) Stop() error {
+	tr.cancel()
+	tr.wg.Wait()
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return tr.err
+}
+
+// TrackTx records the submission time of an L2 transaction.
+func (tr *BlockTracker) TrackTx(hash common.Hash, submitted time.Time) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.submitted[hash] = submitted
+	if block, ok := tr.txBlocks[hash]; ok {
+		tr.updateSubmitted(tr.blocks[block], submitted)
+	}
+}
+
+func (tr *BlockTracker) updateSubmitted(b *BlockTiming, submitted time.Time) {
+	if b.Submitted.IsZero() || submitted.Before(b.Submitted) {
+		b.Submitted = submitted
+	}
+}
+
+// recordChain records the blocks of the chain ending in the given head which are not
+// tracked yet, down to the first tracked block or the block the tracker started at.
+// After a reorg, this records the replacement blocks of heights seen before.
+func (tr *BlockTracker) recordChain(ctx context.Context, cli *ethclient.Client, head common.Hash, start uint64) error {
+	var blocks []*types.Block
+	for hash := head; ; {
+		tr.mu.Lock()
+		_, ok := tr.blocks[hash]
+		tr.mu.Unlock()
+		if ok {
+			break
+		}
+		block, err := cli.BlockByHash(ctx, hash)
+		if err != nil {
+			return fmt.Errorf("%s: can't get L2 block %v: %w", tr.l2.Container, hash, err)
+		}
+		if block.NumberU64() <= start {
+			break
+		}
+		blocks = append(blocks, block)
+		hash = block.ParentHash()
+	}
+	for i := len(blocks) - 1; i >= 0; i-- {
+		tr.recordBlock(blocks[i])
+	}
+	return nil
+}
+
+func (tr *BlockTracker) recordBlock(block *types.Block) {
+	now := time.Now()
+	tr.mu.Lock()
+	b := &BlockTiming{Number: block.NumberU64(), Hash: block.Hash(), Included: now}
+	tr.blocks[b.Hash] = b
+	for _, tx := range block.Transactions() {
+		tr.txBlocks[tx.Hash()] = b.Hash
+		if at, ok := tr.submitted[tx.Hash()]; ok {
+			tr.updateSubmitted(b, at)
+		}
+	}
+	tr.mu.Unlock()
+	tr.notify()
+}
+
+// record applies an event of the TaikoL1 block with the given id.
+func (tr *BlockTracker) record(id *big.Int, apply func(*l1Events, time.Time)) {
+	now := time.Now()
+	tr.mu.Lock()
+	ev, ok := tr.events[id.Uint64()]
+	if !ok {
+		ev = new(l1Events)
+		tr.events[id.Uint64()] = ev
+	}
+	apply(ev, now)
+	tr.mu.Unlock()
+	tr.notify()
+}
+
+// correlate looks up the L1 origins of the proposed blocks which are not linked to an
+// L2 block yet, and links them to the tracked blocks.
+func (tr *BlockTracker) correlate(ctx context.Context) error {
+	tr.mu.Lock()
+	var ids []uint64
+	for id, ev := range tr.events {
+		if ev.origin == nil && !ev.proposed.IsZero() {
+			ids = append(ids, id)
+		}
+	}
+	tr.mu.Unlock()
+
+	linked := false
+	for _, id := range ids {
+		origin, err := L1OriginByID(ctx, tr.l2, new(big.Int).SetUint64(id))
+		if err != nil && err.Error() != ethereum.NotFound.Error() {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("%s: can't get L1 origin of block %d: %w", tr.l2.Container, id, err)
+		}
+		if origin == nil {
+			continue
+		}
+		tr.mu.Lock()
+		// Blocks included before the tracker started are not linked.
+		if b, ok := tr.blocks[origin.L2BlockHash]; ok {
+			tr.events[id].origin = origin
+			b.ID = id
+			linked = true
+		}
+		tr.mu.Unlock()
+	}
+	if linked {
+		tr.notify()
+	}
+	return nil
+}
+
+// timing returns the lifecycle of the L2 block, including the TaikoL1 events of its
+// block ID. It must be called with the lock held.
+func (tr *BlockTracker) timing(b *BlockTiming) BlockTiming {
+	t := *b
+	if ev, ok := tr.events[b.ID]; ok && b.ID != 0 && ev.origin != nil {
+		t.Proposed, t.ProposedIn = ev.proposed, ev.proposedIn
+		t.Proven, t.ProvenHash = ev.proven, ev.provenHash
+		t.Verified, t.VerifiedHash = ev.verified, ev.verifiedHash
+		t.L1BlockHash = ev.origin.L1BlockHash
+	}
+	return t
+}
+
+func (tr *BlockTracker) fail(err error) {
+	tr.mu.Lock()
+	if tr.err == nil {
+		tr.err = err
+	}
+	tr.mu.Unlock()
+	tr.notify()
+}
+
+func (tr *BlockTracker) notify() {
+	select {
+	case tr.updated <- struct{}{}:
+	default:
+	}
+}
+
+// wait calls check with the lock held until it returns true, re-evaluating it
+// whenever something was recorded. check returns a description of the state for the
+// error on timeout.
+func (tr *BlockTracker) wait(ctx context.Context, check func() (bool, string)) error {
+	for {
+		tr.mu.Lock()
+		done, state := check()
+		err := tr.err
+		tr.mu.Unlock()
+		if done {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		select {
+		case <-tr.updated:
+		case <-ctx.Done():
+			return fmt.Errorf("%s: %w", state, ctx.Err())
+		}
+	}
+}
+
+// WaitVerified waits until the verification of the canonical L2 block with the given
+// number was observed.
+func (tr *BlockTracker) WaitVerified(ctx context.Context, num uint64) error {
+	cli, err := tr.l2.EthClient()
+	if err != nil {
+		return err
+	}
+	header, err := cli.HeaderByNumber(ctx, new(big.Int).SetUint64(num))
+	if err != nil {
+		return fmt.Errorf("%s: can't get L2 block %d: %w", tr.l2.Container, num, err)
+	}
+	return tr.wait(ctx, func() (bool, string) {
+		if b, ok := tr.blocks[header.Hash()]; ok && !tr.timing(b).Verified.IsZero() {
+			return true, ""
+		}
+		return false, fmt.Sprintf("L2 block %d (%v) not verified, %s", num, header.Hash(), tr.progress())
+	})
+}
+
+// progress describes the highest blocks observed in every stage. It must be called with
+// the lock held.
+func (tr *BlockTracker) progress() string {
+	var proposed, included, proven, verified uint64
+	for _, b := range tr.blocks {
+		t := tr.timing(b)
+		if !t.Proposed.IsZero() && t.Number > proposed {
+			proposed = t.Number
+		}
+		if t.Number > included {
+			included = t.Number
+		}
+		if !t.Proven.IsZero() && t.Number > proven {
+			proven = t.Number
+		}
+		if !t.Verified.IsZero() && t.Number > verified {
+			verified = t.Number
+		}
+	}
+	return fmt.Sprintf("last proposed %d, included %d, proven %d, verified %d", proposed, included, proven, verified)
+}
+
+// Blocks returns the timings of all tracked blocks, ordered by number. Blocks which
+// were replaced by a reorg are included.
+func (tr *BlockTracker) Blocks() []BlockTiming {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	blocks := make([]BlockTiming, 0, len(tr.blocks))
+	for _, b := range tr.blocks {
+		blocks = append(blocks, tr.timing(b))
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		if blocks[i].Number != blocks[j].Number {
+			return blocks[i].Number < blocks[j].Number
+		}
+		return blocks[i].Included.Before(blocks[j].Included)
+	})
+	return blocks
+}
+
+// CheckHashes verifies that the L2 block hashes of the proof and verification events
+// match the blocks of the L2 chain with the same L1 origin, and that the L1 origins
+// refer to the L1 blocks containing the proposals.
+func (tr *BlockTracker) CheckHashes() error {
+	for _, b := range tr.Blocks() {
+		if b.Proposed.IsZero() {
+			continue
+		}
+		if b.L1BlockHash != b.ProposedIn {
+			return fmt.Errorf("L2 block %d (%v) has L1 origin %v, proposed in L1 block %v", b.Number, b.Hash, b.L1BlockHash, b.ProposedIn)
+		}
+		if !b.Proven.IsZero() && b.ProvenHash != b.Hash {
+			return fmt.Errorf("L2 block %d (%v) with id %d proven with hash %v", b.Number, b.Hash, b.ID, b.ProvenHash)
+		}
+		if !b.Verified.IsZero() && b.VerifiedHash != b.Hash {
+			return fmt.Errorf("L2 block %d (%v) with id %d verified with hash %v", b.Number, b.Hash, b.ID, b.VerifiedHash)
+		}
+	}
+	return nil
+}
+
+// LifecycleReport summarizes the durations between the lifecycle stages of the
+// tracked blocks.
+type LifecycleReport struct {
+	Blocks []BlockTiming
+	// from the earliest transaction submission
+	SubmittedToProposed LatencyStats
+	SubmittedToVerified LatencyStats
+	// between the stages of the block
+	ProposedToIncluded LatencyStats
+	IncludedToProven   LatencyStats
+	ProvenToVerified   LatencyStats
+	ProposedToVerified LatencyStats
+}
+
+// Report returns the lifecycle report of the blocks tracked so far.
+func (tr *BlockTracker) Report() *LifecycleReport {
+	r := &LifecycleReport{Blocks: tr.Blocks()}
+	var submittedProposed, submittedVerified, proposedIncluded, includedProven, provenVerified, proposedVerified []time.Duration
+	add := func(list *[]time.Duration, from, to time.Time) {
+		if !from.IsZero() && !to.IsZero() {
+			*list = append(*list, to.Sub(from))
+		}
+	}
+	for _, b := range r.Blocks {
+		add(&submittedProposed, b.Submitted, b.Proposed)
+		add(&submittedVerified, b.Submitted, b.Verified)
+		add(&proposedIncluded, b.Proposed, b.Included)
+		add(&includedProven, b.Included, b.Proven)
+		add(&provenVerified, b.Proven, b.Verified)
+		add(&proposedVerified, b.Proposed, b.Verified)
+	}
+	r.SubmittedToProposed = NewLatencyStats(submittedProposed)
+	r.SubmittedToVerified = NewLatencyStats(submittedVerified)
+	r.ProposedToIncluded = NewLatencyStats(proposedIncluded)
+	r.IncludedToProven = NewLatencyStats(includedProven)
+	r.ProvenToVerified = NewLatencyStats(provenVerified)
+	r.ProposedToVerified = NewLatencyStats(proposedVerified)
+	return r
+}
+
+func (r *LifecycleReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "submitted -> proposed: %v\n", r.SubmittedToProposed)
+	fmt.Fprintf(&b, "submitted -> verified: %v\n", r.SubmittedToVerified)
+	fmt.Fprintf(&b, "proposed -> included:  %v\n", r.ProposedToIncluded)
+	fmt.Fprintf(&b, "included -> proven:    %v\n", r.IncludedToProven)
+	fmt.Fprintf(&b, "proven -> verified:    %v\n", r.ProvenToVerified)
+	fmt.Fprintf(&b, "proposed -> verified:  %v\n", r.ProposedToVerified)
+	for _, t := range r.Blocks {
+		fmt.Fprintf(&b, "block %d %v: %s\n", t.Number, t.Hash, t.stages())
+	}
+	return b.String()
+}
+
+// stages describes the times of the stages relative to the proposal of the block.
+func (t *BlockTiming) stages() string {
+	rel := func(at time.Time) string {
+		if at.IsZero() || t.Proposed.IsZero() {
+			return "-"
+		}
+		return at.Sub(t.Proposed).Round(time.Millisecond).String()
+	}
+	return fmt.Sprintf("submitted %s, included %s, proven %s, verified %s (relative to proposal)",
+		rel(t.Submitted), rel(t.Included), rel(t.Proven), rel(t.Verified))
+}
@@ -0,0 +1,410 @@
+package taiko
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+// LoadKind is a kind of transaction sent by the LoadGenerator.
+type LoadKind int
+
+const (
+	// LoadTransfer sends ether to a new address.
+	LoadTransfer LoadKind = iota
+	// LoadDeploy deploys a contract.
+	LoadDeploy
+	// LoadStorage calls a contract which writes new storage slots.
+	LoadStorage
+	// LoadCalldata sends ether with large calldata.
+	LoadCalldata
+)
+
+func (k LoadKind) String() string {
+	switch k {
+	case LoadTransfer:
+		return "transfer"
+	case LoadDeploy:
+		return "deploy"
+	case LoadStorage:
+		return "storage"
+	case LoadCalldata:
+		return "calldata"
+	default:
+		return fmt.Sprintf("LoadKind(%d)", int(k))
+	}
+}
+
+// storageWriterCode is the init code of a contract which stores 1 in every slot given
+// as a 32 byte word of the calldata.
+var storageWriterCode = common.FromHex("6016600c60003960166000f3" + "60005b803611600a57005b6001813555602001600256")
+
+// LoadSpec configures a LoadGenerator.
+type LoadSpec struct {
+	// Transactions per second, sent to the L2 nodes in turn.
+	TPS      int
+	Duration time.Duration
+	// Number of sending accounts, each of them funded with Funding.
+	Accounts int
+	Funding  *big.Int
+	// Weights of the transaction kinds. Only transfers are sent if empty.
+	Mix map[LoadKind]int
+	// Transaction types, used in turn. Only EIP-1559 transactions are sent if empty.
+	TxTypes []uint8
+	// Calldata bytes of LoadCalldata transactions.
+	CalldataSize int
+	// Slots written by LoadStorage transactions.
+	StorageSlots int
+	// Seed of the random choices.
+	Seed int64
+}
+
+// DefaultLoadSpec sends 10 transfers per second for a minute.
+func DefaultLoadSpec() *LoadSpec {
+	return &LoadSpec{
+		TPS:          10,
+		Duration:     time.Minute,
+		Accounts:     10,
+		Funding:      new(big.Int).Mul(big.NewInt(10), big.NewInt(params.Ether)),
+		TxTypes:      []uint8{types.DynamicFeeTxType},
+		CalldataSize: 16 * 1024,
+		StorageSlots: 32,
+		Seed:         1,
+	}
+}
+
+// LoadGenerator sends transactions from many vault accounts to L2 nodes at a fixed
+// rate, and records when they are included in L2 blocks, and when these blocks are
+// proposed and verified on L1.
+type LoadGenerator struct {
+	env     *TestEnv
+	spec    *LoadSpec
+	l2s     []*ELNode
+	clients []*ethclient.Client
+	tracker *BlockTracker
+
+	accounts []common.Address
+	// contract called by LoadStorage transactions
+	storage common.Address
+	kinds   []LoadKind
+
+	mu      sync.Mutex
+	records []*loadRecord
+	dropped int
+	sending time.Duration
+}
+
+type loadRecord struct {
+	kind      LoadKind
+	hash      common.Hash
+	submitted time.Time
+	err       error
+}
+
+// NewLoadGenerator creates the sending accounts and funds them on the first L2 node,
+// so the L2 chain must be proposed while it runs. Blocks of the first L2 node are
+// watched for the inclusion of the transactions.
+func (e *TestEnv) NewLoadGenerator(l1 *ELNode, l2s []*ELNode, spec *LoadSpec) *LoadGenerator {
+	t, ctx := e.T, e.Context
+	require.NotEmpty(t, l2s, "no L2 nodes")
+	require.Positive(t, spec.TPS, "invalid TPS")
+	require.Positive(t, spec.Accounts, "no accounts")
+	g := &LoadGenerator{env: e, spec: spec, l2s: l2s}
+	for _, n := range l2s {
+		cli, err := n.EthClient()
+		require.NoError(t, err)
+		g.clients = append(g.clients, cli)
+	}
+	for k, w := range spec.Mix {
+		for i := 0; i < w; i++ {
+			g.kinds = append(g.kinds, k)
+		}
+	}
+	if len(g.kinds) == 0 {
+		g.kinds = []LoadKind{LoadTransfer}
+	}
+	// map iteration is random, the order must only depend on the seed
+	sort.Slice(g.kinds, func(i, j int) bool { return g.kinds[i] < g.kinds[j] })
+	if len(spec.TxTypes) == 0 {
+		spec.TxTypes = []uint8{types.DynamicFeeTxType}
+	}
+
+	amounts := make([]*big.Int, spec.Accounts)
+	for i := range amounts {
+		g.accounts = append(g.accounts, e.L2Vault.GenerateKey())
+		amounts[i] = spec.Funding
+	}
+	require.NoError(t, e.L2Vault.Fund(ctx, g.clients[0], g.accounts, amounts), "can't fund load accounts")
+	if spec.Mix[LoadStorage] > 0 {
+		tx, err := e.L2Vault.SendTx(ctx, g.clients[0], types.DynamicFeeTxType, g.accounts[0], nil, nil, storageWriterCode)
+		require.NoError(t, err)
+		receipt, err := WaitReceiptOK(ctx, g.clients[0], tx.Hash())
+		require.NoError(t, err, "can't deploy storage contract")
+		g.storage = receipt.ContractAddress
+	}
+
+	tr, err := StartBlockTracker(ctx, l1, l2s[0])
+	require.NoError(t, err)
+	g.tracker = tr
+	return g
+}
+
+// Tracker returns the tracker of the L2 blocks created during the load.
+func (g *LoadGenerator) Tracker() *BlockTracker {
+	return g.tracker
+}
+
+// Close stops tracking the blocks.
+func (g *LoadGenerator) Close() error {
+	return g.tracker.Stop()
+}
+
+type loadJob struct {
+	kind LoadKind
+	typ  uint8
+	cli  *ethclient.Client
+}
+
+// Run sends transactions for the duration of the spec, and waits until all sent
+// transactions are included. Transactions which can't be sent are counted in the
+// summary. Every account sends its transactions in order, if an account is still busy
+// when its next transaction is due, the transaction is dropped.
+func (g *LoadGenerator) Run(ctx context.Context) error {
+	spec := g.spec
+	jobs := make([]chan loadJob, len(g.accounts))
+	var wg sync.WaitGroup
+	for i, addr := range g.accounts {
+		jobs[i] = make(chan loadJob, 1)
+		wg.Add(1)
+		go func(from common.Address, jobs <-chan loadJob, rng *rand.Rand) {
+			defer wg.Done()
+			for job := range jobs {
+				g.send(ctx, rng, from, job)
+			}
+		}(addr, jobs[i], rand.New(rand.NewSource(spec.Seed+int64(i))))
+	}
+
+	rng := rand.New(rand.NewSource(spec.Seed))
+	ticker := time.NewTicker(time.Second / time.Duration(spec.TPS))
+	start := time.Now()
+	deadline := time.NewTimer(spec.Duration)
+loop:
+	for i := 0; ; i++ {
+		job := loadJob{
+			kind: g.kinds[rng.Intn(len(g.kinds))],
+			typ:  spec.TxTypes[i%len(spec.TxTypes)],
+			cli:  g.clients[i%len(g.clients)],
+		}
+		select {
+		case jobs[i%len(jobs)] <- job:
+		default:
+			g.mu.Lock()
+			g.dropped++
+			g.mu.Unlock()
+		}
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			break loop
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	ticker.Stop()
+	for _, ch := range jobs {
+		close(ch)
+	}
+	wg.Wait()
+	g.mu.Lock()
+	g.sending = time.Since(start)
+	g.mu.Unlock()
+	return g.waitIncluded(ctx)
+}
+
+func (g *LoadGenerator) send(ctx context.Context, rng *rand.Rand, from common.Address, job loadJob) {
+	var (
+		to    *common.Address
+		value *big.Int
+		data  []byte
+	)
+	switch job.kind {
+	case LoadTransfer, LoadCalldata:
+		addr := common.BigToAddress(big.NewInt(rng.Int63()))
+		to, value = &addr, common.Big1
+		if job.kind == LoadCalldata {
+			data = make([]byte, g.spec.CalldataSize)
+			rng.Read(data)
+		}
+	case LoadDeploy:
+		data = storageWriterCode
+	case LoadStorage:
+		to = &g.storage
+		data = make([]byte, 32*g.spec.StorageSlots)
+		rng.Read(data)
+	}
+	r := &loadRecord{kind: job.kind, submitted: time.Now()}
+	tx, err := g.env.L2Vault.SendTx(ctx, job.cli, job.typ, from, to, value, data)
+	if err != nil {
+		r.err = err
+	} else {
+		r.hash = tx.Hash()
+		g.tracker.TrackTx(r.hash, r.submitted)
+	}
+	g.mu.Lock()
+	g.records = append(g.records, r)
+	g.mu.Unlock()
+}
+
+// sent returns the records of the transactions which were sent successfully.
+func (g *LoadGenerator) sent() []*loadRecord {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var sent []*loadRecord
+	for _, r := range g.records {
+		if r.err == nil {
+			sent = append(sent, r)
+		}
+	}
+	return sent
+}
+
+func (g *LoadGenerator) waitIncluded(ctx context.Context) error {
+	sent := g.sent()
+	return g.tracker.wait(ctx, func() (bool, string) {
+		missing := 0
+		for _, r := range sent {
+			if _, ok := g.tracker.txBlocks[r.hash]; !ok {
+				missing++
+			}
+		}
+		return missing == 0, fmt.Sprintf("%d of %d load transactions not included", missing, len(sent))
+	})
+}
+
+// WaitVerified waits until all L2 blocks containing load transactions are verified.
+func (g *LoadGenerator) WaitVerified(ctx context.Context) error {
+	sent := g.sent()
+	return g.tracker.wait(ctx, func() (bool, string) {
+		var last uint64
+		missing := 0
+		for hash := range g.blockHashes(sent) {
+			b := g.tracker.timing(g.tracker.blocks[hash])
+			if b.Number > last {
+				last = b.Number
+			}
+			if b.Verified.IsZero() {
+				missing++
+			}
+		}
+		return missing == 0, fmt.Sprintf("%d L2 blocks up to %d with load transactions not verified", missing, last)
+	})
+}
+
+// blockHashes returns the L2 blocks which include the transactions. It must be
+// called with the tracker lock held.
+func (g *LoadGenerator) blockHashes(records []*loadRecord) map[common.Hash]struct{} {
+	hashes := make(map[common.Hash]struct{})
+	for _, r := range records {
+		if hash, ok := g.tracker.txBlocks[r.hash]; ok {
+			hashes[hash] = struct{}{}
+		}
+	}
+	return hashes
+}
+
+// LoadSummary is the result of a load run. Latencies are measured from the submission
+// of a transaction.
+type LoadSummary struct {
+	Sent, Failed, Dropped, Included int
+	Kinds                           map[LoadKind]int
+	// Duration of the sending and the achieved submission rate.
+	Duration time.Duration
+	TPS      float64
+	// Number of L2 blocks with load transactions.
+	Blocks int
+
+	Inclusion    LatencyStats
+	Proposal     LatencyStats
+	Verification LatencyStats
+	// The first send errors.
+	Errors []string
+}
+
+// maxSummaryErrors is the number of send errors kept in the summary.
+const maxSummaryErrors = 5
+
+// Summary returns the result of the load, as observed so far.
+func (g *LoadGenerator) Summary() *LoadSummary {
+	g.mu.Lock()
+	records := append([]*loadRecord(nil), g.records...)
+	s := &LoadSummary{Dropped: g.dropped, Duration: g.sending, Kinds: make(map[LoadKind]int)}
+	g.mu.Unlock()
+	if s.Duration > 0 {
+		s.TPS = float64(len(records)) / s.Duration.Seconds()
+	}
+
+	var inclusion, proposal, verification []time.Duration
+	tr := g.tracker
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	for _, r := range records {
+		s.Kinds[r.kind]++
+		if r.err != nil {
+			s.Failed++
+			if len(s.Errors) < maxSummaryErrors {
+				s.Errors = append(s.Errors, r.err.Error())
+			}
+			continue
+		}
+		s.Sent++
+		hash, ok := tr.txBlocks[r.hash]
+		if !ok {
+			continue
+		}
+		s.Included++
+		b := tr.timing(tr.blocks[hash])
+		inclusion = append(inclusion, b.Included.Sub(r.submitted))
+		if !b.Proposed.IsZero() {
+			proposal = append(proposal, b.Proposed.Sub(r.submitted))
+		}
+		if !b.Verified.IsZero() {
+			verification = append(verification, b.Verified.Sub(r.submitted))
+		}
+	}
+	s.Blocks = len(g.blockHashes(records))
+	s.Inclusion = NewLatencyStats(inclusion)
+	s.Proposal = NewLatencyStats(proposal)
+	s.Verification = NewLatencyStats(verification)
+	return s
+}
+
+func (s *LoadSummary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "sent %d transactions in %v (%.1f tx/s), %d failed, %d dropped\n",
+		s.Sent, s.Duration.Round(time.Millisecond), s.TPS, s.Failed, s.Dropped)
+	kinds := make([]string, 0, len(s.Kinds))
+	for k, n := range s.Kinds {
+		kinds = append(kinds, fmt.Sprintf("%v=%d", k, n))
+	}
+	sort.Strings(kinds)
+	fmt.Fprintf(&b, "kinds: %s\n", strings.Join(kinds, " "))
+	fmt.Fprintf(&b, "included %d transactions in %d L2 blocks\n", s.Included, s.Blocks)
+	fmt.Fprintf(&b, "inclusion:    %v\n", s.Inclusion)
+	fmt.Fprintf(&b, "proposal:     %v\n", s.Proposal)
+	fmt.Fprintf(&b, "verification: %v\n", s.Verification)
+	for _, err := range s.Errors {
+		fmt.Fprintf(&b, "error: %s\n", err)
+	}
+	return b.String()
+}
@@ -1,6 +1,7 @@
 package taiko
 
 import (
+	"encoding/json"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -35,7 +36,11 @@ func NewNode(t *hivesim.T, c *hivesim.ClientDefinition, opts ...NodeOption) *Nod
 
 // NewL1ELNode starts a eth1 image and add it to the network
 func (e *TestEnv) NewL1ELNode(l2 *ELNode, opts ...NodeOption) *ELNode {
-	t, c, def := e.T, e.Conf, e.Clients.L1
+	return e.newL1ELNode(e.Clients.L1, l2, opts...)
+}
+
+func (e *TestEnv) newL1ELNode(def *hivesim.ClientDefinition, l2 *ELNode, opts ...NodeOption) *ELNode {
+	t, c := e.T, e.Conf
 	opts = append(opts,
 		WithRole("L1Engine"),
 		WithL1ChainID(c.L1.ChainID),
@@ -50,6 +55,25 @@ func (e *TestEnv) NewL1ELNode(l2 *ELNode, opts ...NodeOption) *ELNode {
 	return l1
 }
 
+// NewPlainL1ELNode starts an L1 node without taiko contracts, which is a plain eth1
+// client (preferably go-ethereum) with the chain config and clique signer of the
+// taiko-l1 image. The contracts are deployed with DeployProtocol.
+func (e *TestEnv) NewPlainL1ELNode(opts ...NodeOption) *ELNode {
+	t, c := e.T, e.Conf
+	require.NotNil(t, e.Clients.PlainL1, "plain L1 node needs an %s client, e.g. %s", plainL1, goEthereum)
+	genesis, err := json.Marshal(plainL1Genesis(c))
+	require.NoError(t, err)
+	opts = append(opts,
+		WithRole("L1Engine"),
+		WithNetworkID(c.L1.NetworkID),
+		WithLondonChain(c.L1.ChainID),
+		WithCliquePeriod(c.L1.CliquePeriod),
+		WithCliqueSigner(c.L1.Deployer),
+		WithGenesis(genesis),
+	)
+	return &ELNode{Node: NewNode(t, e.Clients.PlainL1, opts...)}
+}
+
 func (n *Node) getL1Deployments(t *hivesim.T) *deployResult {
 	query := func(key string) common.Address {
 		result, err := n.Exec("deploy_result.sh", key)
@@ -106,7 +130,8 @@ func (e *TestEnv) NewDriverNode(l1, l2 *ELNode, opts ...NodeOption) *Node {
 
 func (e *TestEnv) NewProposerNode(l1, l2 *ELNode, opts ...NodeOption) *Node {
 	t, c, def := e.T, e.Conf, e.Clients.Proposer
-	opts = append(opts,
+	// Options given by the caller come last, so they can override the defaults.
+	opts = append([]NodeOption{
 		WithRole("proposer"),
 		WithNoCheck(),
 		WithL1WSEndpoint(l1.WsRpcEndpoint()),
@@ -116,13 +141,14 @@ func (e *TestEnv) NewProposerNode(l1, l2 *ELNode, opts ...NodeOption) *Node {
 		WithProposerPrivateKey(c.L2.Proposer.PrivateKeyHex),
 		WithSuggestedFeeRecipient(c.L2.SuggestedFeeRecipient.Address),
 		WithProposeInterval(c.L2.ProposeInterval),
-	)
+	}, opts...)
 	return NewNode(t, def, opts...)
 }
 
 func (e *TestEnv) NewProverNode(l1, l2 *ELNode, opts ...NodeOption) *Node {
 	t, c, def := e.T, e.Conf, e.Clients.Prover
-	opts = append(opts,
+	// Options given by the caller come last, so they can override the defaults.
+	opts = append([]NodeOption{
 		WithRole("prover"),
 		WithNoCheck(),
 		WithL1HTTPEndpoint(l1.HttpRpcEndpoint()),
@@ -132,6 +158,26 @@ func (e *TestEnv) NewProverNode(l1, l2 *ELNode, opts ...NodeOption) *Node {
 		WithL1ContractAddress(l1.deploy.rollupAddress),
 		WithL2ContractAddress(l2.deploy.rollupAddress),
 		WithProverPrivateKey(c.L2.Prover.PrivateKeyHex),
-	)
+	}, opts...)
+	return NewNode(t, def, opts...)
+}
+
+// NewRelayerNode starts a bridge relayer between l1 and l2. The relayer uses a new
+// account, which is funded on both chains.
+func (e *TestEnv) NewRelayerNode(l1, l2 *ELNode, opts ...NodeOption) *Node {
+	t, def := e.T, e.Clients.Relayer
+	key := e.newL1Account(l1)
+	e.fundL2Account(l2, key)
+	opts = append([]NodeOption{
+		WithRole("relayer"),
+		WithNoCheck(),
+		WithL1WSEndpoint(l1.WsRpcEndpoint()),
+		WithL2WSEndpoint(l2.WsRpcEndpoint()),
+		WithL1ContractAddress(l1.deploy.rollupAddress),
+		WithL2ContractAddress(l2.deploy.rollupAddress),
+		WithBridgeAddresses(l1.deploy.bridgeAddress, l2.deploy.bridgeAddress),
+		WithVaultAddresses(l1.deploy.vaultAddress, l2.deploy.vaultAddress),
+		WithRelayerPrivateKey(key),
+	}, opts...)
 	return NewNode(t, def, opts...)
 }
@@ -1,6 +1,9 @@
 package taiko
 
 import (
+	"bytes"
+	"io"
+	"io/ioutil"
 	"math/big"
 	"strconv"
 	"time"
@@ -48,6 +51,41 @@ func WithCliquePeriod(seconds uint64) NodeOption {
 	}
 }
 
+// WithCliqueSigner makes an eth1 client seal clique blocks with the key of acc.
+func WithCliqueSigner(acc *Account) NodeOption {
+	return func(n *Node) {
+		n.opts = append(n.opts, hivesim.Params{
+			envCliquePrivateKey: acc.PrivateKeyHex,
+			envMiner:            acc.Address.Hex(),
+		})
+	}
+}
+
+// WithLondonChain sets the chain ID of an eth1 client, and activates all forks up to
+// London in the genesis block.
+func WithLondonChain(chainID *big.Int) NodeOption {
+	return func(n *Node) {
+		params := hivesim.Params{envChainID: chainID.String()}
+		for _, fork := range []string{
+			envForkHomestead, envForkTangerine, envForkSpurious, envForkByzantium,
+			envForkConstantinople, envForkPetersburg, envForkIstanbul, envForkMuirGlacier,
+			envForkBerlin, envForkLondon,
+		} {
+			params[fork] = "0"
+		}
+		n.opts = append(n.opts, params)
+	}
+}
+
+// WithGenesis adds the genesis.json file of an eth1 client.
+func WithGenesis(genesis []byte) NodeOption {
+	return func(n *Node) {
+		n.opts = append(n.opts, hivesim.WithDynamicFile("/genesis.json", func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(genesis)), nil
+		}))
+	}
+}
+
 func WithL1ChainID(chainID *big.Int) NodeOption {
 	return func(n *Node) {
 		n.opts = append(n.opts, hivesim.Params{
@@ -176,6 +214,16 @@ func WithProverPrivateKey(key string) NodeOption {
 	}
 }
 
+// WithRandomDummyProofDelay delays every dummy proof by a random duration between the
+// bounds of the given range, e.g. "1s-10s". Proofs are submitted out of order then.
+func WithRandomDummyProofDelay(bounds string) NodeOption {
+	return func(n *Node) {
+		n.opts = append(n.opts, hivesim.Params{
+			envTaikoProverRandomDummyProofDelay: bounds,
+		})
+	}
+}
+
 func WithPrivateKey(key string) NodeOption {
 	return func(n *Node) {
 		n.opts = append(n.opts, hivesim.Params{
@@ -215,3 +263,55 @@ func WithL2ChainID(chainID *big.Int) NodeOption {
 		})
 	}
 }
+
+func WithProtocolParams(params ProtocolParams) NodeOption {
+	return func(n *Node) {
+		n.opts = append(n.opts, hivesim.Params{
+			envTaikoProtocolParams: params.String(),
+		})
+	}
+}
+
+func WithBridgeAddresses(l1, l2 common.Address) NodeOption {
+	return func(n *Node) {
+		n.opts = append(n.opts, hivesim.Params{
+			envTaikoL1BridgeAddress: l1.Hex(),
+			envTaikoL2BridgeAddress: l2.Hex(),
+		})
+	}
+}
+
+func WithVaultAddresses(l1, l2 common.Address) NodeOption {
+	return func(n *Node) {
+		n.opts = append(n.opts, hivesim.Params{
+			envTaikoL1VaultAddress: l1.Hex(),
+			envTaikoL2VaultAddress: l2.Hex(),
+		})
+	}
+}
+
+func WithRelayerPrivateKey(key string) NodeOption {
+	return func(n *Node) {
+		n.opts = append(n.opts, hivesim.Params{
+			envTaikoRelayerPrivateKey: key,
+		})
+	}
+}
+
+func WithRelayerConfirmations(blocks uint64) NodeOption {
+	return func(n *Node) {
+		n.opts = append(n.opts, hivesim.Params{
+			envTaikoRelayerConfirmations: strconv.FormatUint(blocks, 10),
+		})
+	}
+}
+
+// WithDatadir starts an L2 execution node from the archive of a data directory
+// written by ELNode.SnapshotDatadir.
+func WithDatadir(path string) NodeOption {
+	return func(n *Node) {
+		n.opts = append(n.opts, hivesim.WithStaticFiles(map[string]string{
+			datadirArchive: path,
+		}))
+	}
+}
@@ -0,0 +1,93 @@
+package taiko
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/taikoxyz/taiko-client/bindings"
+	"github.com/taikoxyz/taiko-client/bindings/encoding"
+)
+
+// ProposedBlock is a block proposed to TaikoL1, together with its transaction list.
+type ProposedBlock struct {
+	Meta        bindings.TaikoDataBlockMetadata
+	TxListBytes []byte
+	// Log is the BlockProposed event, it locates the proposal on L1.
+	Log types.Log
+	// Txs is nil if the transaction list can't be decoded.
+	Txs types.Transactions
+}
+
+// GasLimit returns the sum of the gas limits of all transactions in the block.
+func (b *ProposedBlock) GasLimit() uint64 {
+	var gas uint64
+	for _, tx := range b.Txs {
+		gas += tx.Gas()
+	}
+	return gas
+}
+
+// ProposedBlocks returns the blocks proposed to TaikoL1 since the given L1 block.
+// The transaction lists are taken from the calldata of the proposing transactions.
+func ProposedBlocks(ctx context.Context, l1 *ELNode, start uint64) ([]*ProposedBlock, error) {
+	taikoL1, err := l1.TaikoL1Client()
+	if err != nil {
+		return nil, err
+	}
+	cli, err := l1.EthClient()
+	if err != nil {
+		return nil, err
+	}
+	iter, err := taikoL1.FilterBlockProposed(&bind.FilterOpts{Start: start, Context: ctx}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var blocks []*ProposedBlock
+	for iter.Next() {
+		tx, _, err := cli.TransactionByHash(ctx, iter.Event.Raw.TxHash)
+		if err != nil {
+			return nil, fmt.Errorf("can't get proposing transaction of block %d: %w", iter.Event.Id, err)
+		}
+		txListBytes, err := encoding.UnpackTxListBytes(tx.Data())
+		if err != nil {
+			return nil, fmt.Errorf("can't unpack transaction list of block %d: %w", iter.Event.Id, err)
+		}
+		b := &ProposedBlock{Meta: iter.Event.Meta, TxListBytes: txListBytes, Log: iter.Event.Raw}
+		if err := rlp.DecodeBytes(txListBytes, &b.Txs); err != nil {
+			b.Txs = nil
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, iter.Error()
+}
+
+// CheckTxListLimits verifies that a proposed block respects the limits of the protocol.
+func CheckTxListLimits(c *bindings.TaikoDataConfig, b *ProposedBlock) error {
+	id := b.Meta.Id
+	if b.Txs == nil {
+		return fmt.Errorf("block %d: invalid transaction list", id)
+	}
+	if n := uint64(len(b.Txs)); n > c.MaxTransactionsPerBlock.Uint64() {
+		return fmt.Errorf("block %d: %d transactions, limit %v", id, n, c.MaxTransactionsPerBlock)
+	}
+	if n := uint64(len(b.TxListBytes)); n > c.MaxBytesPerTxList.Uint64() {
+		return fmt.Errorf("block %d: transaction list has %d bytes, limit %v", id, n, c.MaxBytesPerTxList)
+	}
+	if gas := b.GasLimit(); gas > c.BlockMaxGasLimit.Uint64() {
+		return fmt.Errorf("block %d: transactions use %d gas, limit %v", id, gas, c.BlockMaxGasLimit)
+	}
+	if b.Meta.GasLimit > c.BlockMaxGasLimit.Uint64() {
+		return fmt.Errorf("block %d: gas limit %d, limit %v", id, b.Meta.GasLimit, c.BlockMaxGasLimit)
+	}
+	for _, tx := range b.Txs {
+		if tx.Gas() < c.MinTxGasLimit.Uint64() {
+			return fmt.Errorf("block %d: transaction %v has gas limit %d, minimum %v", id, tx.Hash(), tx.Gas(), c.MinTxGasLimit)
+		}
+	}
+	return nil
+}
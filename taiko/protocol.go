@@ -0,0 +1,156 @@
+package taiko
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+// ProtocolParams overrides fields of LibSharedConfig when the taiko-protocol client
+// deploys the L1 contracts. Keys are field names, values are Solidity expressions,
+// e.g. {"blockMaxGasLimit": "6000000", "proofTimeCap": "60 seconds"}.
+type ProtocolParams map[string]string
+
+// Commonly changed protocol parameters.
+const (
+	ParamBlockMaxGasLimit    = "blockMaxGasLimit"
+	ParamMaxBytesPerTxList   = "maxBytesPerTxList"
+	ParamCommitConfirmations = "commitConfirmations"
+	ParamProofTimeCap        = "proofTimeCap"
+	ParamMaxNumBlocks        = "maxNumBlocks"
+)
+
+func (p ProtocolParams) String() string {
+	list := make([]string, 0, len(p))
+	for k, v := range p {
+		list = append(list, k+"="+v)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
+
+// ProtocolDeployment is the result of deploying the L1 contracts, as returned by the
+// taiko-protocol client.
+type ProtocolDeployment struct {
+	Contracts map[string]common.Address `json:"contracts"`
+}
+
+// DeployProtocol deploys the L1 contracts with the given parameters to l1, using the
+// taiko-protocol client. l1 is a node without taiko contracts, see NewPlainL1ELNode.
+// It returns a view of l1 which uses the new contracts.
+//
+// The bridge of the L2 genesis is connected to the L1 bridge of the taiko-l1 image, so
+// the new L1 bridge and token vault can't exchange messages with L2.
+func (e *TestEnv) DeployProtocol(l1, l2 *ELNode, params ProtocolParams) *ELNode {
+	t, c := e.T, e.Conf
+	l2Cli, err := l2.EthClient()
+	require.NoError(t, err)
+	genesis, err := l2Cli.HeaderByNumber(e.Context, common.Big0)
+	require.NoError(t, err)
+
+	key := e.newL1Account(l1)
+	deployer, err := NewAccount(key)
+	require.NoError(t, err)
+	n := NewNode(t, e.Clients.Contract,
+		WithNoCheck(),
+		WithMainnetUrl(l1.HttpRpcEndpoint()),
+		WithPrivateKey(key),
+		WithL1DeployerAddress(deployer.Address),
+		WithL2ChainID(c.L2.ChainID),
+		WithL2GenesisBlockHash(genesis.Hash()),
+		WithL2ContractAddress(l2.deploy.rollupAddress),
+		WithProtocolParams(params),
+	)
+	defer t.Sim.StopClient(t.SuiteID, t.TestID, n.Container)
+
+	d, err := waitDeployment(e.Context, n)
+	require.NoError(t, err)
+	t.Logf("deployed L1 contracts with parameters %v: %v", params, d.Contracts)
+	return &ELNode{
+		Node:        l1.Node,
+		genesisHash: l1.genesisHash,
+		deploy: &deployResult{
+			rollupAddress:    d.Contracts["TaikoL1"],
+			bridgeAddress:    d.Contracts["Bridge"],
+			vaultAddress:     d.Contracts["TokenVault"],
+			testERC20Address: d.Contracts["TestERC20"],
+		},
+	}
+}
+
+// plainL1Genesis returns the genesis of an L1 chain without taiko contracts. Like the
+// chain of the taiko-l1 image, the deployer is the clique signer, and the L1 accounts
+// of the config are funded.
+func plainL1Genesis(c *Config) *core.Genesis {
+	signer := c.L1.Deployer.Address
+	extra := make([]byte, 32+common.AddressLength+crypto.SignatureLength)
+	copy(extra[32:], signer[:])
+	balance := new(big.Int).Mul(big.NewInt(1_000_000_000), big.NewInt(params.Ether))
+	alloc := make(core.GenesisAlloc)
+	for _, acc := range []*Account{c.L1.Deployer, c.L2.Proposer, c.L2.Prover} {
+		alloc[acc.Address] = core.GenesisAccount{Balance: balance}
+	}
+	return &core.Genesis{
+		Config: &params.ChainConfig{
+			ChainID:             c.L1.ChainID,
+			HomesteadBlock:      common.Big0,
+			EIP150Block:         common.Big0,
+			EIP155Block:         common.Big0,
+			EIP158Block:         common.Big0,
+			ByzantiumBlock:      common.Big0,
+			ConstantinopleBlock: common.Big0,
+			PetersburgBlock:     common.Big0,
+			IstanbulBlock:       common.Big0,
+			MuirGlacierBlock:    common.Big0,
+			BerlinBlock:         common.Big0,
+			LondonBlock:         common.Big0,
+			Clique:              &params.CliqueConfig{Period: c.L1.CliquePeriod, Epoch: 30000},
+		},
+		GasLimit:   10_000_000,
+		Difficulty: common.Big1,
+		ExtraData:  extra,
+		Alloc:      alloc,
+	}
+}
+
+// waitDeployment waits until the taiko-protocol client has deployed the contracts.
+func waitDeployment(ctx context.Context, n *Node) (*ProtocolDeployment, error) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		result, err := n.Exec("deploy_result.sh")
+		if err != nil {
+			return nil, err
+		}
+		switch result.ExitCode {
+		case 0:
+			d := new(ProtocolDeployment)
+			if err := json.Unmarshal([]byte(result.Stdout), d); err != nil {
+				return nil, fmt.Errorf("invalid deploy result: %w", err)
+			}
+			if d.Contracts["TaikoL1"] == (common.Address{}) {
+				return nil, fmt.Errorf("deploy result has no TaikoL1 contract")
+			}
+			return d, nil
+		case 1:
+			// still deploying
+		default:
+			return nil, fmt.Errorf("deployment failed: %s", result.Stdout)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("contracts not deployed: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
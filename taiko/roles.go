@@ -1,55 +1,158 @@
 package taiko
 
 import (
+	"fmt"
+	"sort"
+
 	"github.com/ethereum/hive/hivesim"
 	"github.com/taikoxyz/taiko-client/proposer"
 	"github.com/taikoxyz/taiko-client/prover"
 )
 
 const (
+	plainL1       = "eth1"
+	goEthereum    = "go-ethereum"
 	taikoL1       = "taiko-l1"
+	taikoL1Dev    = "taiko-l1-dev"
 	taikoDriver   = "taiko-driver"
 	taikoGeth     = "taiko-geth"
 	taikoProposer = "taiko-proposer"
 	taikoProver   = "taiko-prover"
 	taikoProtocol = "taiko-protocol"
+	taikoRelayer  = "taiko-relayer"
 )
 
-// ClientsByRole is a collection of ClientDefinitions, grouped by role.
+// ClientsByRole is the client definition used for every role by a test.
 type ClientsByRole struct {
 	L1       *hivesim.ClientDefinition
+	PlainL1  *hivesim.ClientDefinition
+	L1Dev    *hivesim.ClientDefinition
 	L2       *hivesim.ClientDefinition
 	Driver   *hivesim.ClientDefinition
 	Proposer *hivesim.ClientDefinition
 	Prover   *hivesim.ClientDefinition
 	Contract *hivesim.ClientDefinition
+	Relayer  *hivesim.ClientDefinition
+}
+
+// String names the combination by its L2 execution client, driver, proposer and prover.
+func (c *ClientsByRole) String() string {
+	return fmt.Sprintf("%s, %s, %s, %s", clientName(c.L2), clientName(c.Driver), clientName(c.Proposer), clientName(c.Prover))
 }
 
-func Roles(t *hivesim.T, clientDefs []*hivesim.ClientDefinition) *ClientsByRole {
-	var out ClientsByRole
-	for _, client := range clientDefs {
+func clientName(def *hivesim.ClientDefinition) string {
+	if def == nil {
+		return "none"
+	}
+	return def.Name
+}
+
+// ClientCandidates holds all client definitions available for every role, sorted
+// by name.
+type ClientCandidates struct {
+	L1       []*hivesim.ClientDefinition
+	PlainL1  []*hivesim.ClientDefinition
+	L1Dev    []*hivesim.ClientDefinition
+	L2       []*hivesim.ClientDefinition
+	Driver   []*hivesim.ClientDefinition
+	Proposer []*hivesim.ClientDefinition
+	Prover   []*hivesim.ClientDefinition
+	Contract []*hivesim.ClientDefinition
+	Relayer  []*hivesim.ClientDefinition
+}
+
+// Roles groups the client definitions by role. A client with several roles, like
+// taiko-client, is a candidate for each of them.
+func Roles(t *hivesim.T, clientDefs []*hivesim.ClientDefinition) *ClientCandidates {
+	defs := make([]*hivesim.ClientDefinition, len(clientDefs))
+	copy(defs, clientDefs)
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+
+	var out ClientCandidates
+	for _, client := range defs {
 		if client.HasRole(taikoL1) {
-			out.L1 = client
+			out.L1 = append(out.L1, client)
+		}
+		if client.HasRole(plainL1) {
+			out.PlainL1 = append(out.PlainL1, client)
+		}
+		if client.HasRole(taikoL1Dev) {
+			out.L1Dev = append(out.L1Dev, client)
 		}
 		if client.HasRole(taikoDriver) {
-			out.Driver = client
+			out.Driver = append(out.Driver, client)
 		}
 		if client.HasRole(taikoGeth) {
-			out.L2 = client
+			out.L2 = append(out.L2, client)
 		}
 		if client.HasRole(taikoProposer) {
-			out.Proposer = client
+			out.Proposer = append(out.Proposer, client)
 		}
 		if client.HasRole(taikoProver) {
-			out.Prover = client
+			out.Prover = append(out.Prover, client)
 		}
 		if client.HasRole(taikoProtocol) {
-			out.Contract = client
+			out.Contract = append(out.Contract, client)
+		}
+		if client.HasRole(taikoRelayer) {
+			out.Relayer = append(out.Relayer, client)
 		}
 	}
 	return &out
 }
 
+// Combinations returns every combination of the L2 execution clients, drivers,
+// proposers and provers. All other roles use their first candidate. There is always at
+// least one combination, roles without candidates are nil.
+func (c *ClientCandidates) Combinations() []*ClientsByRole {
+	var out []*ClientsByRole
+	for _, l2 := range orNone(c.L2) {
+		for _, driver := range orNone(c.Driver) {
+			for _, proposer := range orNone(c.Proposer) {
+				for _, prover := range orNone(c.Prover) {
+					out = append(out, &ClientsByRole{
+						L1:       first(c.L1),
+						PlainL1:  preferred(c.PlainL1, goEthereum),
+						L1Dev:    first(c.L1Dev),
+						L2:       l2,
+						Driver:   driver,
+						Proposer: proposer,
+						Prover:   prover,
+						Contract: first(c.Contract),
+						Relayer:  first(c.Relayer),
+					})
+				}
+			}
+		}
+	}
+	return out
+}
+
+// orNone returns the candidates, or a single nil candidate if there are none.
+func orNone(defs []*hivesim.ClientDefinition) []*hivesim.ClientDefinition {
+	if len(defs) == 0 {
+		return []*hivesim.ClientDefinition{nil}
+	}
+	return defs
+}
+
+func first(defs []*hivesim.ClientDefinition) *hivesim.ClientDefinition {
+	if len(defs) == 0 {
+		return nil
+	}
+	return defs[0]
+}
+
+// preferred returns the candidate with the given name, or the first candidate.
+func preferred(defs []*hivesim.ClientDefinition, name string) *hivesim.ClientDefinition {
+	for _, def := range defs {
+		if def.Name == name {
+			return def
+		}
+	}
+	return first(defs)
+}
+
 func NewProposerConfig(env *TestEnv, l1, l2 *ELNode) *proposer.Config {
 	return &proposer.Config{
 		L1Endpoint:              l1.WsRpcEndpoint(),
@@ -0,0 +1,85 @@
+package taiko
+
+import (
+	"testing"
+	"time"
+)
+
+func ms(values ...int) []time.Duration {
+	d := make([]time.Duration, len(values))
+	for i, v := range values {
+		d[i] = time.Duration(v) * time.Millisecond
+	}
+	return d
+}
+
+func TestNewLatencyStats(t *testing.T) {
+	tests := []struct {
+		name      string
+		latencies []time.Duration
+		want      LatencyStats
+	}{
+		{
+			name: "empty",
+			want: LatencyStats{},
+		},
+		{
+			name:      "single sample",
+			latencies: ms(5),
+			want: LatencyStats{
+				Count: 1,
+				Min:   5 * time.Millisecond, Max: 5 * time.Millisecond, Mean: 5 * time.Millisecond,
+				P50: 5 * time.Millisecond, P90: 5 * time.Millisecond, P99: 5 * time.Millisecond,
+			},
+		},
+		{
+			name:      "unsorted",
+			latencies: ms(7, 3, 10, 1, 5, 2, 9, 4, 8, 6),
+			want: LatencyStats{
+				Count: 10,
+				Min:   1 * time.Millisecond, Max: 10 * time.Millisecond, Mean: 5500 * time.Microsecond,
+				P50: 5 * time.Millisecond, P90: 9 * time.Millisecond, P99: 10 * time.Millisecond,
+			},
+		},
+	}
+	for _, test := range tests {
+		input := append([]time.Duration(nil), test.latencies...)
+		if got := NewLatencyStats(test.latencies); got != test.want {
+			t.Errorf("%s: wrong stats %+v, want %+v", test.name, got, test.want)
+		}
+		for i := range input {
+			if input[i] != test.latencies[i] {
+				t.Errorf("%s: input was modified", test.name)
+				break
+			}
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	hundred := make([]int, 100)
+	for i := range hundred {
+		hundred[i] = i + 1
+	}
+	tests := []struct {
+		sorted []time.Duration
+		p      int
+		want   time.Duration
+	}{
+		{ms(5), 0, 5 * time.Millisecond},
+		{ms(5), 50, 5 * time.Millisecond},
+		{ms(5), 99, 5 * time.Millisecond},
+		{ms(1, 2), 50, 1 * time.Millisecond},
+		{ms(1, 2), 51, 2 * time.Millisecond},
+		{ms(hundred...), 0, 1 * time.Millisecond},
+		{ms(hundred...), 50, 50 * time.Millisecond},
+		{ms(hundred...), 90, 90 * time.Millisecond},
+		{ms(hundred...), 99, 99 * time.Millisecond},
+		{ms(hundred...), 100, 100 * time.Millisecond},
+	}
+	for _, test := range tests {
+		if got := percentile(test.sorted, test.p); got != test.want {
+			t.Errorf("p%d of %d samples: got %v, want %v", test.p, len(test.sorted), got, test.want)
+		}
+	}
+}
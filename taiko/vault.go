@@ -18,6 +18,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/sync/errgroup"
 )
 
 var (
@@ -42,6 +43,11 @@ type Vault struct {
 	nonce uint64
 	// Created accounts are tracked in this map.
 	accounts map[common.Address]*ecdsa.PrivateKey
+	// Next nonces of the accounts in the vault. Accounts added with InsertKey get
+	// their nonce from the node when they send the first transaction.
+	nonces map[common.Address]uint64
+	// Type of the funding transactions.
+	txType uint8
 
 	mu sync.Mutex
 }
@@ -51,9 +57,20 @@ func NewVault(t *hivesim.T, chainID *big.Int) *Vault {
 		t:        t,
 		chainID:  chainID,
 		accounts: make(map[common.Address]*ecdsa.PrivateKey),
+		nonces:   make(map[common.Address]uint64),
+		txType:   types.DynamicFeeTxType,
 	}
 }
 
+// SetTxType sets the type of the funding transactions, e.g. types.AccessListTxType.
+// Funding transactions are EIP-1559 transactions by default, and legacy transactions
+// on chains without base fee.
+func (v *Vault) SetTxType(typ uint8) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.txType = typ
+}
+
 // GenerateKey creates a new account key and stores it.
 func (v *Vault) GenerateKey() common.Address {
 	key, err := crypto.GenerateKey()
@@ -65,6 +82,7 @@ func (v *Vault) GenerateKey() common.Address {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	v.accounts[addr] = key
+	v.nonces[addr] = 0
 	return addr
 }
 
@@ -95,8 +113,11 @@ func (v *Vault) CreateAccount(ctx context.Context, client *ethclient.Client, amo
 	address := v.GenerateKey()
 
 	// order the vault to send some ether
-	tx := v.makeFundingTx(address, amount, nil)
-	if err := client.SendTransaction(ctx, tx); err != nil {
+	tx, err := v.makeFundingTx(ctx, client, address, amount, nil)
+	if err != nil {
+		v.t.Fatalf("unable to create funding transaction: %v", err)
+	}
+	if err := v.sendFundingTx(ctx, client, tx); err != nil {
 		v.t.Fatalf("unable to send funding transaction: %v", err)
 	}
 
@@ -131,25 +152,22 @@ func (v *Vault) KeyedTransactor(addr common.Address) *bind.TransactOpts {
 	return opts
 }
 
-func (v *Vault) makeFundingTx(recipient common.Address, amount *big.Int, data []byte) *types.Transaction {
-	var (
-		nonce    = v.nextNonce()
-		gasLimit = uint64(750000)
-	)
-	tx := types.NewTx(&types.LegacyTx{
-		Nonce:    nonce,
-		Gas:      gasLimit,
-		GasPrice: big.NewInt(1),
-		To:       &recipient,
-		Value:    amount,
-		Data:     data,
-	})
-	signer := types.LatestSignerForChainID(v.chainID)
-	signedTx, err := types.SignTx(tx, signer, vaultKey)
+func (v *Vault) makeFundingTx(ctx context.Context, client *ethclient.Client, recipient common.Address, amount *big.Int, data []byte) (*types.Transaction, error) {
+	v.mu.Lock()
+	typ := v.txType
+	v.mu.Unlock()
+	from := crypto.PubkeyToAddress(vaultKey.PublicKey)
+	fees, err := estimateFees(ctx, client, typ, from, &recipient, amount, data)
+	if errors.Is(err, errNoBaseFee) {
+		typ = types.LegacyTxType
+		fees, err = estimateFees(ctx, client, typ, from, &recipient, amount, data)
+	}
 	if err != nil {
-		v.t.Fatal("can'T sign vault funding tx:", err)
+		return nil, fmt.Errorf("can't estimate fees of vault funding tx: %w", err)
 	}
-	return signedTx
+	tx := types.NewTx(fees.txData(typ, v.chainID, v.nextNonce(), &recipient, amount, data))
+	signer := types.LatestSignerForChainID(v.chainID)
+	return types.SignTx(tx, signer, vaultKey)
 }
 
 // nextNonce generates the nonce of a funding transaction.
@@ -162,11 +180,233 @@ func (v *Vault) nextNonce() uint64 {
 	return nonce
 }
 
+// sendFundingTx sends a transaction created by makeFundingTx. If sending fails, the
+// nonce of the vault account is read from the node again, so the nonce of the failed
+// transaction is not left as a gap.
+func (v *Vault) sendFundingTx(ctx context.Context, client *ethclient.Client, tx *types.Transaction) error {
+	err := client.SendTransaction(ctx, tx)
+	if err == nil {
+		return nil
+	}
+	from := crypto.PubkeyToAddress(vaultKey.PublicKey)
+	if pending, perr := client.PendingNonceAt(ctx, from); perr == nil {
+		v.mu.Lock()
+		v.nonce = pending
+		v.mu.Unlock()
+	}
+	return err
+}
+
 func (v *Vault) SendTestTx(ctx context.Context, client *ethclient.Client, data []byte) error {
 	address := v.GenerateKey()
-	tx := v.makeFundingTx(address, big.NewInt(params.Ether), data)
-	if err := client.SendTransaction(ctx, tx); err != nil {
+	tx, err := v.makeFundingTx(ctx, client, address, big.NewInt(params.Ether), data)
+	if err != nil {
+		return err
+	}
+	if err := v.sendFundingTx(ctx, client, tx); err != nil {
 		return fmt.Errorf("unable to send funding transaction: %v", err)
 	}
 	return nil
 }
+
+// FundAccount sends amount from the vault to an existing account, and waits for the
+// transaction to be included.
+func (v *Vault) FundAccount(ctx context.Context, client *ethclient.Client, addr common.Address, amount *big.Int) error {
+	tx, err := v.makeFundingTx(ctx, client, addr, amount, nil)
+	if err != nil {
+		return err
+	}
+	if err := v.sendFundingTx(ctx, client, tx); err != nil {
+		return fmt.Errorf("unable to send funding transaction: %v", err)
+	}
+	_, err = WaitReceiptOK(ctx, client, tx.Hash())
+	return err
+}
+
+// TransferERC20 sends tokens held by the vault account to addr, and waits for the
+// transaction to be included.
+func (v *Vault) TransferERC20(ctx context.Context, client *ethclient.Client, token, addr common.Address, amount *big.Int) error {
+	data, err := erc20ABI.Pack("transfer", addr, amount)
+	if err != nil {
+		return err
+	}
+	tx, err := v.makeFundingTx(ctx, client, token, new(big.Int), data)
+	if err != nil {
+		return err
+	}
+	if err := v.sendFundingTx(ctx, client, tx); err != nil {
+		return fmt.Errorf("unable to send token transfer transaction: %v", err)
+	}
+	_, err = WaitReceiptOK(ctx, client, tx.Hash())
+	return err
+}
+
+// MakeTx creates a transaction of the given type, sent by an account of the vault and
+// signed with its key. The nonce is tracked by the vault, the gas limit and fees are
+// estimated by the node. Transactions of the account which are not created by the
+// vault, e.g. with KeyedTransactor, make the tracked nonce stale.
+func (v *Vault) MakeTx(ctx context.Context, client *ethclient.Client, typ uint8, from common.Address, to *common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	key := v.FindKey(from)
+	if key == nil {
+		return nil, fmt.Errorf("sender account %v not in vault", from)
+	}
+	fees, err := estimateFees(ctx, client, typ, from, to, value, data)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := v.nextAccountNonce(ctx, client, from)
+	if err != nil {
+		return nil, err
+	}
+	tx := types.NewTx(fees.txData(typ, v.chainID, nonce, to, value, data))
+	return types.SignTx(tx, types.LatestSignerForChainID(v.chainID), key)
+}
+
+// SendTx creates a transaction with MakeTx and sends it. If sending fails, the nonce
+// of the account is read from the node again for the next transaction.
+func (v *Vault) SendTx(ctx context.Context, client *ethclient.Client, typ uint8, from common.Address, to *common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	tx, err := v.MakeTx(ctx, client, typ, from, to, value, data)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		v.mu.Lock()
+		delete(v.nonces, from)
+		v.mu.Unlock()
+		return nil, fmt.Errorf("unable to send transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// nextAccountNonce returns the nonce of the next transaction of an account in the vault.
+// The lock is not held while the nonce is read from the node. If another transaction
+// of the account got a nonce meanwhile, the nonce of the vault is used.
+func (v *Vault) nextAccountNonce(ctx context.Context, client *ethclient.Client, addr common.Address) (uint64, error) {
+	v.mu.Lock()
+	nonce, ok := v.nonces[addr]
+	if ok {
+		v.nonces[addr] = nonce + 1
+		v.mu.Unlock()
+		return nonce, nil
+	}
+	v.mu.Unlock()
+
+	pending, err := client.PendingNonceAt(ctx, addr)
+	if err != nil {
+		return 0, err
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if nonce, ok = v.nonces[addr]; !ok || pending > nonce {
+		nonce = pending
+	}
+	v.nonces[addr] = nonce + 1
+	return nonce, nil
+}
+
+// Fund sends amounts[i] from the vault to addrs[i], and waits until all transactions
+// are included.
+func (v *Vault) Fund(ctx context.Context, client *ethclient.Client, addrs []common.Address, amounts []*big.Int) error {
+	if len(addrs) != len(amounts) {
+		return fmt.Errorf("%d addresses, but %d amounts", len(addrs), len(amounts))
+	}
+	txs := make([]*types.Transaction, len(addrs))
+	for i, addr := range addrs {
+		tx, err := v.makeFundingTx(ctx, client, addr, amounts[i], nil)
+		if err != nil {
+			return err
+		}
+		if err := v.sendFundingTx(ctx, client, tx); err != nil {
+			return fmt.Errorf("unable to send funding transaction to %v: %v", addr, err)
+		}
+		txs[i] = tx
+	}
+	g, ctx := errgroup.WithContext(ctx)
+	for i := range txs {
+		tx, addr := txs[i], addrs[i]
+		g.Go(func() error {
+			if _, err := WaitReceiptOK(ctx, client, tx.Hash()); err != nil {
+				return fmt.Errorf("funding transaction %v to %v: %w", tx.Hash(), addr, err)
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// errNoBaseFee is returned for EIP-1559 transactions on chains without base fee.
+var errNoBaseFee = errors.New("chain has no base fee")
+
+// txFees are the gas limit and fees of a transaction.
+type txFees struct {
+	gas       uint64
+	gasPrice  *big.Int
+	gasTipCap *big.Int
+	gasFeeCap *big.Int
+}
+
+// estimateFees returns the gas limit and fees of a transaction, as estimated by the node.
+// The fee cap of EIP-1559 transactions allows the base fee to double.
+func estimateFees(ctx context.Context, client *ethclient.Client, typ uint8, from common.Address, to *common.Address, value *big.Int, data []byte) (*txFees, error) {
+	gas, err := client.EstimateGas(ctx, ethereum.CallMsg{From: from, To: to, Value: value, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	fees := &txFees{gas: gas}
+	switch typ {
+	case types.LegacyTxType, types.AccessListTxType:
+		if fees.gasPrice, err = client.SuggestGasPrice(ctx); err != nil {
+			return nil, err
+		}
+	case types.DynamicFeeTxType:
+		head, err := client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		if head.BaseFee == nil {
+			return nil, errNoBaseFee
+		}
+		if fees.gasTipCap, err = client.SuggestGasTipCap(ctx); err != nil {
+			return nil, err
+		}
+		fees.gasFeeCap = new(big.Int).Add(fees.gasTipCap, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %d", typ)
+	}
+	return fees, nil
+}
+
+func (f *txFees) txData(typ uint8, chainID *big.Int, nonce uint64, to *common.Address, value *big.Int, data []byte) types.TxData {
+	switch typ {
+	case types.AccessListTxType:
+		return &types.AccessListTx{
+			ChainID:  chainID,
+			Nonce:    nonce,
+			GasPrice: f.gasPrice,
+			Gas:      f.gas,
+			To:       to,
+			Value:    value,
+			Data:     data,
+		}
+	case types.DynamicFeeTxType:
+		return &types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			GasTipCap: f.gasTipCap,
+			GasFeeCap: f.gasFeeCap,
+			Gas:       f.gas,
+			To:        to,
+			Value:     value,
+			Data:      data,
+		}
+	default:
+		return &types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: f.gasPrice,
+			Gas:      f.gas,
+			To:       to,
+			Value:    value,
+			Data:     data,
+		}
+	}
+}
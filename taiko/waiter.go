@@ -0,0 +1,259 @@
+package taiko
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/taikoxyz/taiko-client/bindings"
+	"github.com/taikoxyz/taiko-client/pkg/rpc"
+)
+
+// The waiters in this file block until a condition holds on a node, or the context is
+// done. They are driven by websocket subscriptions instead of polling. When the context
+// ends first, the returned error wraps ctx.Err() and describes the last observed state,
+// so a test failing on a timeout shows how far the node got.
+
+// waitHeads calls check with the current head of n, and again on every new head, until
+// check reports done or ctx ends. check returns a description of the observed state,
+// which is used in the error on timeout.
+func waitHeads(ctx context.Context, n *ELNode, check func(*types.Header) (done bool, state string, err error)) error {
+	cli, err := n.EthClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+	ch := make(chan *types.Header, 16)
+	sub, err := cli.SubscribeNewHead(ctx, ch)
+	if err != nil {
+		return fmt.Errorf("%s: can't subscribe to new heads: %w", n.Container, err)
+	}
+	defer sub.Unsubscribe()
+
+	head, err := cli.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for {
+		done, state, err := check(head)
+		if err != nil || done {
+			return err
+		}
+		select {
+		case head = <-ch:
+		case err := <-sub.Err():
+			return fmt.Errorf("%s: head subscription failed, %s: %w", n.Container, state, err)
+		case <-ctx.Done():
+			return fmt.Errorf("%s: %s: %w", n.Container, state, ctx.Err())
+		}
+	}
+}
+
+// WaitHeight waits until the height of n satisfies f.
+func WaitHeight(ctx context.Context, n *ELNode, f func(uint64) bool) error {
+	return waitHeads(ctx, n, func(h *types.Header) (bool, string, error) {
+		return f(h.Number.Uint64()), fmt.Sprintf("last height %d", h.Number), nil
+	})
+}
+
+// SubscribeHeight waits until the number of a head of n satisfies f.
+func SubscribeHeight(ctx context.Context, n *ELNode, f func(*big.Int) bool) error {
+	return waitHeads(ctx, n, func(h *types.Header) (bool, string, error) {
+		return f(h.Number), fmt.Sprintf("last height %d", h.Number), nil
+	})
+}
+
+// WaitStateChange waits until the protocol state of the TaikoL1 contract on n satisfies
+// f. The state is checked again on every new L1 head.
+func WaitStateChange(ctx context.Context, n *ELNode, f func(*bindings.LibUtilsStateVariables) bool) error {
+	taikoL1, err := n.TaikoL1Client()
+	if err != nil {
+		return err
+	}
+	return waitHeads(ctx, n, func(h *types.Header) (bool, string, error) {
+		s, err := rpc.GetProtocolStateVariables(taikoL1, &bind.CallOpts{Context: ctx, BlockNumber: h.Number})
+		if err != nil {
+			return false, "", err
+		}
+		return f(s), fmt.Sprintf("protocol state at L1 block %d: %s", h.Number, FormatState(s)), nil
+	})
+}
+
+// WaitL1Origin waits until the L2 node n derived the block with the given TaikoL1 block
+// ID, and returns the L1 origin it recorded. It is checked again on every new L2 head.
+func WaitL1Origin(ctx context.Context, n *ELNode, id uint64) (*L1Origin, error) {
+	var origin *L1Origin
+	err := waitHeads(ctx, n, func(h *types.Header) (bool, string, error) {
+		var err error
+		origin, err = L1OriginByID(ctx, n, new(big.Int).SetUint64(id))
+		if err != nil && err.Error() != ethereum.NotFound.Error() {
+			return false, "", err
+		}
+		return origin != nil, fmt.Sprintf("no L1 origin of block %d at height %d", id, h.Number), nil
+	})
+	return origin, err
+}
+
+// FormatState returns a short description of the protocol state, for logs and errors.
+func FormatState(s *bindings.LibUtilsStateVariables) string {
+	return fmt.Sprintf("next block id %d, latest verified id %d, latest verified height %d",
+		s.NextBlockId, s.LatestVerifiedId, s.LatestVerifiedHeight)
+}
+
+// WaitBlockProposed waits until the L2 block with the given id is proposed to the
+// TaikoL1 contract on n, and returns the event.
+func WaitBlockProposed(ctx context.Context, n *ELNode, id uint64) (*bindings.TaikoL1ClientBlockProposed, error) {
+	taikoL1, err := n.TaikoL1Client()
+	if err != nil {
+		return nil, err
+	}
+	return waitEvent(ctx, n, "proposed", fmt.Sprint(id),
+		func(opts *bind.WatchOpts, ch chan *bindings.TaikoL1ClientBlockProposed) (event.Subscription, error) {
+			return taikoL1.WatchBlockProposed(opts, ch, nil)
+		},
+		func(opts *bind.FilterOpts) (*bindings.TaikoL1ClientBlockProposed, bool, error) {
+			iter, err := taikoL1.FilterBlockProposed(opts, []*big.Int{new(big.Int).SetUint64(id)})
+			if err != nil {
+				return nil, false, err
+			}
+			defer iter.Close()
+			if iter.Next() {
+				return iter.Event, true, nil
+			}
+			return nil, false, iter.Error()
+		},
+		func(e *bindings.TaikoL1ClientBlockProposed) bool { return e.Id.Uint64() == id },
+		func(e *bindings.TaikoL1ClientBlockProposed) *big.Int { return e.Id },
+	)
+}
+
+// WaitBlockProven waits until the L2 block with the given id is proven on the TaikoL1
+// contract on n, and returns the first proof event.
+func WaitBlockProven(ctx context.Context, n *ELNode, id uint64) (*bindings.TaikoL1ClientBlockProven, error) {
+	taikoL1, err := n.TaikoL1Client()
+	if err != nil {
+		return nil, err
+	}
+	return waitEvent(ctx, n, "proven", fmt.Sprint(id),
+		func(opts *bind.WatchOpts, ch chan *bindings.TaikoL1ClientBlockProven) (event.Subscription, error) {
+			return taikoL1.WatchBlockProven(opts, ch, nil)
+		},
+		func(opts *bind.FilterOpts) (*bindings.TaikoL1ClientBlockProven, bool, error) {
+			iter, err := taikoL1.FilterBlockProven(opts, []*big.Int{new(big.Int).SetUint64(id)})
+			if err != nil {
+				return nil, false, err
+			}
+			defer iter.Close()
+			if iter.Next() {
+				return iter.Event, true, nil
+			}
+			return nil, false, iter.Error()
+		},
+		func(e *bindings.TaikoL1ClientBlockProven) bool { return e.Id.Uint64() == id },
+		func(e *bindings.TaikoL1ClientBlockProven) *big.Int { return e.Id },
+	)
+}
+
+// WaitBlockVerified waits until the L2 block with the given id is verified on the
+// TaikoL1 contract on n, and returns the event.
+func WaitBlockVerified(ctx context.Context, n *ELNode, id uint64) (*bindings.TaikoL1ClientBlockVerified, error) {
+	taikoL1, err := n.TaikoL1Client()
+	if err != nil {
+		return nil, err
+	}
+	return waitEvent(ctx, n, "verified", fmt.Sprint(id),
+		func(opts *bind.WatchOpts, ch chan *bindings.TaikoL1ClientBlockVerified) (event.Subscription, error) {
+			return taikoL1.WatchBlockVerified(opts, ch, nil)
+		},
+		func(opts *bind.FilterOpts) (*bindings.TaikoL1ClientBlockVerified, bool, error) {
+			iter, err := taikoL1.FilterBlockVerified(opts, []*big.Int{new(big.Int).SetUint64(id)})
+			if err != nil {
+				return nil, false, err
+			}
+			defer iter.Close()
+			if iter.Next() {
+				return iter.Event, true, nil
+			}
+			return nil, false, iter.Error()
+		},
+		func(e *bindings.TaikoL1ClientBlockVerified) bool { return e.Id.Uint64() == id },
+		func(e *bindings.TaikoL1ClientBlockVerified) *big.Int { return e.Id },
+	)
+}
+
+// waitEvent waits for the TaikoL1 event of a block, which is selected by match. It
+// subscribes before looking at past events, so an event emitted in between is not
+// missed. The subscription is not filtered, which lets the timeout error report the id
+// of the last event seen.
+func waitEvent[E any](
+	ctx context.Context,
+	n *ELNode,
+	what string,
+	block string,
+	watch func(*bind.WatchOpts, chan E) (event.Subscription, error),
+	past func(*bind.FilterOpts) (E, bool, error),
+	match func(E) bool,
+	eventID func(E) *big.Int,
+) (E, error) {
+	var zero E
+	ch := make(chan E, 16)
+	sub, err := watch(&bind.WatchOpts{Context: ctx}, ch)
+	if err != nil {
+		return zero, fmt.Errorf("%s: can't subscribe to %s blocks: %w", n.Container, what, err)
+	}
+	defer sub.Unsubscribe()
+
+	if e, ok, err := past(&bind.FilterOpts{Start: 0, Context: ctx}); err != nil || ok {
+		return e, err
+	}
+	last := "none"
+	for {
+		select {
+		case e := <-ch:
+			if match(e) {
+				return e, nil
+			}
+			last = eventID(e).String()
+		case err := <-sub.Err():
+			return zero, fmt.Errorf("%s: subscription to %s blocks failed: %w", n.Container, what, err)
+		case <-ctx.Done():
+			return zero, fmt.Errorf("%s: block %s not %s, last %s block %s: %w", n.Container, block, what, what, last, ctx.Err())
+		}
+	}
+}
+
+// WaitProveEvent waits until the L2 block with the given hash is proven on the TaikoL1
+// contract on n.
+func WaitProveEvent(ctx context.Context, n *ELNode, hash common.Hash) error {
+	taikoL1, err := n.TaikoL1Client()
+	if err != nil {
+		return err
+	}
+	match := func(e *bindings.TaikoL1ClientBlockProven) bool { return e.BlockHash == hash }
+	_, err = waitEvent(ctx, n, "proven", hash.Hex(),
+		func(opts *bind.WatchOpts, ch chan *bindings.TaikoL1ClientBlockProven) (event.Subscription, error) {
+			return taikoL1.WatchBlockProven(opts, ch, nil)
+		},
+		func(opts *bind.FilterOpts) (*bindings.TaikoL1ClientBlockProven, bool, error) {
+			iter, err := taikoL1.FilterBlockProven(opts, nil)
+			if err != nil {
+				return nil, false, err
+			}
+			defer iter.Close()
+			for iter.Next() {
+				if match(iter.Event) {
+					return iter.Event, true, nil
+				}
+			}
+			return nil, false, iter.Error()
+		},
+		match,
+		func(e *bindings.TaikoL1ClientBlockProven) *big.Int { return e.Id },
+	)
+	return err
+}